@@ -2,60 +2,217 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
 	"fibonacci/config"
+	"fibonacci/internal/logging"
 	"fibonacci/internal/server"
 	"fibonacci/internal/service"
+	"fibonacci/internal/tracing"
 
-	"github.com/caarlos0/env"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 && os.Args[1] == "table" {
+		os.Exit(runTable(os.Args[2:]))
+	}
+
 	// Config setup
-	var cfg config.Config
-	if err := env.Parse(&cfg); err != nil {
+	cfg, err := config.Load()
+	if err != nil {
 		panic(err)
 	}
 
 	// Logs setup
-	logger := logrus.New()
-	level, err := logrus.ParseLevel(strings.ToLower(cfg.LogLevel))
-	if err != nil {
+	// The standard logger is the fallback of the service layer for calls that carry no request-scoped logger.
+	logger := logrus.StandardLogger()
+	if err := logging.Setup(logger, cfg.LogFormat, cfg.LogLevel); err != nil {
 		panic(err)
 	}
-	logger.SetLevel(level)
 
 	// Global context setup
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Tracing setup
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.TracingEndpoint,
+		Insecure:    cfg.TracingInsecure,
+		File:        cfg.TracingFile,
+		ServiceName: "fibonacci",
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logger.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		// The global context is done by now, so the pending spans get a moment of their own to be flushed.
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Errorf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	// Setup signal handling
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start Prometheus metrics server
-	startMetricsServer(ctx, cfg.MetricsPort, logger)
-
 	// Create Fibonacci service and gRPC server
-	fibService := service.NewService(cfg.MaxChunkSize, cfg.MinChunkSize, cfg.NLimit, cfg.StreamNLimit)
-	grpcServer := grpc.NewServer()
+	opts := []service.Option{
+		service.WithTokenSecret([]byte(cfg.ResumeTokenSecret)),
+		service.WithBatchLimits(cfg.BatchItemsLimit, cfg.BatchTermsLimit, cfg.BatchDigitsLimit),
+		service.WithByteBudgets(cfg.ResponseBytesLimit, cfg.StreamBytesLimit, cfg.ChunkBytesLimit),
+		service.WithJobs(ctx, service.JobConfig{
+			Dir:        cfg.JobsDir,
+			Workers:    cfg.JobWorkers,
+			QueueSize:  cfg.JobQueueSize,
+			NLimit:     cfg.JobNLimit,
+			BytesLimit: cfg.JobBytesLimit,
+			Retention:  cfg.JobRetention,
+		}),
+		service.WithCache(service.CacheConfig{
+			Interval:        cfg.CacheCheckpointInterval,
+			CheckpointBytes: cfg.CacheCheckpointBytes,
+			PrefixBytes:     cfg.CachePrefixBytes,
+		}),
+	}
+	if cfg.CoalesceRequests {
+		opts = append(opts, service.WithCoalescing(cfg.CoalesceStreamBacklog))
+	}
+	if cfg.TablePath != "" {
+		// The table stays mapped until the process exits, as streams may still read it while shutting down.
+		table, err := service.OpenTable(cfg.TablePath, cfg.TableMaxBytes)
+		if err != nil {
+			logger.Fatalf("Failed to load precomputed table: %v", err)
+		}
+
+		logger.Infof("Loaded precomputed table of %d terms from %s", table.Len(), cfg.TablePath)
+		opts = append(opts, service.WithTable(table))
+	}
+
+	fibService := service.NewService(cfg.MaxChunkSize, cfg.MinChunkSize, cfg.NLimit, cfg.StreamNLimit, cfg.NthDigitsLimit, opts...)
+	var (
+		// Tracing runs first, so that the later interceptors and the handlers see the trace of the caller.
+		serverOpts = []grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(server.TraceUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(server.TraceStreamInterceptor()),
+		}
+		gatewayOpts []server.GatewayOption
+		metricsTLS  *tls.Config
+	)
+	// Metrics and logging wrap the recovery, so that they see the calls that panicked, and the authentication
+	// and limits, so that they see the calls rejected by them.
+	if cfg.GRPCMetrics {
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(server.MetricsUnaryInterceptor()),
+			grpc.ChainStreamInterceptor(server.MetricsStreamInterceptor()),
+		)
+	}
+	if cfg.GRPCLogging {
+		sampler := logging.NewSampler(cfg.LogSampleFirst, cfg.LogSampleThereafter)
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(server.LoggingUnaryInterceptor(logger, sampler)),
+			grpc.ChainStreamInterceptor(server.LoggingStreamInterceptor(logger, sampler)),
+		)
+	}
+	if cfg.GRPCRecovery {
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(server.RecoveryUnaryInterceptor(logger)),
+			grpc.ChainStreamInterceptor(server.RecoveryStreamInterceptor(logger)),
+		)
+		gatewayOpts = append(gatewayOpts, server.WithGatewayRecovery())
+	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		grpcCerts := newCertReloader(ctx, server.TLSConfig{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.TLSClientCAFile,
+			MinVersion:   cfg.TLSMinVersion,
+		}, logger)
+		metricsCerts := newCertReloader(ctx, server.TLSConfig{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			ClientCAFile: cfg.MetricsTLSClientCAFile,
+			MinVersion:   cfg.TLSMinVersion,
+		}, logger)
+
+		logger.Infof("Serving TLS %s+ w/ %s (gRPC mTLS: %t, metrics mTLS: %t)",
+			cfg.TLSMinVersion, cfg.TLSCertFile, cfg.TLSClientCAFile != "", cfg.MetricsTLSClientCAFile != "")
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(grpcCerts.TLSConfig())))
+		metricsTLS = metricsCerts.TLSConfig()
+	}
+	if cfg.AuthConfig != "" {
+		authCfg, err := server.LoadAuth(cfg.AuthConfig)
+		if err != nil {
+			logger.Fatalf("Failed to load authentication config: %v", err)
+		}
+
+		auth, err := server.NewAuthenticator(authCfg, logger)
+		if err != nil {
+			logger.Fatalf("Invalid authentication config in %s: %v", cfg.AuthConfig, err)
+		}
+
+		logger.Infof("Authenticating calls w/ %d API keys (JWTs accepted: %t)", len(authCfg.APIKeys), authCfg.JWT != nil)
+		// Authentication runs first, so that the limits apply to the authenticated identity.
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(auth.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(auth.StreamInterceptor()),
+		)
+		gatewayOpts = append(gatewayOpts, server.WithGatewayAuth(auth))
+	} else {
+		logger.Warn("AUTH_CONFIG is not set, accepting anonymous calls")
+	}
+	if cfg.LimitsConfig != "" {
+		limits, err := server.LoadLimits(cfg.LimitsConfig)
+		if err != nil {
+			logger.Fatalf("Failed to load client limits: %v", err)
+		}
+
+		limiter, err := server.NewLimiter(limits)
+		if err != nil {
+			logger.Fatalf("Invalid client limits in %s: %v", cfg.LimitsConfig, err)
+		}
+
+		logger.Infof("Enforcing the limits of %d client tiers from %s", len(limits.Tiers), cfg.LimitsConfig)
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(limiter.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(limiter.StreamInterceptor()),
+		)
+		gatewayOpts = append(gatewayOpts, server.WithGatewayLimits(limiter))
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	fibServer := server.NewFibonacciServer(ctx, grpcServer, fibService, logger)
 	if fibServer == nil {
 		logger.Fatal("Failed to create Fibonacci server")
 	}
 
+	var healthOpts []server.HealthOption
+	if cfg.HealthSelfTest {
+		healthOpts = append(healthOpts, server.WithSelfTest(fibService))
+	}
+	health := server.NewHealth(grpcServer, logger, healthOpts...)
+	health.Watch(ctx, cfg.HealthSelfTestInterval)
+
+	// Start HTTP server with Prometheus metrics, the health checks and the JSON gateway
+	startHTTPServer(ctx, cfg.MetricsPort, metricsTLS, health, server.NewGateway(fibServer, gatewayOpts...), logger)
+
 	lis, err := net.Listen("tcp", ":"+cfg.AppPort)
 	if err != nil {
 		logger.Fatalf("Failed to listen on %s: :%v", cfg.AppPort, err)
@@ -84,6 +241,9 @@ ShutdownLoop:
 					logger.Info("Received SIGINT. Performing soft shutdown...")
 					shutdownInitiated = true
 
+					// Orchestrators stop routing calls here while the running ones finish.
+					health.Shutdown()
+
 					go func() {
 						grpcServer.GracefulStop()
 
@@ -95,6 +255,7 @@ ShutdownLoop:
 				}
 			case syscall.SIGTERM:
 				logger.Info("Received SIGTERM. Triggering shutdown...")
+				health.Shutdown()
 				cancel()
 			}
 		}
@@ -103,29 +264,57 @@ ShutdownLoop:
 	logger.Info("Exiting...")
 }
 
-func startMetricsServer(ctx context.Context, port string, logger *logrus.Logger) {
+// newCertReloader loads the TLS files of cfg and reloads them on change until ctx is done.
+func newCertReloader(ctx context.Context, cfg server.TLSConfig, logger *logrus.Logger) *server.CertReloader {
+	certs, err := server.NewCertReloader(cfg, logger)
+	if err != nil {
+		logger.Fatalf("Failed to load TLS files: %v", err)
+	}
+
+	if err := certs.Watch(ctx); err != nil {
+		logger.Fatalf("Failed to watch TLS files: %v", err)
+	}
+
+	return certs
+}
+
+// startHTTPServer serves the metrics, the health checks and the gateway, over TLS if tlsConfig is set.
+func startHTTPServer(ctx context.Context, port string, tlsConfig *tls.Config, health *server.Health, gateway *server.Gateway,
+	logger *logrus.Logger) {
 	router := mux.NewRouter()
 
 	router.Path("/metrics").Handler(promhttp.Handler())
+	health.Register(router)
+	gateway.Register(router)
 
 	s := &http.Server{
-		Addr:    ":" + port,
-		Handler: router,
+		Addr:      ":" + port,
+		Handler:   router,
+		TLSConfig: tlsConfig,
 	}
 
 	go func() {
-		logger.Infof("Starting metrics server at :%s/metrics", port)
-		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			logger.Errorf("Metrics server error: %v", err)
+		logger.Infof("Starting HTTP server at :%s (metrics at /metrics, health at /healthz and /readyz, API at /v1, TLS: %t)", port, tlsConfig != nil)
+
+		var err error
+		if tlsConfig != nil {
+			// The certificate comes from the TLS config, which reloads it.
+			err = s.ListenAndServeTLS("", "")
+		} else {
+			err = s.ListenAndServe()
+		}
+
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("HTTP server error: %v", err)
 		}
 	}()
 
 	go func() {
 		<-ctx.Done()
 		if err := s.Shutdown(ctx); err != nil {
-			logger.Errorf("Error shutting down metrics server: %v", err)
+			logger.Errorf("Error shutting down HTTP server: %v", err)
 		} else {
-			logger.Info("Metrics server stopped.")
+			logger.Info("HTTP server stopped.")
 		}
 	}()
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"fibonacci/internal/service"
+)
+
+const tableUsage = `Usage:
+  fibonacci table build -n <terms> <path>    Writes the table of F(0)..F(terms-1) to path
+  fibonacci table verify <path>              Checks the checksum and every term of the table at path
+`
+
+// runTable runs the table subcommand with its arguments and returns the exit code.
+func runTable(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, tableUsage)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	flags := flag.NewFlagSet("table "+args[0], flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, tableUsage) }
+
+	switch args[0] {
+	case "build":
+		n := flags.Int("n", 0, "number of terms")
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 1 {
+			flags.Usage()
+			return 2
+		}
+
+		path := flags.Arg(0)
+		if err := service.BuildTable(ctx, path, *n); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to build table: %v\n", err)
+			return 1
+		}
+
+		fmt.Printf("Wrote table of %d terms to %s\n", *n, path)
+
+	case "verify":
+		if err := flags.Parse(args[1:]); err != nil || flags.NArg() != 1 {
+			flags.Usage()
+			return 2
+		}
+
+		path := flags.Arg(0)
+
+		table, err := service.OpenTable(path, math.MaxInt64)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open table: %v\n", err)
+			return 1
+		}
+		defer table.Close()
+
+		if err := table.Verify(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to verify table: %v\n", err)
+			return 1
+		}
+
+		fmt.Printf("Table %s holds %d valid terms\n", path, table.Len())
+
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	return 0
+}
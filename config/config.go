@@ -1,13 +1,120 @@
 package config
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/caarlos0/env"
+)
+
 type Config struct {
 	MaxChunkSize int `env:"MAX_CHUNK_SIZE" envDefault:"100"`
 	MinChunkSize int `env:"MIN_CHUNK_SIZE"  envDefault:"5"`
 	NLimit       int `env:"N_LIMIT"  envDefault:"500"`
 	StreamNLimit int `env:"STREAM_N_LIMIT"  envDefault:"1000"`
 
+	// NthDigitsLimit bounds the decimal digits of a single term, both of FibonacciNth and of every term of a range,
+	// stream, batch or job w/o a modulus.
+	NthDigitsLimit int `env:"NTH_DIGITS_LIMIT" envDefault:"1000000"`
+
+	// Budgets for the estimated size of the terms returned by a call, a whole stream and a single chunk,
+	// one byte per decimal digit; 0 disables a budget. The response and chunk budgets stay below the default 4 MiB
+	// limit gRPC clients put on the messages they receive.
+	ResponseBytesLimit int64 `env:"RESPONSE_BYTES_LIMIT" envDefault:"4000000"`
+	StreamBytesLimit   int64 `env:"STREAM_BYTES_LIMIT" envDefault:"1073741824"`
+	ChunkBytesLimit    int64 `env:"CHUNK_BYTES_LIMIT" envDefault:"4000000"`
+
+	// ResumeTokenSecret signs continuation tokens. Replicas must share it to resume each other's streams;
+	// when empty a random secret is used and tokens are only valid until restart.
+	ResumeTokenSecret string `env:"RESUME_TOKEN_SECRET"`
+
+	BatchItemsLimit int `env:"BATCH_ITEMS_LIMIT" envDefault:"10000"`
+	BatchTermsLimit int `env:"BATCH_TERMS_LIMIT" envDefault:"1000000"`
+	// BatchDigitsLimit bounds the estimated digits a batch returns, counting the terms repeated by its items; 0 disables it.
+	BatchDigitsLimit int64 `env:"BATCH_DIGITS_LIMIT" envDefault:"67108864"`
+
+	JobsDir      string `env:"JOBS_DIR" envDefault:"jobs"`
+	JobWorkers   int    `env:"JOB_WORKERS" envDefault:"2"`
+	JobQueueSize int    `env:"JOB_QUEUE_SIZE" envDefault:"16"`
+	JobNLimit    int    `env:"JOB_N_LIMIT" envDefault:"10000000"`
+	// JobBytesLimit bounds the estimated size of the result file of a job, one byte per decimal digit; 0 disables it.
+	// Finished jobs are deleted after the retention; 0 keeps them forever.
+	JobBytesLimit int64         `env:"JOB_BYTES_LIMIT" envDefault:"1073741824"`
+	JobRetention  time.Duration `env:"JOB_RETENTION" envDefault:"24h"`
+
+	// Checkpoint cache: the state of every sequence is kept at multiples of the interval, and the longest
+	// prefix returned for each sequence, both evicting the least recently used entries beyond their size.
+	// An interval or prefix size of zero disables the respective cache.
+	CacheCheckpointInterval int   `env:"CACHE_CHECKPOINT_INTERVAL" envDefault:"1000"`
+	CacheCheckpointBytes    int64 `env:"CACHE_CHECKPOINT_BYTES" envDefault:"67108864"`
+	CachePrefixBytes        int64 `env:"CACHE_PREFIX_BYTES" envDefault:"67108864"`
+
+	// Identical concurrent calls share a single computation; streams keep up to the backlog of chunks
+	// for subscribers lagging behind the fastest one.
+	CoalesceRequests      bool `env:"COALESCE_REQUESTS" envDefault:"true"`
+	CoalesceStreamBacklog int  `env:"COALESCE_STREAM_BACKLOG" envDefault:"16"`
+
+	// Precomputed table of Fibonacci terms built with `fibonacci table build`, served instead of computing them.
+	// An empty path disables it; larger files are refused at startup.
+	TablePath     string `env:"TABLE_PATH"`
+	TableMaxBytes int64  `env:"TABLE_MAX_BYTES" envDefault:"1073741824"`
+
+	// YAML file with the accepted API key hashes and JWKS, see server.LoadAuth. An empty path accepts anonymous calls.
+	AuthConfig string `env:"AUTH_CONFIG"`
+
+	// YAML file with the rate limits and daily digit quotas of the client tiers, see server.LoadLimits.
+	// An empty path disables the limits.
+	LimitsConfig string `env:"LIMITS_CONFIG"`
+
+	// TLS for the gRPC and metrics listeners, enabled by setting the certificate and key files, which are
+	// reloaded when they change. Client CA bundles require clients to present a certificate issued by one of them.
+	TLSCertFile            string `env:"TLS_CERT_FILE"`
+	TLSKeyFile             string `env:"TLS_KEY_FILE"`
+	TLSClientCAFile        string `env:"TLS_CLIENT_CA_FILE"`
+	TLSMinVersion          string `env:"TLS_MIN_VERSION" envDefault:"1.2"`
+	MetricsTLSClientCAFile string `env:"METRICS_TLS_CLIENT_CA_FILE"`
+
+	// Standard gRPC interceptors: call metrics per method and status code, recovery turning panics into
+	// Internal errors, and a log line per completed call.
+	GRPCMetrics  bool `env:"GRPC_METRICS" envDefault:"true"`
+	GRPCRecovery bool `env:"GRPC_RECOVERY" envDefault:"true"`
+	GRPCLogging  bool `env:"GRPC_LOGGING" envDefault:"true"`
+
+	// OpenTelemetry tracing: "otlp" sends spans to a collector over gRPC, "file" appends them to a file as JSON lines
+	// and "none" only propagates the trace context of callers. The ratio applies to traces started by this service.
+	TracingExporter    string  `env:"TRACING_EXPORTER" envDefault:"none"`
+	TracingEndpoint    string  `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4317"`
+	TracingInsecure    bool    `env:"TRACING_OTLP_INSECURE" envDefault:"true"`
+	TracingFile        string  `env:"TRACING_FILE" envDefault:"traces.jsonl"`
+	TracingSampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+
+	// Health checks over grpc.health.v1 and at /healthz and /readyz. The self-test makes readiness depend on
+	// computing a known Fibonacci number, every interval.
+	HealthSelfTest         bool          `env:"HEALTH_SELF_TEST" envDefault:"false"`
+	HealthSelfTestInterval time.Duration `env:"HEALTH_SELF_TEST_INTERVAL" envDefault:"10s"`
+
 	AppPort     string `env:"APP_PORT" envDefault:"50051"`
 	MetricsPort string `env:"PORT" envDefault:"8080"`
 
-	LogLevel string `env:"PORT" envDefault:"info"`
+	// Logs are written as JSON, or as "text"; successful calls are sampled per method and second, logging the first ones
+	// and every thereafter-th one after them. A first of 0 logs every call.
+	LogLevel            string `env:"LOG_LEVEL" envDefault:"info"`
+	LogFormat           string `env:"LOG_FORMAT" envDefault:"json"`
+	LogSampleFirst      int    `env:"LOG_SAMPLE_FIRST" envDefault:"100"`
+	LogSampleThereafter int    `env:"LOG_SAMPLE_THEREAFTER" envDefault:"100"`
+}
+
+// Load reads the config from the environment and checks the values that would otherwise only fail later,
+// or not at all.
+func Load() (Config, error) {
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return Config{}, err
+	}
+
+	if cfg.HealthSelfTest && cfg.HealthSelfTestInterval <= 0 {
+		return Config{}, fmt.Errorf("HEALTH_SELF_TEST_INTERVAL must be positive, got %s", cfg.HealthSelfTestInterval)
+	}
+
+	return cfg, nil
 }
@@ -0,0 +1,43 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"fibonacci/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := config.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, 10*time.Second, cfg.HealthSelfTestInterval)
+	})
+
+	t.Run("self-test interval must be positive", func(t *testing.T) {
+		t.Setenv("HEALTH_SELF_TEST", "true")
+
+		for _, interval := range []string{"0s", "-1s"} {
+			t.Setenv("HEALTH_SELF_TEST_INTERVAL", interval)
+
+			_, err := config.Load()
+			assert.ErrorContains(t, err, "HEALTH_SELF_TEST_INTERVAL must be positive", interval)
+		}
+
+		t.Setenv("HEALTH_SELF_TEST_INTERVAL", "1s")
+
+		cfg, err := config.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, cfg.HealthSelfTestInterval)
+	})
+
+	t.Run("interval is unused w/o the self-test", func(t *testing.T) {
+		t.Setenv("HEALTH_SELF_TEST", "false")
+		t.Setenv("HEALTH_SELF_TEST_INTERVAL", "0s")
+
+		_, err := config.Load()
+		assert.NoError(t, err)
+	})
+}
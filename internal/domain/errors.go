@@ -4,7 +4,41 @@ import "errors"
 
 var (
 	ErrInvalidChunkSize = errors.New("invalid chunk size")
-	ErrNegativeN        = errors.New("n must be positive")
 	ErrTooLargeN        = errors.New("to large n")
+	ErrInvalidRange     = errors.New("invalid range")
+	ErrInvalidModulus   = errors.New("invalid modulus")
+	ErrInvalidSequence  = errors.New("invalid sequence")
+	ErrUndefinedIndex   = errors.New("sequence is undefined at negative indices")
+	ErrTooManyDigits    = errors.New("too many digits")
+	ErrTooLargeResponse = errors.New("response too large")
+	ErrInvalidToken     = errors.New("invalid resume token")
+	ErrInvalidCommand   = errors.New("invalid flow control command")
+	ErrInvalidBatchItem = errors.New("invalid batch item")
+	ErrTooLargeBatch    = errors.New("batch too large")
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobNotReady      = errors.New("job result is not available")
+	ErrJobQueueFull     = errors.New("job queue is full")
+	ErrJobsDisabled     = errors.New("jobs are disabled")
 	ErrContextCanceled  = errors.New("context canceled")
+	ErrInvalidTable     = errors.New("invalid precomputed table")
 )
+
+// FieldError is a validation error caused by a single request field.
+// It wraps one of the errors above, so errors.Is keeps working on it.
+type FieldError struct {
+	Field string // Name of the field in the API, e.g. "n" or "chunk_size"
+	Err   error  // Reason, including the limits the value violated
+}
+
+// NewFieldError returns err attributed to field.
+func NewFieldError(field string, err error) *FieldError {
+	return &FieldError{Field: field, Err: err}
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
@@ -1,7 +1,154 @@
 package domain
 
+import "time"
+
+// SequenceSpec selects the sequence to generate. The zero value selects Fibonacci.
+type SequenceSpec struct {
+	Name         string   // Registered sequence name, "kbonacci" or "custom"
+	K            int      // Order of a k-bonacci sequence
+	Seeds        []string // Initial terms of a custom recurrence
+	Coefficients []int64  // Coefficients c(1)..c(k) of a custom recurrence
+}
+
+// FibonacciRequest describes the range F(Start)..F(End-1), or a(Start)..a(End-1) of the selected sequence.
+// A non-zero Modulus reduces every value modulo Modulus.
+type FibonacciRequest struct {
+	Sequence SequenceSpec
+	Start    int
+	End      int
+	Modulus  uint64
+}
+
+// FibonacciStreamRequest describes the range F(Start)..F(End-1), or a(Start)..a(End-1) of the selected sequence, streamed in chunks.
+// A non-zero Modulus reduces every value modulo Modulus.
+// A non-empty ResumeToken continues an earlier stream right after the chunk that carried it;
+// the range, sequence and modulus are then taken from the token and only ChunkSize is used.
+// SendFunc receives each chunk.
 type FibonacciStreamRequest struct {
-	N         int
+	Sequence    SequenceSpec
+	Start       int
+	End         int
+	Modulus     uint64
+	ChunkSize   int
+	ResumeToken string
+	SendFunc    func(FibonacciChunk) error
+}
+
+// FibonacciChunk is a part of a streamed range.
+type FibonacciChunk struct {
+	Index             int      // Absolute index of the first value
+	Values            []string // Reused between sends, so it must not be retained
+	ContinuationToken string   // Resumes the stream after this chunk; empty for the last chunk
+}
+
+// FibonacciFlowRequest describes a stream whose chunks are only generated as Commands request them.
+// Stream selects the range and the initial chunk size like a FibonacciStreamRequest, and its SendFunc receives each chunk.
+// Closing Commands ends the stream once the chunks requested so far have been sent.
+type FibonacciFlowRequest struct {
+	Stream   FibonacciStreamRequest
+	Commands <-chan FlowCommand
+}
+
+// FlowCommandKind selects what a FlowCommand does.
+type FlowCommandKind int
+
+const (
+	FlowNext      FlowCommandKind = iota + 1 // Request Value more chunks
+	FlowChunkSize                            // Change the size of the following chunks to Value
+	FlowPause                                // Drop the requested chunks that have not been sent yet
+	FlowSeek                                 // Continue from the absolute index Value
+)
+
+// FlowCommand is a flow control message of a FibonacciFlowRequest.
+type FlowCommand struct {
+	Kind  FlowCommandKind
+	Value int
+}
+
+// FibonacciNthRequest describes the single term F(N), or a(N) of the selected sequence,
+// reduced modulo Modulus when it is not zero.
+type FibonacciNthRequest struct {
+	Sequence SequenceSpec
+	N        int
+	Modulus  uint64
+}
+
+// BatchItem is a request of a batch: either a range or a single term, so exactly one field is set.
+type BatchItem struct {
+	Range *FibonacciRequest
+	Nth   *FibonacciNthRequest
+}
+
+// BatchResult is the outcome of a BatchItem: the terms of a range, the term of an nth request,
+// or the error the item failed with.
+type BatchResult struct {
+	Values []string
+	Value  string
+	Err    error
+}
+
+// JobRequest describes the range a(Start)..a(End-1) of the selected sequence, computed in the background
+// for ranges that take longer than a call may last. A non-zero Modulus reduces every value modulo Modulus.
+// ChargeFunc, if set, receives the estimated number of decimal digits of the result once the job is queued.
+type JobRequest struct {
+	Sequence   SequenceSpec
+	Start      int
+	End        int
+	Modulus    uint64
+	ChargeFunc func(digits int64)
+}
+
+// JobState is the stage of a job's lifecycle.
+type JobState int
+
+const (
+	JobQueued    JobState = iota + 1 // Waiting for a worker
+	JobRunning                       // Being computed
+	JobSucceeded                     // Result is available
+	JobFailed                        // Stopped by an error
+	JobCanceled                      // Canceled by the client or the server shutting down
+)
+
+func (s JobState) String() string {
+	switch s {
+	case JobQueued:
+		return "queued"
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "succeeded"
+	case JobFailed:
+		return "failed"
+	case JobCanceled:
+		return "canceled"
+	default:
+		return "unknown"
+	}
+}
+
+// Finished reports whether the job has stopped for good.
+func (s JobState) Finished() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCanceled
+}
+
+// JobStatus reports the progress of a job.
+type JobStatus struct {
+	ID          string
+	State       JobState
+	Start       int
+	End         int
+	Computed    int           // Number of terms computed so far
+	ETA         time.Duration // Estimated time left while running, zero otherwise
+	Error       string        // Why the job failed or was canceled
+	SubmittedAt time.Time
+	StartedAt   time.Time // Zero until a worker picks the job up
+	FinishedAt  time.Time // Zero until the job has finished
+}
+
+// FetchJobResultRequest describes the result of a succeeded job, streamed in chunks.
+// SendFunc receives each chunk.
+type FetchJobResultRequest struct {
+	ID        string
 	ChunkSize int
-	SendFunc  func([]string, int) error
+	SendFunc  func(FibonacciChunk) error
 }
@@ -9,6 +9,9 @@ package api
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -20,17 +23,223 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type SequenceKind int32
+
+const (
+	SequenceKind_SEQUENCE_KIND_FIBONACCI  SequenceKind = 0
+	SequenceKind_SEQUENCE_KIND_LUCAS      SequenceKind = 1
+	SequenceKind_SEQUENCE_KIND_PELL       SequenceKind = 2
+	SequenceKind_SEQUENCE_KIND_TRIBONACCI SequenceKind = 3
+	SequenceKind_SEQUENCE_KIND_KBONACCI   SequenceKind = 4
+	SequenceKind_SEQUENCE_KIND_CUSTOM     SequenceKind = 5
+)
+
+// Enum value maps for SequenceKind.
+var (
+	SequenceKind_name = map[int32]string{
+		0: "SEQUENCE_KIND_FIBONACCI",
+		1: "SEQUENCE_KIND_LUCAS",
+		2: "SEQUENCE_KIND_PELL",
+		3: "SEQUENCE_KIND_TRIBONACCI",
+		4: "SEQUENCE_KIND_KBONACCI",
+		5: "SEQUENCE_KIND_CUSTOM",
+	}
+	SequenceKind_value = map[string]int32{
+		"SEQUENCE_KIND_FIBONACCI":  0,
+		"SEQUENCE_KIND_LUCAS":      1,
+		"SEQUENCE_KIND_PELL":       2,
+		"SEQUENCE_KIND_TRIBONACCI": 3,
+		"SEQUENCE_KIND_KBONACCI":   4,
+		"SEQUENCE_KIND_CUSTOM":     5,
+	}
+)
+
+func (x SequenceKind) Enum() *SequenceKind {
+	p := new(SequenceKind)
+	*p = x
+	return p
+}
+
+func (x SequenceKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SequenceKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_fibonacci_proto_enumTypes[0].Descriptor()
+}
+
+func (SequenceKind) Type() protoreflect.EnumType {
+	return &file_api_fibonacci_proto_enumTypes[0]
+}
+
+func (x SequenceKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SequenceKind.Descriptor instead.
+func (SequenceKind) EnumDescriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{0}
+}
+
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED JobState = 0
+	JobState_JOB_STATE_QUEUED      JobState = 1
+	JobState_JOB_STATE_RUNNING     JobState = 2
+	JobState_JOB_STATE_SUCCEEDED   JobState = 3
+	JobState_JOB_STATE_FAILED      JobState = 4
+	JobState_JOB_STATE_CANCELED    JobState = 5
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0: "JOB_STATE_UNSPECIFIED",
+		1: "JOB_STATE_QUEUED",
+		2: "JOB_STATE_RUNNING",
+		3: "JOB_STATE_SUCCEEDED",
+		4: "JOB_STATE_FAILED",
+		5: "JOB_STATE_CANCELED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED": 0,
+		"JOB_STATE_QUEUED":      1,
+		"JOB_STATE_RUNNING":     2,
+		"JOB_STATE_SUCCEEDED":   3,
+		"JOB_STATE_FAILED":      4,
+		"JOB_STATE_CANCELED":    5,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_fibonacci_proto_enumTypes[1].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_api_fibonacci_proto_enumTypes[1]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{1}
+}
+
+// Selects the linear recurrence a(n) = c(1)*a(n-1) + ... + c(k)*a(n-k) to generate.
+// The default is Fibonacci.
+type Sequence struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kind SequenceKind `protobuf:"varint,1,opt,name=kind,proto3,enum=api.SequenceKind" json:"kind,omitempty"`
+	// Name of a sequence registered on the server. Takes precedence over kind when set.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Order of a k-bonacci sequence.
+	K int32 `protobuf:"varint,3,opt,name=k,proto3" json:"k,omitempty"`
+	// Initial terms a(0)..a(k-1) of a custom recurrence.
+	Seeds []string `protobuf:"bytes,4,rep,name=seeds,proto3" json:"seeds,omitempty"`
+	// Coefficients c(1)..c(k) of a custom recurrence.
+	Coefficients []int64 `protobuf:"varint,5,rep,packed,name=coefficients,proto3" json:"coefficients,omitempty"`
+}
+
+func (x *Sequence) Reset() {
+	*x = Sequence{}
+	mi := &file_api_fibonacci_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Sequence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Sequence) ProtoMessage() {}
+
+func (x *Sequence) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Sequence.ProtoReflect.Descriptor instead.
+func (*Sequence) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Sequence) GetKind() SequenceKind {
+	if x != nil {
+		return x.Kind
+	}
+	return SequenceKind_SEQUENCE_KIND_FIBONACCI
+}
+
+func (x *Sequence) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Sequence) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+func (x *Sequence) GetSeeds() []string {
+	if x != nil {
+		return x.Seeds
+	}
+	return nil
+}
+
+func (x *Sequence) GetCoefficients() []int64 {
+	if x != nil {
+		return x.Coefficients
+	}
+	return nil
+}
+
+// Requests F(start)..F(end-1). When end is omitted, end = start + n.
+// Indices are signed and negative ones follow F(-n) = (-1)^(n+1) F(n).
+// A non-zero modulus reduces every value modulo it.
 type FibonacciRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	N int32 `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	N        int32     `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Start    int32     `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End      *int32    `protobuf:"varint,3,opt,name=end,proto3,oneof" json:"end,omitempty"`
+	Modulus  uint64    `protobuf:"varint,4,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	Sequence *Sequence `protobuf:"bytes,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
 }
 
 func (x *FibonacciRequest) Reset() {
 	*x = FibonacciRequest{}
-	mi := &file_api_fibonacci_proto_msgTypes[0]
+	mi := &file_api_fibonacci_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -42,7 +251,7 @@ func (x *FibonacciRequest) String() string {
 func (*FibonacciRequest) ProtoMessage() {}
 
 func (x *FibonacciRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_fibonacci_proto_msgTypes[0]
+	mi := &file_api_fibonacci_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -55,7 +264,7 @@ func (x *FibonacciRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FibonacciRequest.ProtoReflect.Descriptor instead.
 func (*FibonacciRequest) Descriptor() ([]byte, []int) {
-	return file_api_fibonacci_proto_rawDescGZIP(), []int{0}
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *FibonacciRequest) GetN() int32 {
@@ -65,6 +274,34 @@ func (x *FibonacciRequest) GetN() int32 {
 	return 0
 }
 
+func (x *FibonacciRequest) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *FibonacciRequest) GetEnd() int32 {
+	if x != nil && x.End != nil {
+		return *x.End
+	}
+	return 0
+}
+
+func (x *FibonacciRequest) GetModulus() uint64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+func (x *FibonacciRequest) GetSequence() *Sequence {
+	if x != nil {
+		return x.Sequence
+	}
+	return nil
+}
+
 type FibonacciResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -75,7 +312,7 @@ type FibonacciResponse struct {
 
 func (x *FibonacciResponse) Reset() {
 	*x = FibonacciResponse{}
-	mi := &file_api_fibonacci_proto_msgTypes[1]
+	mi := &file_api_fibonacci_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +324,7 @@ func (x *FibonacciResponse) String() string {
 func (*FibonacciResponse) ProtoMessage() {}
 
 func (x *FibonacciResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_fibonacci_proto_msgTypes[1]
+	mi := &file_api_fibonacci_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +337,7 @@ func (x *FibonacciResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FibonacciResponse.ProtoReflect.Descriptor instead.
 func (*FibonacciResponse) Descriptor() ([]byte, []int) {
-	return file_api_fibonacci_proto_rawDescGZIP(), []int{1}
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *FibonacciResponse) GetValues() []string {
@@ -110,18 +347,28 @@ func (x *FibonacciResponse) GetValues() []string {
 	return nil
 }
 
+// Streams F(start)..F(end-1), which may cross zero. When end is omitted, end = start + n.
+// A non-zero modulus reduces every value modulo it.
 type FibonacciStreamRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	N         int32 `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
-	ChunkSize int32 `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	N         int32     `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	ChunkSize int32     `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	Start     int32     `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End       *int32    `protobuf:"varint,4,opt,name=end,proto3,oneof" json:"end,omitempty"`
+	Modulus   uint64    `protobuf:"varint,5,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	Sequence  *Sequence `protobuf:"bytes,6,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// Continuation token of the last chunk received from an interrupted stream.
+	// The stream resumes right after that chunk, with the range, modulus and sequence of the
+	// original request; only chunk_size is taken from this request.
+	ResumeToken string `protobuf:"bytes,7,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
 }
 
 func (x *FibonacciStreamRequest) Reset() {
 	*x = FibonacciStreamRequest{}
-	mi := &file_api_fibonacci_proto_msgTypes[2]
+	mi := &file_api_fibonacci_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -133,7 +380,7 @@ func (x *FibonacciStreamRequest) String() string {
 func (*FibonacciStreamRequest) ProtoMessage() {}
 
 func (x *FibonacciStreamRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_fibonacci_proto_msgTypes[2]
+	mi := &file_api_fibonacci_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -146,7 +393,7 @@ func (x *FibonacciStreamRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FibonacciStreamRequest.ProtoReflect.Descriptor instead.
 func (*FibonacciStreamRequest) Descriptor() ([]byte, []int) {
-	return file_api_fibonacci_proto_rawDescGZIP(), []int{2}
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *FibonacciStreamRequest) GetN() int32 {
@@ -163,18 +410,56 @@ func (x *FibonacciStreamRequest) GetChunkSize() int32 {
 	return 0
 }
 
+func (x *FibonacciStreamRequest) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *FibonacciStreamRequest) GetEnd() int32 {
+	if x != nil && x.End != nil {
+		return *x.End
+	}
+	return 0
+}
+
+func (x *FibonacciStreamRequest) GetModulus() uint64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+func (x *FibonacciStreamRequest) GetSequence() *Sequence {
+	if x != nil {
+		return x.Sequence
+	}
+	return nil
+}
+
+func (x *FibonacciStreamRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
 type FibonacciChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Absolute position of the first value in the sequence.
 	Index  int32    `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
 	Values []string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	// Opaque token to resume the stream after this chunk. Empty on the last chunk.
+	ContinuationToken string `protobuf:"bytes,3,opt,name=continuation_token,json=continuationToken,proto3" json:"continuation_token,omitempty"`
 }
 
 func (x *FibonacciChunk) Reset() {
 	*x = FibonacciChunk{}
-	mi := &file_api_fibonacci_proto_msgTypes[3]
+	mi := &file_api_fibonacci_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -186,7 +471,7 @@ func (x *FibonacciChunk) String() string {
 func (*FibonacciChunk) ProtoMessage() {}
 
 func (x *FibonacciChunk) ProtoReflect() protoreflect.Message {
-	mi := &file_api_fibonacci_proto_msgTypes[3]
+	mi := &file_api_fibonacci_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -199,7 +484,7 @@ func (x *FibonacciChunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FibonacciChunk.ProtoReflect.Descriptor instead.
 func (*FibonacciChunk) Descriptor() ([]byte, []int) {
-	return file_api_fibonacci_proto_rawDescGZIP(), []int{3}
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *FibonacciChunk) GetIndex() int32 {
@@ -216,86 +501,1444 @@ func (x *FibonacciChunk) GetValues() []string {
 	return nil
 }
 
-var File_api_fibonacci_proto protoreflect.FileDescriptor
+func (x *FibonacciChunk) GetContinuationToken() string {
+	if x != nil {
+		return x.ContinuationToken
+	}
+	return ""
+}
 
-var file_api_fibonacci_proto_rawDesc = []byte{
-	0x0a, 0x13, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70, 0x69, 0x22, 0x20, 0x0a, 0x10, 0x46, 0x69,
-	0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c,
-	0x0a, 0x01, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6e, 0x22, 0x2b, 0x0a, 0x11,
-	0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x22, 0x45, 0x0a, 0x16, 0x46, 0x69, 0x62,
-	0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01,
-	0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65,
-	0x22, 0x3e, 0x0a, 0x0e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x43, 0x68, 0x75,
-	0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
-	0x32, 0x95, 0x01, 0x0a, 0x10, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x0f, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63,
-	0x63, 0x69, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46,
-	0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f,
-	0x6e, 0x61, 0x63, 0x63, 0x69, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x12, 0x3a, 0x0a, 0x09,
-	0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e,
-	0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x1b, 0x5a, 0x19, 0x66, 0x69, 0x62, 0x6f,
-	0x6e, 0x61, 0x63, 0x63, 0x69, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x61, 0x70,
-	0x69, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+// Controls a FibonacciFlow stream. The first message must be start; chunks are then only
+// generated as the client requests them with next. The stream ends after the last chunk of the range,
+// or once the requested chunks have been sent after the client closes its side.
+type FibonacciFlowRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Command:
+	//	*FibonacciFlowRequest_Start
+	//	*FibonacciFlowRequest_Next
+	//	*FibonacciFlowRequest_ChunkSize
+	//	*FibonacciFlowRequest_Pause
+	//	*FibonacciFlowRequest_Seek
+	Command isFibonacciFlowRequest_Command `protobuf_oneof:"command"`
 }
 
-var (
-	file_api_fibonacci_proto_rawDescOnce sync.Once
-	file_api_fibonacci_proto_rawDescData = file_api_fibonacci_proto_rawDesc
-)
+func (x *FibonacciFlowRequest) Reset() {
+	*x = FibonacciFlowRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func file_api_fibonacci_proto_rawDescGZIP() []byte {
-	file_api_fibonacci_proto_rawDescOnce.Do(func() {
-		file_api_fibonacci_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_fibonacci_proto_rawDescData)
-	})
-	return file_api_fibonacci_proto_rawDescData
+func (x *FibonacciFlowRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_api_fibonacci_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
-var file_api_fibonacci_proto_goTypes = []any{
-	(*FibonacciRequest)(nil),       // 0: api.FibonacciRequest
-	(*FibonacciResponse)(nil),      // 1: api.FibonacciResponse
-	(*FibonacciStreamRequest)(nil), // 2: api.FibonacciStreamRequest
-	(*FibonacciChunk)(nil),         // 3: api.FibonacciChunk
+func (*FibonacciFlowRequest) ProtoMessage() {}
+
+func (x *FibonacciFlowRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_api_fibonacci_proto_depIdxs = []int32{
-	2, // 0: api.FibonacciService.FibonacciStream:input_type -> api.FibonacciStreamRequest
-	0, // 1: api.FibonacciService.Fibonacci:input_type -> api.FibonacciRequest
-	3, // 2: api.FibonacciService.FibonacciStream:output_type -> api.FibonacciChunk
-	1, // 3: api.FibonacciService.Fibonacci:output_type -> api.FibonacciResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+// Deprecated: Use FibonacciFlowRequest.ProtoReflect.Descriptor instead.
+func (*FibonacciFlowRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{5}
 }
 
-func init() { file_api_fibonacci_proto_init() }
-func file_api_fibonacci_proto_init() {
-	if File_api_fibonacci_proto != nil {
-		return
+func (m *FibonacciFlowRequest) GetCommand() isFibonacciFlowRequest_Command {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (x *FibonacciFlowRequest) GetStart() *FibonacciStreamRequest {
+	if x, ok := x.GetCommand().(*FibonacciFlowRequest_Start); ok {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *FibonacciFlowRequest) GetNext() int32 {
+	if x, ok := x.GetCommand().(*FibonacciFlowRequest_Next); ok {
+		return x.Next
+	}
+	return 0
+}
+
+func (x *FibonacciFlowRequest) GetChunkSize() int32 {
+	if x, ok := x.GetCommand().(*FibonacciFlowRequest_ChunkSize); ok {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+func (x *FibonacciFlowRequest) GetPause() bool {
+	if x, ok := x.GetCommand().(*FibonacciFlowRequest_Pause); ok {
+		return x.Pause
+	}
+	return false
+}
+
+func (x *FibonacciFlowRequest) GetSeek() int32 {
+	if x, ok := x.GetCommand().(*FibonacciFlowRequest_Seek); ok {
+		return x.Seek
+	}
+	return 0
+}
+
+type isFibonacciFlowRequest_Command interface {
+	isFibonacciFlowRequest_Command()
+}
+
+type FibonacciFlowRequest_Start struct {
+	// Opens the stream like a FibonacciStream call, including resuming from a token. Nothing is sent until next.
+	Start *FibonacciStreamRequest `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type FibonacciFlowRequest_Next struct {
+	// Requests this many more chunks, on top of those requested but not sent yet.
+	Next int32 `protobuf:"varint,2,opt,name=next,proto3,oneof"`
+}
+
+type FibonacciFlowRequest_ChunkSize struct {
+	// Changes the size of the following chunks.
+	ChunkSize int32 `protobuf:"varint,3,opt,name=chunk_size,json=chunkSize,proto3,oneof"`
+}
+
+type FibonacciFlowRequest_Pause struct {
+	// Must be true; drops the requested chunks that have not been sent yet.
+	Pause bool `protobuf:"varint,4,opt,name=pause,proto3,oneof"`
+}
+
+type FibonacciFlowRequest_Seek struct {
+	// Moves to an absolute index within the range, where the following chunks start.
+	Seek int32 `protobuf:"varint,5,opt,name=seek,proto3,oneof"`
+}
+
+func (*FibonacciFlowRequest_Start) isFibonacciFlowRequest_Command() {}
+
+func (*FibonacciFlowRequest_Next) isFibonacciFlowRequest_Command() {}
+
+func (*FibonacciFlowRequest_ChunkSize) isFibonacciFlowRequest_Command() {}
+
+func (*FibonacciFlowRequest_Pause) isFibonacciFlowRequest_Command() {}
+
+func (*FibonacciFlowRequest_Seek) isFibonacciFlowRequest_Command() {}
+
+// Requests F(n) for any signed n. A non-zero modulus returns F(n) mod modulus, which lifts the digit limit on n.
+type FibonacciNthRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	N        int64     `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Modulus  uint64    `protobuf:"varint,2,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	Sequence *Sequence `protobuf:"bytes,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+}
+
+func (x *FibonacciNthRequest) Reset() {
+	*x = FibonacciNthRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FibonacciNthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FibonacciNthRequest) ProtoMessage() {}
+
+func (x *FibonacciNthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FibonacciNthRequest.ProtoReflect.Descriptor instead.
+func (*FibonacciNthRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FibonacciNthRequest) GetN() int64 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *FibonacciNthRequest) GetModulus() uint64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+func (x *FibonacciNthRequest) GetSequence() *Sequence {
+	if x != nil {
+		return x.Sequence
+	}
+	return nil
+}
+
+type FibonacciNthResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	N     int64  `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *FibonacciNthResponse) Reset() {
+	*x = FibonacciNthResponse{}
+	mi := &file_api_fibonacci_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FibonacciNthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FibonacciNthResponse) ProtoMessage() {}
+
+func (x *FibonacciNthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FibonacciNthResponse.ProtoReflect.Descriptor instead.
+func (*FibonacciNthResponse) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FibonacciNthResponse) GetN() int64 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *FibonacciNthResponse) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type PisanoPeriodRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Modulus uint64 `protobuf:"varint,1,opt,name=modulus,proto3" json:"modulus,omitempty"`
+}
+
+func (x *PisanoPeriodRequest) Reset() {
+	*x = PisanoPeriodRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PisanoPeriodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PisanoPeriodRequest) ProtoMessage() {}
+
+func (x *PisanoPeriodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PisanoPeriodRequest.ProtoReflect.Descriptor instead.
+func (*PisanoPeriodRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PisanoPeriodRequest) GetModulus() uint64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+type PisanoPeriodResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Modulus uint64 `protobuf:"varint,1,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	Period  uint64 `protobuf:"varint,2,opt,name=period,proto3" json:"period,omitempty"`
+}
+
+func (x *PisanoPeriodResponse) Reset() {
+	*x = PisanoPeriodResponse{}
+	mi := &file_api_fibonacci_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PisanoPeriodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PisanoPeriodResponse) ProtoMessage() {}
+
+func (x *PisanoPeriodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PisanoPeriodResponse.ProtoReflect.Descriptor instead.
+func (*PisanoPeriodResponse) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PisanoPeriodResponse) GetModulus() uint64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+func (x *PisanoPeriodResponse) GetPeriod() uint64 {
+	if x != nil {
+		return x.Period
+	}
+	return 0
+}
+
+// Answers many requests in one call. Requests on the same sequence and modulus share a single pass
+// over the sequence, so overlapping and nearby ranges are computed once.
+type FibonacciBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items []*BatchItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+}
+
+func (x *FibonacciBatchRequest) Reset() {
+	*x = FibonacciBatchRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FibonacciBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FibonacciBatchRequest) ProtoMessage() {}
+
+func (x *FibonacciBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FibonacciBatchRequest.ProtoReflect.Descriptor instead.
+func (*FibonacciBatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FibonacciBatchRequest) GetItems() []*BatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Chosen by the client to match the result to the item. Defaults to the position of the item.
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Types that are assignable to Request:
+	//	*BatchItem_Range
+	//	*BatchItem_Nth
+	Request isBatchItem_Request `protobuf_oneof:"request"`
+}
+
+func (x *BatchItem) Reset() {
+	*x = BatchItem{}
+	mi := &file_api_fibonacci_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItem) ProtoMessage() {}
+
+func (x *BatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItem.ProtoReflect.Descriptor instead.
+func (*BatchItem) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchItem) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (m *BatchItem) GetRequest() isBatchItem_Request {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+func (x *BatchItem) GetRange() *FibonacciRequest {
+	if x, ok := x.GetRequest().(*BatchItem_Range); ok {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *BatchItem) GetNth() *FibonacciNthRequest {
+	if x, ok := x.GetRequest().(*BatchItem_Nth); ok {
+		return x.Nth
+	}
+	return nil
+}
+
+type isBatchItem_Request interface {
+	isBatchItem_Request()
+}
+
+type BatchItem_Range struct {
+	Range *FibonacciRequest `protobuf:"bytes,2,opt,name=range,proto3,oneof"`
+}
+
+type BatchItem_Nth struct {
+	Nth *FibonacciNthRequest `protobuf:"bytes,3,opt,name=nth,proto3,oneof"`
+}
+
+func (*BatchItem_Range) isBatchItem_Request() {}
+
+func (*BatchItem_Nth) isBatchItem_Request() {}
+
+// Holds a result for every item, in the order of the items.
+type FibonacciBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*BatchResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *FibonacciBatchResponse) Reset() {
+	*x = FibonacciBatchResponse{}
+	mi := &file_api_fibonacci_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FibonacciBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FibonacciBatchResponse) ProtoMessage() {}
+
+func (x *FibonacciBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FibonacciBatchResponse.ProtoReflect.Descriptor instead.
+func (*FibonacciBatchResponse) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FibonacciBatchResponse) GetResults() []*BatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// Types that are assignable to Result:
+	//	*BatchResult_Range
+	//	*BatchResult_Nth
+	//	*BatchResult_Error
+	Result isBatchResult_Result `protobuf_oneof:"result"`
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_api_fibonacci_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchResult) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (m *BatchResult) GetResult() isBatchResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (x *BatchResult) GetRange() *FibonacciResponse {
+	if x, ok := x.GetResult().(*BatchResult_Range); ok {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *BatchResult) GetNth() *FibonacciNthResponse {
+	if x, ok := x.GetResult().(*BatchResult_Nth); ok {
+		return x.Nth
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() *BatchError {
+	if x, ok := x.GetResult().(*BatchResult_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+type isBatchResult_Result interface {
+	isBatchResult_Result()
+}
+
+type BatchResult_Range struct {
+	Range *FibonacciResponse `protobuf:"bytes,2,opt,name=range,proto3,oneof"`
+}
+
+type BatchResult_Nth struct {
+	Nth *FibonacciNthResponse `protobuf:"bytes,3,opt,name=nth,proto3,oneof"`
+}
+
+type BatchResult_Error struct {
+	// The error the equivalent single call would fail with. Other items are not affected.
+	Error *BatchError `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchResult_Range) isBatchResult_Result() {}
+
+func (*BatchResult_Nth) isBatchResult_Result() {}
+
+func (*BatchResult_Error) isBatchResult_Result() {}
+
+// Same layout as google.rpc.Status.
+type BatchError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// A google.rpc.Code value.
+	Code    int32        `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message string       `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Details []*anypb.Any `protobuf:"bytes,3,rep,name=details,proto3" json:"details,omitempty"`
+}
+
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	mi := &file_api_fibonacci_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *BatchError) GetDetails() []*anypb.Any {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+// Computes F(start)..F(end-1) in the background, for ranges too large for a single call.
+// When end is omitted, end = start + n. A non-zero modulus reduces every value modulo it.
+type SubmitJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	N        int32     `protobuf:"varint,1,opt,name=n,proto3" json:"n,omitempty"`
+	Start    int32     `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End      *int32    `protobuf:"varint,3,opt,name=end,proto3,oneof" json:"end,omitempty"`
+	Modulus  uint64    `protobuf:"varint,4,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	Sequence *Sequence `protobuf:"bytes,5,opt,name=sequence,proto3" json:"sequence,omitempty"`
+}
+
+func (x *SubmitJobRequest) Reset() {
+	*x = SubmitJobRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobRequest) ProtoMessage() {}
+
+func (x *SubmitJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobRequest.ProtoReflect.Descriptor instead.
+func (*SubmitJobRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SubmitJobRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *SubmitJobRequest) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *SubmitJobRequest) GetEnd() int32 {
+	if x != nil && x.End != nil {
+		return *x.End
+	}
+	return 0
+}
+
+func (x *SubmitJobRequest) GetModulus() uint64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+func (x *SubmitJobRequest) GetSequence() *Sequence {
+	if x != nil {
+		return x.Sequence
+	}
+	return nil
+}
+
+type SubmitJobResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *SubmitJobResponse) Reset() {
+	*x = SubmitJobResponse{}
+	mi := &file_api_fibonacci_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitJobResponse) ProtoMessage() {}
+
+func (x *SubmitJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitJobResponse.ProtoReflect.Descriptor instead.
+func (*SubmitJobResponse) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SubmitJobResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetJobStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetJobStatusRequest) Reset() {
+	*x = GetJobStatusRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusRequest) ProtoMessage() {}
+
+func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetJobStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetJobStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Cancels a queued or running job. Canceling a finished job does nothing.
+type CancelJobRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CancelJobRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type JobStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	State JobState `protobuf:"varint,2,opt,name=state,proto3,enum=api.JobState" json:"state,omitempty"`
+	Start int32    `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End   int32    `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`
+	// Number of terms computed so far.
+	Computed int64 `protobuf:"varint,5,opt,name=computed,proto3" json:"computed,omitempty"`
+	// Estimated time left while the job is running.
+	Eta *durationpb.Duration `protobuf:"bytes,6,opt,name=eta,proto3" json:"eta,omitempty"`
+	// Why the job failed or was canceled.
+	Error       string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	SubmittedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=submitted_at,json=submittedAt,proto3" json:"submitted_at,omitempty"`
+	StartedAt   *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt  *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+}
+
+func (x *JobStatus) Reset() {
+	*x = JobStatus{}
+	mi := &file_api_fibonacci_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatus) ProtoMessage() {}
+
+func (x *JobStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatus.ProtoReflect.Descriptor instead.
+func (*JobStatus) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *JobStatus) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *JobStatus) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *JobStatus) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *JobStatus) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+func (x *JobStatus) GetComputed() int64 {
+	if x != nil {
+		return x.Computed
+	}
+	return 0
+}
+
+func (x *JobStatus) GetEta() *durationpb.Duration {
+	if x != nil {
+		return x.Eta
+	}
+	return nil
+}
+
+func (x *JobStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobStatus) GetSubmittedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubmittedAt
+	}
+	return nil
+}
+
+func (x *JobStatus) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *JobStatus) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+// Streams the result of a succeeded job in chunks of chunk_size values.
+type FetchJobResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChunkSize int32  `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+}
+
+func (x *FetchJobResultRequest) Reset() {
+	*x = FetchJobResultRequest{}
+	mi := &file_api_fibonacci_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FetchJobResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchJobResultRequest) ProtoMessage() {}
+
+func (x *FetchJobResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_fibonacci_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchJobResultRequest.ProtoReflect.Descriptor instead.
+func (*FetchJobResultRequest) Descriptor() ([]byte, []int) {
+	return file_api_fibonacci_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *FetchJobResultRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FetchJobResultRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+var File_api_fibonacci_proto protoreflect.FileDescriptor
+
+var file_api_fibonacci_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x61, 0x70, 0x69, 0x2f, 0x66, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x03, 0x61, 0x70, 0x69, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8d, 0x01, 0x0a, 0x08, 0x53, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x11, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x4b, 0x69, 0x6e, 0x64, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x0c,
+	0x0a, 0x01, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6b, 0x12, 0x14, 0x0a, 0x05,
+	0x73, 0x65, 0x65, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x73, 0x65, 0x65,
+	0x64, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x65, 0x66, 0x66, 0x69, 0x63, 0x69, 0x65, 0x6e,
+	0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x6f, 0x65, 0x66, 0x66, 0x69,
+	0x63, 0x69, 0x65, 0x6e, 0x74, 0x73, 0x22, 0x9a, 0x01, 0x0a, 0x10, 0x46, 0x69, 0x62, 0x6f, 0x6e,
+	0x61, 0x63, 0x63, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12,
+	0x15, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x03,
+	0x65, 0x6e, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73,
+	0x12, 0x29, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f,
+	0x65, 0x6e, 0x64, 0x22, 0x2b, 0x0a, 0x11, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x22, 0xe2, 0x01, 0x0a, 0x16, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x15,
+	0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x03, 0x65,
+	0x6e, 0x64, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x12,
+	0x29, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65,
+	0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65,
+	0x73, 0x75, 0x6d, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0b, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x06, 0x0a,
+	0x04, 0x5f, 0x65, 0x6e, 0x64, 0x22, 0x6d, 0x0a, 0x0e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63,
+	0x63, 0x69, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x16, 0x0a,
+	0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x2d, 0x0a, 0x12, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x11, 0x63, 0x6f, 0x6e, 0x74, 0x69, 0x6e, 0x75, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xbb, 0x01, 0x0a, 0x14, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63,
+	0x63, 0x69, 0x46, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a,
+	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x12, 0x14, 0x0a, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x00, 0x52, 0x04, 0x6e, 0x65, 0x78, 0x74, 0x12, 0x1f, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x09,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x05, 0x70, 0x61, 0x75,
+	0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x05, 0x70, 0x61, 0x75, 0x73,
+	0x65, 0x12, 0x14, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x00, 0x52, 0x04, 0x73, 0x65, 0x65, 0x6b, 0x42, 0x09, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x22, 0x68, 0x0a, 0x13, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x4e,
+	0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x01, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c,
+	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75,
+	0x73, 0x12, 0x29, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x22, 0x3a, 0x0a, 0x14,
+	0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x4e, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x01, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x2f, 0x0a, 0x13, 0x50, 0x69, 0x73, 0x61,
+	0x6e, 0x6f, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x22, 0x48, 0x0a, 0x14, 0x50, 0x69, 0x73,
+	0x61, 0x6e, 0x6f, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x70,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x70, 0x65, 0x72,
+	0x69, 0x6f, 0x64, 0x22, 0x3d, 0x0a, 0x15, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65,
+	0x6d, 0x73, 0x22, 0x85, 0x01, 0x0a, 0x09, 0x42, 0x61, 0x74, 0x63, 0x68, 0x49, 0x74, 0x65, 0x6d,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x2d, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63,
+	0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67,
+	0x65, 0x12, 0x2c, 0x0a, 0x03, 0x6e, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x4e, 0x74,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x74, 0x68, 0x42,
+	0x09, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x44, 0x0a, 0x16, 0x46, 0x69,
+	0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73,
+	0x22, 0xb1, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x2e, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63,
+	0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x05, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x2d, 0x0a, 0x03, 0x6e, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x4e,
+	0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x6e, 0x74,
+	0x68, 0x12, 0x27, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x48, 0x00, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x42, 0x08, 0x0a, 0x06, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x22, 0x6a, 0x0a, 0x0a, 0x42, 0x61, 0x74, 0x63, 0x68, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x2e, 0x0a, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79, 0x52, 0x07, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x73,
+	0x22, 0x9a, 0x01, 0x0a, 0x10, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x01, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x15, 0x0a, 0x03, 0x65, 0x6e, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x75, 0x6c, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x08, 0x73, 0x65,
+	0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x53, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x73, 0x65, 0x71,
+	0x75, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x65, 0x6e, 0x64, 0x22, 0x23, 0x0a,
+	0x11, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x25, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x22, 0x0a, 0x10, 0x43, 0x61, 0x6e,
+	0x63, 0x65, 0x6c, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0xfe, 0x02,
+	0x0a, 0x09, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x23, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0d, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70,
+	0x75, 0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70,
+	0x75, 0x74, 0x65, 0x64, 0x12, 0x2b, 0x0a, 0x03, 0x65, 0x74, 0x61, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x03, 0x65, 0x74,
+	0x61, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x3d, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x73, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x39, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65,
+	0x64, 0x5f, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41,
+	0x74, 0x12, 0x3b, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x5f, 0x61, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0a, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x65, 0x64, 0x41, 0x74, 0x22, 0x46,
+	0x0a, 0x15, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x53, 0x69, 0x7a, 0x65, 0x2a, 0xb0, 0x01, 0x0a, 0x0c, 0x53, 0x65, 0x71, 0x75, 0x65,
+	0x6e, 0x63, 0x65, 0x4b, 0x69, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x45, 0x51, 0x55, 0x45,
+	0x4e, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x46, 0x49, 0x42, 0x4f, 0x4e, 0x41, 0x43,
+	0x43, 0x49, 0x10, 0x00, 0x12, 0x17, 0x0a, 0x13, 0x53, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x45,
+	0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4c, 0x55, 0x43, 0x41, 0x53, 0x10, 0x01, 0x12, 0x16, 0x0a,
+	0x12, 0x53, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x50,
+	0x45, 0x4c, 0x4c, 0x10, 0x02, 0x12, 0x1c, 0x0a, 0x18, 0x53, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43,
+	0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x54, 0x52, 0x49, 0x42, 0x4f, 0x4e, 0x41, 0x43, 0x43,
+	0x49, 0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x53, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x45, 0x5f,
+	0x4b, 0x49, 0x4e, 0x44, 0x5f, 0x4b, 0x42, 0x4f, 0x4e, 0x41, 0x43, 0x43, 0x49, 0x10, 0x04, 0x12,
+	0x18, 0x0a, 0x14, 0x53, 0x45, 0x51, 0x55, 0x45, 0x4e, 0x43, 0x45, 0x5f, 0x4b, 0x49, 0x4e, 0x44,
+	0x5f, 0x43, 0x55, 0x53, 0x54, 0x4f, 0x4d, 0x10, 0x05, 0x2a, 0x99, 0x01, 0x0a, 0x08, 0x4a, 0x6f,
+	0x62, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x15, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x00, 0x12, 0x14, 0x0a, 0x10, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x51,
+	0x55, 0x45, 0x55, 0x45, 0x44, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x4a, 0x4f, 0x42, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x17,
+	0x0a, 0x13, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x53, 0x55, 0x43, 0x43,
+	0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x4a, 0x4f, 0x42, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x04, 0x12, 0x16, 0x0a,
+	0x12, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45,
+	0x4c, 0x45, 0x44, 0x10, 0x05, 0x32, 0x9e, 0x05, 0x0a, 0x10, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61,
+	0x63, 0x63, 0x69, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x0f, 0x46, 0x69,
+	0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x30,
+	0x01, 0x12, 0x43, 0x0a, 0x0d, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x46, 0x6c,
+	0x6f, 0x77, 0x12, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63,
+	0x63, 0x69, 0x46, 0x6c, 0x6f, 0x77, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x28, 0x01, 0x30, 0x01, 0x12, 0x3a, 0x0a, 0x09, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61,
+	0x63, 0x63, 0x69, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61,
+	0x63, 0x63, 0x69, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x4e,
+	0x74, 0x68, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63,
+	0x63, 0x69, 0x4e, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x4e, 0x74, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x0c, 0x50, 0x69, 0x73, 0x61, 0x6e,
+	0x6f, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x69,
+	0x73, 0x61, 0x6e, 0x6f, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x50, 0x69, 0x73, 0x61, 0x6e, 0x6f, 0x50, 0x65,
+	0x72, 0x69, 0x6f, 0x64, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0e,
+	0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1a,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3a, 0x0a, 0x09, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x4a, 0x6f, 0x62, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69,
+	0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x75, 0x62, 0x6d, 0x69, 0x74, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x4a, 0x6f, 0x62,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x32, 0x0a,
+	0x09, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x6f, 0x62, 0x12, 0x15, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x43, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x0e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x4a, 0x6f, 0x62, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x43, 0x0a, 0x0e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x12, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a,
+	0x6f, 0x62, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x13, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x46, 0x69, 0x62, 0x6f, 0x6e, 0x61, 0x63, 0x63, 0x69, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x30, 0x01, 0x42, 0x1b, 0x5a, 0x19, 0x66, 0x69, 0x62, 0x6f, 0x6e, 0x61,
+	0x63, 0x63, 0x69, 0x2d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x3b,
+	0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_fibonacci_proto_rawDescOnce sync.Once
+	file_api_fibonacci_proto_rawDescData = file_api_fibonacci_proto_rawDesc
+)
+
+func file_api_fibonacci_proto_rawDescGZIP() []byte {
+	file_api_fibonacci_proto_rawDescOnce.Do(func() {
+		file_api_fibonacci_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_fibonacci_proto_rawDescData)
+	})
+	return file_api_fibonacci_proto_rawDescData
+}
+
+var file_api_fibonacci_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_api_fibonacci_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_api_fibonacci_proto_goTypes = []any{
+	(SequenceKind)(0),              // 0: api.SequenceKind
+	(JobState)(0),                  // 1: api.JobState
+	(*Sequence)(nil),               // 2: api.Sequence
+	(*FibonacciRequest)(nil),       // 3: api.FibonacciRequest
+	(*FibonacciResponse)(nil),      // 4: api.FibonacciResponse
+	(*FibonacciStreamRequest)(nil), // 5: api.FibonacciStreamRequest
+	(*FibonacciChunk)(nil),         // 6: api.FibonacciChunk
+	(*FibonacciFlowRequest)(nil),   // 7: api.FibonacciFlowRequest
+	(*FibonacciNthRequest)(nil),    // 8: api.FibonacciNthRequest
+	(*FibonacciNthResponse)(nil),   // 9: api.FibonacciNthResponse
+	(*PisanoPeriodRequest)(nil),    // 10: api.PisanoPeriodRequest
+	(*PisanoPeriodResponse)(nil),   // 11: api.PisanoPeriodResponse
+	(*FibonacciBatchRequest)(nil),  // 12: api.FibonacciBatchRequest
+	(*BatchItem)(nil),              // 13: api.BatchItem
+	(*FibonacciBatchResponse)(nil), // 14: api.FibonacciBatchResponse
+	(*BatchResult)(nil),            // 15: api.BatchResult
+	(*BatchError)(nil),             // 16: api.BatchError
+	(*SubmitJobRequest)(nil),       // 17: api.SubmitJobRequest
+	(*SubmitJobResponse)(nil),      // 18: api.SubmitJobResponse
+	(*GetJobStatusRequest)(nil),    // 19: api.GetJobStatusRequest
+	(*CancelJobRequest)(nil),       // 20: api.CancelJobRequest
+	(*JobStatus)(nil),              // 21: api.JobStatus
+	(*FetchJobResultRequest)(nil),  // 22: api.FetchJobResultRequest
+	(*anypb.Any)(nil),              // 23: google.protobuf.Any
+	(*durationpb.Duration)(nil),    // 24: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil),  // 25: google.protobuf.Timestamp
+}
+var file_api_fibonacci_proto_depIdxs = []int32{
+	0,  // 0: api.Sequence.kind:type_name -> api.SequenceKind
+	2,  // 1: api.FibonacciRequest.sequence:type_name -> api.Sequence
+	2,  // 2: api.FibonacciStreamRequest.sequence:type_name -> api.Sequence
+	5,  // 3: api.FibonacciFlowRequest.start:type_name -> api.FibonacciStreamRequest
+	2,  // 4: api.FibonacciNthRequest.sequence:type_name -> api.Sequence
+	13, // 5: api.FibonacciBatchRequest.items:type_name -> api.BatchItem
+	3,  // 6: api.BatchItem.range:type_name -> api.FibonacciRequest
+	8,  // 7: api.BatchItem.nth:type_name -> api.FibonacciNthRequest
+	15, // 8: api.FibonacciBatchResponse.results:type_name -> api.BatchResult
+	4,  // 9: api.BatchResult.range:type_name -> api.FibonacciResponse
+	9,  // 10: api.BatchResult.nth:type_name -> api.FibonacciNthResponse
+	16, // 11: api.BatchResult.error:type_name -> api.BatchError
+	23, // 12: api.BatchError.details:type_name -> google.protobuf.Any
+	2,  // 13: api.SubmitJobRequest.sequence:type_name -> api.Sequence
+	1,  // 14: api.JobStatus.state:type_name -> api.JobState
+	24, // 15: api.JobStatus.eta:type_name -> google.protobuf.Duration
+	25, // 16: api.JobStatus.submitted_at:type_name -> google.protobuf.Timestamp
+	25, // 17: api.JobStatus.started_at:type_name -> google.protobuf.Timestamp
+	25, // 18: api.JobStatus.finished_at:type_name -> google.protobuf.Timestamp
+	5,  // 19: api.FibonacciService.FibonacciStream:input_type -> api.FibonacciStreamRequest
+	7,  // 20: api.FibonacciService.FibonacciFlow:input_type -> api.FibonacciFlowRequest
+	3,  // 21: api.FibonacciService.Fibonacci:input_type -> api.FibonacciRequest
+	8,  // 22: api.FibonacciService.FibonacciNth:input_type -> api.FibonacciNthRequest
+	10, // 23: api.FibonacciService.PisanoPeriod:input_type -> api.PisanoPeriodRequest
+	12, // 24: api.FibonacciService.FibonacciBatch:input_type -> api.FibonacciBatchRequest
+	17, // 25: api.FibonacciService.SubmitJob:input_type -> api.SubmitJobRequest
+	19, // 26: api.FibonacciService.GetJobStatus:input_type -> api.GetJobStatusRequest
+	20, // 27: api.FibonacciService.CancelJob:input_type -> api.CancelJobRequest
+	22, // 28: api.FibonacciService.FetchJobResult:input_type -> api.FetchJobResultRequest
+	6,  // 29: api.FibonacciService.FibonacciStream:output_type -> api.FibonacciChunk
+	6,  // 30: api.FibonacciService.FibonacciFlow:output_type -> api.FibonacciChunk
+	4,  // 31: api.FibonacciService.Fibonacci:output_type -> api.FibonacciResponse
+	9,  // 32: api.FibonacciService.FibonacciNth:output_type -> api.FibonacciNthResponse
+	11, // 33: api.FibonacciService.PisanoPeriod:output_type -> api.PisanoPeriodResponse
+	14, // 34: api.FibonacciService.FibonacciBatch:output_type -> api.FibonacciBatchResponse
+	18, // 35: api.FibonacciService.SubmitJob:output_type -> api.SubmitJobResponse
+	21, // 36: api.FibonacciService.GetJobStatus:output_type -> api.JobStatus
+	21, // 37: api.FibonacciService.CancelJob:output_type -> api.JobStatus
+	6,  // 38: api.FibonacciService.FetchJobResult:output_type -> api.FibonacciChunk
+	29, // [29:39] is the sub-list for method output_type
+	19, // [19:29] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
+}
+
+func init() { file_api_fibonacci_proto_init() }
+func file_api_fibonacci_proto_init() {
+	if File_api_fibonacci_proto != nil {
+		return
+	}
+	file_api_fibonacci_proto_msgTypes[1].OneofWrappers = []any{}
+	file_api_fibonacci_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_fibonacci_proto_msgTypes[5].OneofWrappers = []any{
+		(*FibonacciFlowRequest_Start)(nil),
+		(*FibonacciFlowRequest_Next)(nil),
+		(*FibonacciFlowRequest_ChunkSize)(nil),
+		(*FibonacciFlowRequest_Pause)(nil),
+		(*FibonacciFlowRequest_Seek)(nil),
+	}
+	file_api_fibonacci_proto_msgTypes[11].OneofWrappers = []any{
+		(*BatchItem_Range)(nil),
+		(*BatchItem_Nth)(nil),
+	}
+	file_api_fibonacci_proto_msgTypes[13].OneofWrappers = []any{
+		(*BatchResult_Range)(nil),
+		(*BatchResult_Nth)(nil),
+		(*BatchResult_Error)(nil),
 	}
+	file_api_fibonacci_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_fibonacci_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   4,
+			NumEnums:      2,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_api_fibonacci_proto_goTypes,
 		DependencyIndexes: file_api_fibonacci_proto_depIdxs,
+		EnumInfos:         file_api_fibonacci_proto_enumTypes,
 		MessageInfos:      file_api_fibonacci_proto_msgTypes,
 	}.Build()
 	File_api_fibonacci_proto = out.File
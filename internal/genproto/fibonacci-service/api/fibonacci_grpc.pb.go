@@ -20,7 +20,15 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	FibonacciService_FibonacciStream_FullMethodName = "/api.FibonacciService/FibonacciStream"
+	FibonacciService_FibonacciFlow_FullMethodName   = "/api.FibonacciService/FibonacciFlow"
 	FibonacciService_Fibonacci_FullMethodName       = "/api.FibonacciService/Fibonacci"
+	FibonacciService_FibonacciNth_FullMethodName    = "/api.FibonacciService/FibonacciNth"
+	FibonacciService_PisanoPeriod_FullMethodName    = "/api.FibonacciService/PisanoPeriod"
+	FibonacciService_FibonacciBatch_FullMethodName  = "/api.FibonacciService/FibonacciBatch"
+	FibonacciService_SubmitJob_FullMethodName       = "/api.FibonacciService/SubmitJob"
+	FibonacciService_GetJobStatus_FullMethodName    = "/api.FibonacciService/GetJobStatus"
+	FibonacciService_CancelJob_FullMethodName       = "/api.FibonacciService/CancelJob"
+	FibonacciService_FetchJobResult_FullMethodName  = "/api.FibonacciService/FetchJobResult"
 )
 
 // FibonacciServiceClient is the client API for FibonacciService service.
@@ -28,7 +36,15 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type FibonacciServiceClient interface {
 	FibonacciStream(ctx context.Context, in *FibonacciStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FibonacciChunk], error)
+	FibonacciFlow(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FibonacciFlowRequest, FibonacciChunk], error)
 	Fibonacci(ctx context.Context, in *FibonacciRequest, opts ...grpc.CallOption) (*FibonacciResponse, error)
+	FibonacciNth(ctx context.Context, in *FibonacciNthRequest, opts ...grpc.CallOption) (*FibonacciNthResponse, error)
+	PisanoPeriod(ctx context.Context, in *PisanoPeriodRequest, opts ...grpc.CallOption) (*PisanoPeriodResponse, error)
+	FibonacciBatch(ctx context.Context, in *FibonacciBatchRequest, opts ...grpc.CallOption) (*FibonacciBatchResponse, error)
+	SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error)
+	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*JobStatus, error)
+	FetchJobResult(ctx context.Context, in *FetchJobResultRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FibonacciChunk], error)
 }
 
 type fibonacciServiceClient struct {
@@ -58,6 +74,19 @@ func (c *fibonacciServiceClient) FibonacciStream(ctx context.Context, in *Fibona
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type FibonacciService_FibonacciStreamClient = grpc.ServerStreamingClient[FibonacciChunk]
 
+func (c *fibonacciServiceClient) FibonacciFlow(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FibonacciFlowRequest, FibonacciChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FibonacciService_ServiceDesc.Streams[1], FibonacciService_FibonacciFlow_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FibonacciFlowRequest, FibonacciChunk]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FibonacciService_FibonacciFlowClient = grpc.BidiStreamingClient[FibonacciFlowRequest, FibonacciChunk]
+
 func (c *fibonacciServiceClient) Fibonacci(ctx context.Context, in *FibonacciRequest, opts ...grpc.CallOption) (*FibonacciResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FibonacciResponse)
@@ -68,12 +97,99 @@ func (c *fibonacciServiceClient) Fibonacci(ctx context.Context, in *FibonacciReq
 	return out, nil
 }
 
+func (c *fibonacciServiceClient) FibonacciNth(ctx context.Context, in *FibonacciNthRequest, opts ...grpc.CallOption) (*FibonacciNthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FibonacciNthResponse)
+	err := c.cc.Invoke(ctx, FibonacciService_FibonacciNth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fibonacciServiceClient) PisanoPeriod(ctx context.Context, in *PisanoPeriodRequest, opts ...grpc.CallOption) (*PisanoPeriodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PisanoPeriodResponse)
+	err := c.cc.Invoke(ctx, FibonacciService_PisanoPeriod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fibonacciServiceClient) FibonacciBatch(ctx context.Context, in *FibonacciBatchRequest, opts ...grpc.CallOption) (*FibonacciBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FibonacciBatchResponse)
+	err := c.cc.Invoke(ctx, FibonacciService_FibonacciBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fibonacciServiceClient) SubmitJob(ctx context.Context, in *SubmitJobRequest, opts ...grpc.CallOption) (*SubmitJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitJobResponse)
+	err := c.cc.Invoke(ctx, FibonacciService_SubmitJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fibonacciServiceClient) GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, FibonacciService_GetJobStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fibonacciServiceClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*JobStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobStatus)
+	err := c.cc.Invoke(ctx, FibonacciService_CancelJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fibonacciServiceClient) FetchJobResult(ctx context.Context, in *FetchJobResultRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[FibonacciChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FibonacciService_ServiceDesc.Streams[2], FibonacciService_FetchJobResult_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FetchJobResultRequest, FibonacciChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FibonacciService_FetchJobResultClient = grpc.ServerStreamingClient[FibonacciChunk]
+
 // FibonacciServiceServer is the server API for FibonacciService service.
 // All implementations must embed UnimplementedFibonacciServiceServer
 // for forward compatibility.
 type FibonacciServiceServer interface {
 	FibonacciStream(*FibonacciStreamRequest, grpc.ServerStreamingServer[FibonacciChunk]) error
+	FibonacciFlow(grpc.BidiStreamingServer[FibonacciFlowRequest, FibonacciChunk]) error
 	Fibonacci(context.Context, *FibonacciRequest) (*FibonacciResponse, error)
+	FibonacciNth(context.Context, *FibonacciNthRequest) (*FibonacciNthResponse, error)
+	PisanoPeriod(context.Context, *PisanoPeriodRequest) (*PisanoPeriodResponse, error)
+	FibonacciBatch(context.Context, *FibonacciBatchRequest) (*FibonacciBatchResponse, error)
+	SubmitJob(context.Context, *SubmitJobRequest) (*SubmitJobResponse, error)
+	GetJobStatus(context.Context, *GetJobStatusRequest) (*JobStatus, error)
+	CancelJob(context.Context, *CancelJobRequest) (*JobStatus, error)
+	FetchJobResult(*FetchJobResultRequest, grpc.ServerStreamingServer[FibonacciChunk]) error
 	mustEmbedUnimplementedFibonacciServiceServer()
 }
 
@@ -87,9 +203,33 @@ type UnimplementedFibonacciServiceServer struct{}
 func (UnimplementedFibonacciServiceServer) FibonacciStream(*FibonacciStreamRequest, grpc.ServerStreamingServer[FibonacciChunk]) error {
 	return status.Errorf(codes.Unimplemented, "method FibonacciStream not implemented")
 }
+func (UnimplementedFibonacciServiceServer) FibonacciFlow(grpc.BidiStreamingServer[FibonacciFlowRequest, FibonacciChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method FibonacciFlow not implemented")
+}
 func (UnimplementedFibonacciServiceServer) Fibonacci(context.Context, *FibonacciRequest) (*FibonacciResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Fibonacci not implemented")
 }
+func (UnimplementedFibonacciServiceServer) FibonacciNth(context.Context, *FibonacciNthRequest) (*FibonacciNthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FibonacciNth not implemented")
+}
+func (UnimplementedFibonacciServiceServer) PisanoPeriod(context.Context, *PisanoPeriodRequest) (*PisanoPeriodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PisanoPeriod not implemented")
+}
+func (UnimplementedFibonacciServiceServer) FibonacciBatch(context.Context, *FibonacciBatchRequest) (*FibonacciBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FibonacciBatch not implemented")
+}
+func (UnimplementedFibonacciServiceServer) SubmitJob(context.Context, *SubmitJobRequest) (*SubmitJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitJob not implemented")
+}
+func (UnimplementedFibonacciServiceServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobStatus not implemented")
+}
+func (UnimplementedFibonacciServiceServer) CancelJob(context.Context, *CancelJobRequest) (*JobStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedFibonacciServiceServer) FetchJobResult(*FetchJobResultRequest, grpc.ServerStreamingServer[FibonacciChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method FetchJobResult not implemented")
+}
 func (UnimplementedFibonacciServiceServer) mustEmbedUnimplementedFibonacciServiceServer() {}
 func (UnimplementedFibonacciServiceServer) testEmbeddedByValue()                          {}
 
@@ -122,6 +262,13 @@ func _FibonacciService_FibonacciStream_Handler(srv interface{}, stream grpc.Serv
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type FibonacciService_FibonacciStreamServer = grpc.ServerStreamingServer[FibonacciChunk]
 
+func _FibonacciService_FibonacciFlow_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FibonacciServiceServer).FibonacciFlow(&grpc.GenericServerStream[FibonacciFlowRequest, FibonacciChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FibonacciService_FibonacciFlowServer = grpc.BidiStreamingServer[FibonacciFlowRequest, FibonacciChunk]
+
 func _FibonacciService_Fibonacci_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FibonacciRequest)
 	if err := dec(in); err != nil {
@@ -140,6 +287,125 @@ func _FibonacciService_Fibonacci_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _FibonacciService_FibonacciNth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FibonacciNthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FibonacciServiceServer).FibonacciNth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FibonacciService_FibonacciNth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FibonacciServiceServer).FibonacciNth(ctx, req.(*FibonacciNthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FibonacciService_PisanoPeriod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PisanoPeriodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FibonacciServiceServer).PisanoPeriod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FibonacciService_PisanoPeriod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FibonacciServiceServer).PisanoPeriod(ctx, req.(*PisanoPeriodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FibonacciService_FibonacciBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FibonacciBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FibonacciServiceServer).FibonacciBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FibonacciService_FibonacciBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FibonacciServiceServer).FibonacciBatch(ctx, req.(*FibonacciBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FibonacciService_SubmitJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FibonacciServiceServer).SubmitJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FibonacciService_SubmitJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FibonacciServiceServer).SubmitJob(ctx, req.(*SubmitJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FibonacciService_GetJobStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FibonacciServiceServer).GetJobStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FibonacciService_GetJobStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FibonacciServiceServer).GetJobStatus(ctx, req.(*GetJobStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FibonacciService_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FibonacciServiceServer).CancelJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FibonacciService_CancelJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FibonacciServiceServer).CancelJob(ctx, req.(*CancelJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FibonacciService_FetchJobResult_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchJobResultRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FibonacciServiceServer).FetchJobResult(m, &grpc.GenericServerStream[FetchJobResultRequest, FibonacciChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FibonacciService_FetchJobResultServer = grpc.ServerStreamingServer[FibonacciChunk]
+
 // FibonacciService_ServiceDesc is the grpc.ServiceDesc for FibonacciService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +417,30 @@ var FibonacciService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Fibonacci",
 			Handler:    _FibonacciService_Fibonacci_Handler,
 		},
+		{
+			MethodName: "FibonacciNth",
+			Handler:    _FibonacciService_FibonacciNth_Handler,
+		},
+		{
+			MethodName: "PisanoPeriod",
+			Handler:    _FibonacciService_PisanoPeriod_Handler,
+		},
+		{
+			MethodName: "FibonacciBatch",
+			Handler:    _FibonacciService_FibonacciBatch_Handler,
+		},
+		{
+			MethodName: "SubmitJob",
+			Handler:    _FibonacciService_SubmitJob_Handler,
+		},
+		{
+			MethodName: "GetJobStatus",
+			Handler:    _FibonacciService_GetJobStatus_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _FibonacciService_CancelJob_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -158,6 +448,17 @@ var FibonacciService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _FibonacciService_FibonacciStream_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "FibonacciFlow",
+			Handler:       _FibonacciService_FibonacciFlow_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FetchJobResult",
+			Handler:       _FibonacciService_FetchJobResult_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/fibonacci.proto",
 }
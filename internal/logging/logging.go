@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader is the metadata key or HTTP header carrying the ID that correlates the log lines of a call.
+// Callers may pass their own, otherwise one is generated and returned in the response header.
+const RequestIDHeader = "x-request-id"
+
+// Setup configures logger to write JSON, or text if format is "text", at level.
+func Setup(logger *logrus.Logger, format, level string) error {
+	lvl, err := logrus.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return err
+	}
+
+	logger.SetLevel(lvl)
+
+	switch format {
+	case "", "json":
+		logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano})
+	case "text":
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return fmt.Errorf("unsupported log format %q, expected json or text", format)
+	}
+
+	return nil
+}
+
+type contextKey struct{}
+
+// NewContext returns ctx carrying logger, which is usually scoped to a request.
+func NewContext(ctx context.Context, logger logrus.FieldLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the standard logger if there is none.
+func FromContext(ctx context.Context) logrus.FieldLogger {
+	return Logger(ctx, logrus.StandardLogger())
+}
+
+// Logger returns the logger carried by ctx, or fallback if there is none.
+func Logger(ctx context.Context, fallback logrus.FieldLogger) logrus.FieldLogger {
+	if logger, ok := ctx.Value(contextKey{}).(logrus.FieldLogger); ok {
+		return logger
+	}
+
+	return fallback
+}
+
+// NewRequestID returns a random request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// Sampler thins out high-volume log lines: every second, the first lines of a key are allowed,
+// and then only every thereafter-th one. A nil Sampler allows every line.
+type Sampler struct {
+	first      int
+	thereafter int
+	now        func() time.Time
+
+	mu     sync.Mutex
+	second int64          // Unix second the counts are for
+	counts map[string]int // Lines seen by key this second
+}
+
+// NewSampler returns a Sampler allowing the first lines of a key per second and every thereafter-th one after them;
+// a thereafter of 0 drops all lines beyond the first. It returns nil, allowing every line, if first is 0.
+func NewSampler(first, thereafter int) *Sampler {
+	if first <= 0 {
+		return nil
+	}
+
+	return &Sampler{first: first, thereafter: thereafter, now: time.Now, counts: map[string]int{}}
+}
+
+// Allow reports whether a line of key is logged.
+func (s *Sampler) Allow(key string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if second := s.now().Unix(); second != s.second {
+		s.second = second
+		clear(s.counts)
+	}
+
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.first {
+		return true
+	}
+
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
@@ -0,0 +1,77 @@
+package logging_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fibonacci/internal/logging"
+
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetup(t *testing.T) {
+	logger := logrus.New()
+
+	assert.NoError(t, logging.Setup(logger, "json", "DEBUG"))
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.IsType(t, &logrus.JSONFormatter{}, logger.Formatter)
+
+	assert.Error(t, logging.Setup(logger, "xml", "info"))
+	assert.Error(t, logging.Setup(logger, "json", "loud"))
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, logrus.StandardLogger(), logging.FromContext(context.Background()))
+
+	logger, hook := logtest.NewNullLogger()
+	ctx := logging.NewContext(context.Background(), logger.WithField("request_id", "abc"))
+
+	logging.FromContext(ctx).Info("scoped")
+	assert.Equal(t, "abc", hook.LastEntry().Data["request_id"])
+}
+
+func TestSampler(t *testing.T) {
+	t.Run("nil allows every line", func(t *testing.T) {
+		s := logging.NewSampler(0, 10)
+		assert.Nil(t, s)
+		assert.True(t, s.Allow("key"))
+	})
+
+	t.Run("first lines and every thereafter-th", func(t *testing.T) {
+		s := logging.NewSampler(2, 3)
+		// Stay within a second, so that the counts are not reset midway.
+		waitForSecond()
+
+		var allowed []int
+		for i := 1; i <= 11; i++ {
+			if s.Allow("a") {
+				allowed = append(allowed, i)
+			}
+		}
+
+		assert.Equal(t, []int{1, 2, 5, 8, 11}, allowed)
+		assert.True(t, s.Allow("b"), "keys are sampled separately")
+	})
+
+	t.Run("counts reset every second", func(t *testing.T) {
+		s := logging.NewSampler(1, 0)
+		waitForSecond()
+
+		assert.True(t, s.Allow("a"))
+		assert.False(t, s.Allow("a"))
+
+		time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+		assert.True(t, s.Allow("a"))
+	})
+}
+
+// waitForSecond waits for the start of the next second if the current one is nearly over.
+func waitForSecond() {
+	now := time.Now()
+	if next := now.Truncate(time.Second).Add(time.Second); next.Sub(now) < 100*time.Millisecond {
+		time.Sleep(time.Until(next))
+	}
+}
@@ -4,45 +4,237 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+// Size classes bound the cardinality of the size label: a size falls in the first class it does not exceed.
+var sizeClasses = []struct {
+	limit uint64
+	label string
+}{
+	{100, "100"},
+	{1_000, "1k"},
+	{10_000, "10k"},
+	{100_000, "100k"},
+	{1_000_000, "1m"},
+}
+
+// SizeClass returns the size label of n: the number of terms of a range or stream, the index of a single term
+// or the modulus of a Pisano period.
+func SizeClass(n uint64) string {
+	for _, c := range sizeClasses {
+		if n <= c.limit {
+			return c.label
+		}
+	}
+
+	return "more"
+}
+
 var (
-	FibonacciCalculationDuration = prometheus.NewHistogramVec(
+	CalculationDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "fibonacci_calculation_duration_nanoseconds",
-			Help:    "Time spent calculating Fibonacci sequences, labeled by n size.",
-			Buckets: prometheus.DefBuckets,
+			Name: "fibonacci_calculation_duration_seconds",
+			Help: "Time spent on successful calculations, including sending the chunks of streams, labeled by method and size class.",
+			// 100µs to about 26s.
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10),
 		},
-		[]string{},
+		[]string{"method", "size"},
+	)
+
+	CalculationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_calculations_total",
+			Help: "Total number of successful calculations, labeled by method and size class.",
+		},
+		[]string{"method", "size"},
 	)
 
-	FibonacciStreamCalculationDuration = prometheus.NewHistogramVec(
+	ChunkSendDuration = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name:    "fibonacci_stream_calculation_duration_nanoseconds",
-			Help:    "Time spent calculating Fibonacci sequences, labeled by n and chunk size.",
-			Buckets: prometheus.DefBuckets,
+			Name: "fibonacci_chunk_send_duration_seconds",
+			Help: "Time spent sending a single stream chunk, which grows when clients read slower than chunks are computed.",
+			// 10µs to about 2.6s.
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10),
 		},
 		[]string{},
 	)
 
-	FibonacciCalculationsTotal = prometheus.NewCounterVec(
+	RequestsInFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "fibonacci_requests_in_flight",
+			Help: "Number of calls being handled, labeled by method.",
+		},
+		[]string{"method"},
+	)
+
+	EmittedDigitsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "fibonacci_calculations_total",
-			Help: "Total number of Fibonacci calculations performed, labeled by n size.",
+			Name: "fibonacci_emitted_digits_total",
+			Help: "Total number of decimal digits of the terms returned, labeled by method.",
+		},
+		[]string{"method"},
+	)
+
+	EmittedBytesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_emitted_bytes_total",
+			Help: "Total protobuf-encoded size of the responses and stream chunks returned, labeled by method.",
+		},
+		[]string{"method"},
+	)
+
+	ErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_errors_total",
+			Help: "Total number of failed calls, labeled by method and gRPC status code.",
+		},
+		[]string{"method", "code"},
+	)
+
+	StreamsCanceledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_streams_canceled_total",
+			Help: "Total number of streams ended before completion, labeled by method and reason (client, deadline or shutdown).",
+		},
+		[]string{"method", "reason"},
+	)
+
+	// gRPC server metrics, named like the usual gRPC Prometheus middleware so that its dashboards apply.
+
+	GRPCServerStartedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of calls started on the server.",
+		},
+		[]string{"grpc_type", "grpc_service", "grpc_method"},
+	)
+
+	GRPCServerHandledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of calls completed on the server, including rejected, failed and canceled ones, labeled by status code.",
+		},
+		[]string{"grpc_type", "grpc_service", "grpc_method", "grpc_code"},
+	)
+
+	GRPCServerHandlingSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "grpc_server_handling_seconds",
+			Help: "Time from the start of a call until the server completed it.",
+			// 1ms to about 65s.
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 9),
+		},
+		[]string{"grpc_type", "grpc_service", "grpc_method"},
+	)
+
+	GRPCServerMsgReceivedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of messages received from clients.",
+		},
+		[]string{"grpc_type", "grpc_service", "grpc_method"},
+	)
+
+	GRPCServerMsgSentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of messages sent to clients.",
+		},
+		[]string{"grpc_type", "grpc_service", "grpc_method"},
+	)
+
+	PanicsRecoveredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_panics_recovered_total",
+			Help: "Total number of handler panics turned into Internal errors, labeled by gRPC method.",
+		},
+		[]string{"grpc_method"},
+	)
+
+	FibonacciCoalescedCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_coalesced_calls_total",
+			Help: "Total number of calls served by the computation of an identical concurrent call, labeled by method.",
+		},
+		[]string{"method"},
+	)
+
+	CacheHitsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_cache_hits_total",
+			Help: "Total number of lookups answered from the cache, labeled by cache (checkpoint or prefix).",
+		},
+		[]string{"cache"},
+	)
+
+	CacheMissesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_cache_misses_total",
+			Help: "Total number of lookups not found in the cache, labeled by cache (checkpoint or prefix).",
+		},
+		[]string{"cache"},
+	)
+
+	ClientRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_client_requests_total",
+			Help: "Total number of gRPC calls subject to client limits, labeled by tier and client (API key name, jwt or anonymous).",
+		},
+		[]string{"tier", "client"},
+	)
+
+	ClientRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_client_rejections_total",
+			Help: "Total number of gRPC calls and stream messages rejected by client limits, labeled by tier, client and reason (rate or quota).",
+		},
+		[]string{"tier", "client", "reason"},
+	)
+
+	ClientDigitsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_client_digits_total",
+			Help: "Total number of decimal digits of the terms sent to clients, counted against their daily quota, labeled by tier and client.",
+		},
+		[]string{"tier", "client"},
+	)
+
+	AuthenticatedCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fibonacci_authenticated_calls_total",
+			Help: "Total number of authenticated calls, labeled by client (API key name or jwt) and auth (api_key or jwt).",
 		},
-		[]string{"n"},
+		[]string{"client", "auth"},
 	)
 
-	FibonacciStreamCalculationsTotal = prometheus.NewCounterVec(
+	AuthenticationFailuresTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
-			Name: "fibonacci_stream_calculations_total",
-			Help: "Total number of Fibonacci calculations performed, labeled by n and chunk size.",
+			Name: "fibonacci_authentication_failures_total",
+			Help: "Total number of calls rejected for missing or invalid credentials, labeled by method.",
 		},
-		[]string{"n", "chunk_size"},
+		[]string{"method"},
 	)
 )
 
 func init() {
-	prometheus.MustRegister(FibonacciCalculationDuration)
-	prometheus.MustRegister(FibonacciStreamCalculationDuration)
-	prometheus.MustRegister(FibonacciCalculationsTotal)
-	prometheus.MustRegister(FibonacciStreamCalculationsTotal)
+	prometheus.MustRegister(CalculationDuration)
+	prometheus.MustRegister(CalculationsTotal)
+	prometheus.MustRegister(ChunkSendDuration)
+	prometheus.MustRegister(RequestsInFlight)
+	prometheus.MustRegister(EmittedDigitsTotal)
+	prometheus.MustRegister(EmittedBytesTotal)
+	prometheus.MustRegister(ErrorsTotal)
+	prometheus.MustRegister(StreamsCanceledTotal)
+	prometheus.MustRegister(GRPCServerStartedTotal)
+	prometheus.MustRegister(GRPCServerHandledTotal)
+	prometheus.MustRegister(GRPCServerHandlingSeconds)
+	prometheus.MustRegister(GRPCServerMsgReceivedTotal)
+	prometheus.MustRegister(GRPCServerMsgSentTotal)
+	prometheus.MustRegister(PanicsRecoveredTotal)
+	prometheus.MustRegister(FibonacciCoalescedCallsTotal)
+	prometheus.MustRegister(CacheHitsTotal)
+	prometheus.MustRegister(CacheMissesTotal)
+	prometheus.MustRegister(ClientRequestsTotal)
+	prometheus.MustRegister(ClientRejectionsTotal)
+	prometheus.MustRegister(ClientDigitsTotal)
+	prometheus.MustRegister(AuthenticatedCallsTotal)
+	prometheus.MustRegister(AuthenticationFailuresTotal)
 }
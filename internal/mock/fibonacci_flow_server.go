@@ -0,0 +1,406 @@
+// Code generated by mockery v2.50.0. DO NOT EDIT.
+
+package mock
+
+import (
+	context "context"
+	api "fibonacci/internal/genproto/fibonacci-service/api"
+
+	metadata "google.golang.org/grpc/metadata"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FibonacciFlowServer is an autogenerated mock type for the FibonacciFlowServer type
+type FibonacciFlowServer struct {
+	mock.Mock
+}
+
+type FibonacciFlowServer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *FibonacciFlowServer) EXPECT() *FibonacciFlowServer_Expecter {
+	return &FibonacciFlowServer_Expecter{mock: &_m.Mock}
+}
+
+// Context provides a mock function with no fields
+func (_m *FibonacciFlowServer) Context() context.Context {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Context")
+	}
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func() context.Context); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// FibonacciFlowServer_Context_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Context'
+type FibonacciFlowServer_Context_Call struct {
+	*mock.Call
+}
+
+// Context is a helper method to define mock.On call
+func (_e *FibonacciFlowServer_Expecter) Context() *FibonacciFlowServer_Context_Call {
+	return &FibonacciFlowServer_Context_Call{Call: _e.mock.On("Context")}
+}
+
+func (_c *FibonacciFlowServer_Context_Call) Run(run func()) *FibonacciFlowServer_Context_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_Context_Call) Return(_a0 context.Context) *FibonacciFlowServer_Context_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_Context_Call) RunAndReturn(run func() context.Context) *FibonacciFlowServer_Context_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Recv provides a mock function with no fields
+func (_m *FibonacciFlowServer) Recv() (*api.FibonacciFlowRequest, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Recv")
+	}
+
+	var r0 *api.FibonacciFlowRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*api.FibonacciFlowRequest, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *api.FibonacciFlowRequest); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*api.FibonacciFlowRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FibonacciFlowServer_Recv_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Recv'
+type FibonacciFlowServer_Recv_Call struct {
+	*mock.Call
+}
+
+// Recv is a helper method to define mock.On call
+func (_e *FibonacciFlowServer_Expecter) Recv() *FibonacciFlowServer_Recv_Call {
+	return &FibonacciFlowServer_Recv_Call{Call: _e.mock.On("Recv")}
+}
+
+func (_c *FibonacciFlowServer_Recv_Call) Run(run func()) *FibonacciFlowServer_Recv_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_Recv_Call) Return(_a0 *api.FibonacciFlowRequest, _a1 error) *FibonacciFlowServer_Recv_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_Recv_Call) RunAndReturn(run func() (*api.FibonacciFlowRequest, error)) *FibonacciFlowServer_Recv_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecvMsg provides a mock function with given fields: m
+func (_m *FibonacciFlowServer) RecvMsg(m interface{}) error {
+	ret := _m.Called(m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecvMsg")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
+		r0 = rf(m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FibonacciFlowServer_RecvMsg_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecvMsg'
+type FibonacciFlowServer_RecvMsg_Call struct {
+	*mock.Call
+}
+
+// RecvMsg is a helper method to define mock.On call
+//   - m interface{}
+func (_e *FibonacciFlowServer_Expecter) RecvMsg(m interface{}) *FibonacciFlowServer_RecvMsg_Call {
+	return &FibonacciFlowServer_RecvMsg_Call{Call: _e.mock.On("RecvMsg", m)}
+}
+
+func (_c *FibonacciFlowServer_RecvMsg_Call) Run(run func(m interface{})) *FibonacciFlowServer_RecvMsg_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(interface{}))
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_RecvMsg_Call) Return(_a0 error) *FibonacciFlowServer_RecvMsg_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_RecvMsg_Call) RunAndReturn(run func(interface{}) error) *FibonacciFlowServer_RecvMsg_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Send provides a mock function with given fields: _a0
+func (_m *FibonacciFlowServer) Send(_a0 *api.FibonacciChunk) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*api.FibonacciChunk) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FibonacciFlowServer_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type FibonacciFlowServer_Send_Call struct {
+	*mock.Call
+}
+
+// Send is a helper method to define mock.On call
+//   - _a0 *api.FibonacciChunk
+func (_e *FibonacciFlowServer_Expecter) Send(_a0 interface{}) *FibonacciFlowServer_Send_Call {
+	return &FibonacciFlowServer_Send_Call{Call: _e.mock.On("Send", _a0)}
+}
+
+func (_c *FibonacciFlowServer_Send_Call) Run(run func(_a0 *api.FibonacciChunk)) *FibonacciFlowServer_Send_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*api.FibonacciChunk))
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_Send_Call) Return(_a0 error) *FibonacciFlowServer_Send_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_Send_Call) RunAndReturn(run func(*api.FibonacciChunk) error) *FibonacciFlowServer_Send_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendHeader provides a mock function with given fields: _a0
+func (_m *FibonacciFlowServer) SendHeader(_a0 metadata.MD) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendHeader")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(metadata.MD) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FibonacciFlowServer_SendHeader_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendHeader'
+type FibonacciFlowServer_SendHeader_Call struct {
+	*mock.Call
+}
+
+// SendHeader is a helper method to define mock.On call
+//   - _a0 metadata.MD
+func (_e *FibonacciFlowServer_Expecter) SendHeader(_a0 interface{}) *FibonacciFlowServer_SendHeader_Call {
+	return &FibonacciFlowServer_SendHeader_Call{Call: _e.mock.On("SendHeader", _a0)}
+}
+
+func (_c *FibonacciFlowServer_SendHeader_Call) Run(run func(_a0 metadata.MD)) *FibonacciFlowServer_SendHeader_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(metadata.MD))
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SendHeader_Call) Return(_a0 error) *FibonacciFlowServer_SendHeader_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SendHeader_Call) RunAndReturn(run func(metadata.MD) error) *FibonacciFlowServer_SendHeader_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendMsg provides a mock function with given fields: m
+func (_m *FibonacciFlowServer) SendMsg(m interface{}) error {
+	ret := _m.Called(m)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMsg")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(interface{}) error); ok {
+		r0 = rf(m)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FibonacciFlowServer_SendMsg_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendMsg'
+type FibonacciFlowServer_SendMsg_Call struct {
+	*mock.Call
+}
+
+// SendMsg is a helper method to define mock.On call
+//   - m interface{}
+func (_e *FibonacciFlowServer_Expecter) SendMsg(m interface{}) *FibonacciFlowServer_SendMsg_Call {
+	return &FibonacciFlowServer_SendMsg_Call{Call: _e.mock.On("SendMsg", m)}
+}
+
+func (_c *FibonacciFlowServer_SendMsg_Call) Run(run func(m interface{})) *FibonacciFlowServer_SendMsg_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(interface{}))
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SendMsg_Call) Return(_a0 error) *FibonacciFlowServer_SendMsg_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SendMsg_Call) RunAndReturn(run func(interface{}) error) *FibonacciFlowServer_SendMsg_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetHeader provides a mock function with given fields: _a0
+func (_m *FibonacciFlowServer) SetHeader(_a0 metadata.MD) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetHeader")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(metadata.MD) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FibonacciFlowServer_SetHeader_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetHeader'
+type FibonacciFlowServer_SetHeader_Call struct {
+	*mock.Call
+}
+
+// SetHeader is a helper method to define mock.On call
+//   - _a0 metadata.MD
+func (_e *FibonacciFlowServer_Expecter) SetHeader(_a0 interface{}) *FibonacciFlowServer_SetHeader_Call {
+	return &FibonacciFlowServer_SetHeader_Call{Call: _e.mock.On("SetHeader", _a0)}
+}
+
+func (_c *FibonacciFlowServer_SetHeader_Call) Run(run func(_a0 metadata.MD)) *FibonacciFlowServer_SetHeader_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(metadata.MD))
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SetHeader_Call) Return(_a0 error) *FibonacciFlowServer_SetHeader_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SetHeader_Call) RunAndReturn(run func(metadata.MD) error) *FibonacciFlowServer_SetHeader_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetTrailer provides a mock function with given fields: _a0
+func (_m *FibonacciFlowServer) SetTrailer(_a0 metadata.MD) {
+	_m.Called(_a0)
+}
+
+// FibonacciFlowServer_SetTrailer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetTrailer'
+type FibonacciFlowServer_SetTrailer_Call struct {
+	*mock.Call
+}
+
+// SetTrailer is a helper method to define mock.On call
+//   - _a0 metadata.MD
+func (_e *FibonacciFlowServer_Expecter) SetTrailer(_a0 interface{}) *FibonacciFlowServer_SetTrailer_Call {
+	return &FibonacciFlowServer_SetTrailer_Call{Call: _e.mock.On("SetTrailer", _a0)}
+}
+
+func (_c *FibonacciFlowServer_SetTrailer_Call) Run(run func(_a0 metadata.MD)) *FibonacciFlowServer_SetTrailer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(metadata.MD))
+	})
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SetTrailer_Call) Return() *FibonacciFlowServer_SetTrailer_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *FibonacciFlowServer_SetTrailer_Call) RunAndReturn(run func(metadata.MD)) *FibonacciFlowServer_SetTrailer_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewFibonacciFlowServer creates a new instance of FibonacciFlowServer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFibonacciFlowServer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FibonacciFlowServer {
+	mock := &FibonacciFlowServer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,9 +22,113 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
-// GetFibonacci provides a mock function with given fields: ctx, n
-func (_m *Service) GetFibonacci(ctx context.Context, n int) ([]string, error) {
-	ret := _m.Called(ctx, n)
+// CancelJob provides a mock function with given fields: ctx, id
+func (_m *Service) CancelJob(ctx context.Context, id string) (domain.JobStatus, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelJob")
+	}
+
+	var r0 domain.JobStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.JobStatus, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.JobStatus); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.JobStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_CancelJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelJob'
+type Service_CancelJob_Call struct {
+	*mock.Call
+}
+
+// CancelJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Service_Expecter) CancelJob(ctx interface{}, id interface{}) *Service_CancelJob_Call {
+	return &Service_CancelJob_Call{Call: _e.mock.On("CancelJob", ctx, id)}
+}
+
+func (_c *Service_CancelJob_Call) Run(run func(ctx context.Context, id string)) *Service_CancelJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_CancelJob_Call) Return(_a0 domain.JobStatus, _a1 error) *Service_CancelJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_CancelJob_Call) RunAndReturn(run func(context.Context, string) (domain.JobStatus, error)) *Service_CancelJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchJobResult provides a mock function with given fields: ctx, req
+func (_m *Service) FetchJobResult(ctx context.Context, req domain.FetchJobResultRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchJobResult")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FetchJobResultRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_FetchJobResult_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchJobResult'
+type Service_FetchJobResult_Call struct {
+	*mock.Call
+}
+
+// FetchJobResult is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req domain.FetchJobResultRequest
+func (_e *Service_Expecter) FetchJobResult(ctx interface{}, req interface{}) *Service_FetchJobResult_Call {
+	return &Service_FetchJobResult_Call{Call: _e.mock.On("FetchJobResult", ctx, req)}
+}
+
+func (_c *Service_FetchJobResult_Call) Run(run func(ctx context.Context, req domain.FetchJobResultRequest)) *Service_FetchJobResult_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.FetchJobResultRequest))
+	})
+	return _c
+}
+
+func (_c *Service_FetchJobResult_Call) Return(_a0 error) *Service_FetchJobResult_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_FetchJobResult_Call) RunAndReturn(run func(context.Context, domain.FetchJobResultRequest) error) *Service_FetchJobResult_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFibonacci provides a mock function with given fields: ctx, req
+func (_m *Service) GetFibonacci(ctx context.Context, req domain.FibonacciRequest) ([]string, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetFibonacci")
@@ -32,19 +136,19 @@ func (_m *Service) GetFibonacci(ctx context.Context, n int) ([]string, error) {
 
 	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]string, error)); ok {
-		return rf(ctx, n)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FibonacciRequest) ([]string, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []string); ok {
-		r0 = rf(ctx, n)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FibonacciRequest) []string); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, n)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.FibonacciRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -59,14 +163,14 @@ type Service_GetFibonacci_Call struct {
 
 // GetFibonacci is a helper method to define mock.On call
 //   - ctx context.Context
-//   - n int
-func (_e *Service_Expecter) GetFibonacci(ctx interface{}, n interface{}) *Service_GetFibonacci_Call {
-	return &Service_GetFibonacci_Call{Call: _e.mock.On("GetFibonacci", ctx, n)}
+//   - req domain.FibonacciRequest
+func (_e *Service_Expecter) GetFibonacci(ctx interface{}, req interface{}) *Service_GetFibonacci_Call {
+	return &Service_GetFibonacci_Call{Call: _e.mock.On("GetFibonacci", ctx, req)}
 }
 
-func (_c *Service_GetFibonacci_Call) Run(run func(ctx context.Context, n int)) *Service_GetFibonacci_Call {
+func (_c *Service_GetFibonacci_Call) Run(run func(ctx context.Context, req domain.FibonacciRequest)) *Service_GetFibonacci_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(int))
+		run(args[0].(context.Context), args[1].(domain.FibonacciRequest))
 	})
 	return _c
 }
@@ -76,7 +180,113 @@ func (_c *Service_GetFibonacci_Call) Return(_a0 []string, _a1 error) *Service_Ge
 	return _c
 }
 
-func (_c *Service_GetFibonacci_Call) RunAndReturn(run func(context.Context, int) ([]string, error)) *Service_GetFibonacci_Call {
+func (_c *Service_GetFibonacci_Call) RunAndReturn(run func(context.Context, domain.FibonacciRequest) ([]string, error)) *Service_GetFibonacci_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFibonacciBatch provides a mock function with given fields: ctx, items
+func (_m *Service) GetFibonacciBatch(ctx context.Context, items []domain.BatchItem) ([]domain.BatchResult, error) {
+	ret := _m.Called(ctx, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFibonacciBatch")
+	}
+
+	var r0 []domain.BatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.BatchItem) ([]domain.BatchResult, error)); ok {
+		return rf(ctx, items)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.BatchItem) []domain.BatchResult); ok {
+		r0 = rf(ctx, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.BatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.BatchItem) error); ok {
+		r1 = rf(ctx, items)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetFibonacciBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFibonacciBatch'
+type Service_GetFibonacciBatch_Call struct {
+	*mock.Call
+}
+
+// GetFibonacciBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - items []domain.BatchItem
+func (_e *Service_Expecter) GetFibonacciBatch(ctx interface{}, items interface{}) *Service_GetFibonacciBatch_Call {
+	return &Service_GetFibonacciBatch_Call{Call: _e.mock.On("GetFibonacciBatch", ctx, items)}
+}
+
+func (_c *Service_GetFibonacciBatch_Call) Run(run func(ctx context.Context, items []domain.BatchItem)) *Service_GetFibonacciBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]domain.BatchItem))
+	})
+	return _c
+}
+
+func (_c *Service_GetFibonacciBatch_Call) Return(_a0 []domain.BatchResult, _a1 error) *Service_GetFibonacciBatch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetFibonacciBatch_Call) RunAndReturn(run func(context.Context, []domain.BatchItem) ([]domain.BatchResult, error)) *Service_GetFibonacciBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetFibonacciFlow provides a mock function with given fields: ctx, req
+func (_m *Service) GetFibonacciFlow(ctx context.Context, req domain.FibonacciFlowRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFibonacciFlow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FibonacciFlowRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_GetFibonacciFlow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetFibonacciFlow'
+type Service_GetFibonacciFlow_Call struct {
+	*mock.Call
+}
+
+// GetFibonacciFlow is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req domain.FibonacciFlowRequest
+func (_e *Service_Expecter) GetFibonacciFlow(ctx interface{}, req interface{}) *Service_GetFibonacciFlow_Call {
+	return &Service_GetFibonacciFlow_Call{Call: _e.mock.On("GetFibonacciFlow", ctx, req)}
+}
+
+func (_c *Service_GetFibonacciFlow_Call) Run(run func(ctx context.Context, req domain.FibonacciFlowRequest)) *Service_GetFibonacciFlow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.FibonacciFlowRequest))
+	})
+	return _c
+}
+
+func (_c *Service_GetFibonacciFlow_Call) Return(_a0 error) *Service_GetFibonacciFlow_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_GetFibonacciFlow_Call) RunAndReturn(run func(context.Context, domain.FibonacciFlowRequest) error) *Service_GetFibonacciFlow_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -128,6 +338,234 @@ func (_c *Service_GetFibonacciStream_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
+// GetJobStatus provides a mock function with given fields: ctx, id
+func (_m *Service) GetJobStatus(ctx context.Context, id string) (domain.JobStatus, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobStatus")
+	}
+
+	var r0 domain.JobStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.JobStatus, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.JobStatus); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.JobStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetJobStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetJobStatus'
+type Service_GetJobStatus_Call struct {
+	*mock.Call
+}
+
+// GetJobStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Service_Expecter) GetJobStatus(ctx interface{}, id interface{}) *Service_GetJobStatus_Call {
+	return &Service_GetJobStatus_Call{Call: _e.mock.On("GetJobStatus", ctx, id)}
+}
+
+func (_c *Service_GetJobStatus_Call) Run(run func(ctx context.Context, id string)) *Service_GetJobStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Service_GetJobStatus_Call) Return(_a0 domain.JobStatus, _a1 error) *Service_GetJobStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetJobStatus_Call) RunAndReturn(run func(context.Context, string) (domain.JobStatus, error)) *Service_GetJobStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetNth provides a mock function with given fields: ctx, req
+func (_m *Service) GetNth(ctx context.Context, req domain.FibonacciNthRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNth")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FibonacciNthRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.FibonacciNthRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.FibonacciNthRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetNth_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetNth'
+type Service_GetNth_Call struct {
+	*mock.Call
+}
+
+// GetNth is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req domain.FibonacciNthRequest
+func (_e *Service_Expecter) GetNth(ctx interface{}, req interface{}) *Service_GetNth_Call {
+	return &Service_GetNth_Call{Call: _e.mock.On("GetNth", ctx, req)}
+}
+
+func (_c *Service_GetNth_Call) Run(run func(ctx context.Context, req domain.FibonacciNthRequest)) *Service_GetNth_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.FibonacciNthRequest))
+	})
+	return _c
+}
+
+func (_c *Service_GetNth_Call) Return(_a0 string, _a1 error) *Service_GetNth_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetNth_Call) RunAndReturn(run func(context.Context, domain.FibonacciNthRequest) (string, error)) *Service_GetNth_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPisanoPeriod provides a mock function with given fields: ctx, modulus
+func (_m *Service) GetPisanoPeriod(ctx context.Context, modulus uint64) (uint64, error) {
+	ret := _m.Called(ctx, modulus)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPisanoPeriod")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (uint64, error)); ok {
+		return rf(ctx, modulus)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) uint64); ok {
+		r0 = rf(ctx, modulus)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, modulus)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_GetPisanoPeriod_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPisanoPeriod'
+type Service_GetPisanoPeriod_Call struct {
+	*mock.Call
+}
+
+// GetPisanoPeriod is a helper method to define mock.On call
+//   - ctx context.Context
+//   - modulus uint64
+func (_e *Service_Expecter) GetPisanoPeriod(ctx interface{}, modulus interface{}) *Service_GetPisanoPeriod_Call {
+	return &Service_GetPisanoPeriod_Call{Call: _e.mock.On("GetPisanoPeriod", ctx, modulus)}
+}
+
+func (_c *Service_GetPisanoPeriod_Call) Run(run func(ctx context.Context, modulus uint64)) *Service_GetPisanoPeriod_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint64))
+	})
+	return _c
+}
+
+func (_c *Service_GetPisanoPeriod_Call) Return(_a0 uint64, _a1 error) *Service_GetPisanoPeriod_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_GetPisanoPeriod_Call) RunAndReturn(run func(context.Context, uint64) (uint64, error)) *Service_GetPisanoPeriod_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SubmitJob provides a mock function with given fields: ctx, req
+func (_m *Service) SubmitJob(ctx context.Context, req domain.JobRequest) (string, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubmitJob")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.JobRequest) (string, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.JobRequest) string); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.JobRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_SubmitJob_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SubmitJob'
+type Service_SubmitJob_Call struct {
+	*mock.Call
+}
+
+// SubmitJob is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req domain.JobRequest
+func (_e *Service_Expecter) SubmitJob(ctx interface{}, req interface{}) *Service_SubmitJob_Call {
+	return &Service_SubmitJob_Call{Call: _e.mock.On("SubmitJob", ctx, req)}
+}
+
+func (_c *Service_SubmitJob_Call) Run(run func(ctx context.Context, req domain.JobRequest)) *Service_SubmitJob_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.JobRequest))
+	})
+	return _c
+}
+
+func (_c *Service_SubmitJob_Call) Return(_a0 string, _a1 error) *Service_SubmitJob_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_SubmitJob_Call) RunAndReturn(run func(context.Context, domain.JobRequest) (string, error)) *Service_SubmitJob_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewService(t interface {
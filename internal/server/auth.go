@@ -0,0 +1,457 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"fibonacci/internal/metrics"
+	"fibonacci/internal/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+// AuthorizationHeader is the metadata key clients pass a JWT in, as "Bearer <token>".
+const AuthorizationHeader = "authorization"
+
+// Authentication methods, as reported in logs and metrics.
+const (
+	AuthAPIKey = "api_key"
+	AuthJWT    = "jwt"
+)
+
+// AuthConfig holds the credentials the server accepts, see LoadAuth for the file format.
+type AuthConfig struct {
+	APIKeys []APIKey   `yaml:"api_keys"`
+	JWT     *JWTConfig `yaml:"jwt"` // Nil if JWTs are not accepted
+}
+
+// APIKey is a static API key, stored as its SHA-256 so that the config does not reveal it.
+type APIKey struct {
+	Name   string `yaml:"name"`   // Identity of the callers using the key
+	SHA256 string `yaml:"sha256"` // Hex encoded SHA-256 of the key
+	Tier   string `yaml:"tier"`   // Tier of the client limits, if not assigned in the limits config
+
+	NLimit       int `yaml:"n_limit"`        // Overrides the N_LIMIT of the server if set
+	StreamNLimit int `yaml:"stream_n_limit"` // Overrides the STREAM_N_LIMIT of the server if set
+}
+
+// JWTConfig selects the JWTs the server accepts. Tokens must be signed with a key of the JWKS, name a subject
+// and expire; the issuer and audience are checked if set.
+//
+// Besides the registered claims, tokens may carry the tier, n_limit and stream_n_limit of the subject,
+// which have the same meaning as the fields of an APIKey.
+type JWTConfig struct {
+	JWKS     string `yaml:"jwks"` // Path of a JSON Web Key Set with the public keys of the issuer
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+}
+
+// LoadAuth reads the accepted credentials from the YAML file at path, e.g.
+//
+//	api_keys:
+//	  - {name: acme, sha256: "<sha256 of the key>", tier: pro, n_limit: 100000}
+//	jwt:
+//	  jwks: /etc/fibonacci/jwks.json
+//	  issuer: https://auth.example.com
+//	  audience: fibonacci
+func LoadAuth(path string) (AuthConfig, error) {
+	var cfg AuthConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Identity is the authenticated caller of a call.
+type Identity struct {
+	Name   string // Name of the API key or subject of the JWT
+	Method string // AuthAPIKey or AuthJWT
+	Tier   string // Tier of the client limits, empty if not set by the credentials
+	Limits service.ClientLimits
+}
+
+// metricsLabel returns the client label of the metrics of the identity: the name of its API key, which is one
+// of the configured keys, or "jwt" for JWT subjects, which an issuer can mint without bound. Logs and traces
+// name the subject itself.
+func (id Identity) metricsLabel() string {
+	if id.Method == AuthJWT {
+		return AuthJWT
+	}
+
+	return id.Name
+}
+
+type identityKey struct{}
+
+// IdentityFromContext returns the identity of the caller authenticated by an Authenticator.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// withIdentity returns a context carrying id, which also applies its limits to the service calls made with it.
+func withIdentity(ctx context.Context, id Identity) context.Context {
+	return service.WithClientLimits(context.WithValue(ctx, identityKey{}, id), id.Limits)
+}
+
+// errUnauthenticated is returned for calls without valid credentials. The cause is only logged,
+// so that callers cannot probe which keys or tokens exist.
+var errUnauthenticated = status.Error(codes.Unauthenticated, "valid API key or bearer token required")
+
+// Authenticator rejects the calls without a valid API key or JWT, and passes the identity of the caller
+// to the handlers, see IdentityFromContext.
+type Authenticator struct {
+	keys   map[string]APIKey // By SHA-256
+	jwt    *JWTConfig
+	jwks   map[string]any // Public keys by key ID
+	logger *logrus.Logger
+}
+
+// NewAuthenticator returns an Authenticator accepting the credentials of cfg, loading the JWKS it names.
+func NewAuthenticator(cfg AuthConfig, logger *logrus.Logger) (*Authenticator, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	a := &Authenticator{keys: make(map[string]APIKey, len(cfg.APIKeys)), jwt: cfg.JWT, logger: logger}
+
+	for _, key := range cfg.APIKeys {
+		sum, err := hex.DecodeString(key.SHA256)
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("API key %q: sha256 must be a hex encoded SHA-256", key.Name)
+		}
+
+		if key.Name == "" {
+			return nil, errors.New("API key without a name")
+		}
+
+		if _, ok := a.keys[string(sum)]; ok {
+			return nil, fmt.Errorf("API key %q: key is already used by another client", key.Name)
+		}
+
+		a.keys[string(sum)] = key
+	}
+
+	if cfg.JWT != nil {
+		jwks, err := loadJWKS(cfg.JWT.JWKS)
+		if err != nil {
+			return nil, fmt.Errorf("JWKS: %w", err)
+		}
+
+		a.jwks = jwks
+	}
+
+	if len(a.keys) == 0 && a.jwt == nil {
+		return nil, errors.New("no API keys and no JWKS configured, so no call could be authenticated")
+	}
+
+	return a, nil
+}
+
+// UnaryInterceptor authenticates unary calls, except the health checks.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isHealthCheck(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		id, err := a.authenticate(md, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(withIdentity(ctx, id), req)
+	}
+}
+
+// StreamInterceptor authenticates streaming calls, except the health checks.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isHealthCheck(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		md, _ := metadata.FromIncomingContext(ss.Context())
+
+		id, err := a.authenticate(md, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{ServerStream: ss, ctx: withIdentity(ss.Context(), id)})
+	}
+}
+
+// contextStream is a server stream with a context of its own.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// middleware authenticates HTTP requests, reading the credentials from the headers of the same names.
+func (a *Authenticator) middleware(onError func(http.ResponseWriter, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			md := metadata.MD{}
+			for _, name := range []string{APIKeyHeader, AuthorizationHeader} {
+				md.Set(name, r.Header.Values(name)...)
+			}
+
+			id, err := a.authenticate(md, r.URL.Path)
+			if err != nil {
+				onError(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(withIdentity(r.Context(), id)))
+		})
+	}
+}
+
+// authenticate returns the identity of the credentials in md, preferring an API key over a JWT.
+func (a *Authenticator) authenticate(md metadata.MD, method string) (Identity, error) {
+	var (
+		id  Identity
+		err error
+	)
+
+	if keys := md.Get(APIKeyHeader); len(keys) > 0 {
+		id, err = a.apiKeyIdentity(keys[0])
+	} else if auth := md.Get(AuthorizationHeader); len(auth) > 0 {
+		id, err = a.jwtIdentity(auth[0])
+	} else {
+		err = errors.New("no credentials")
+	}
+
+	if err != nil {
+		metrics.AuthenticationFailuresTotal.WithLabelValues(method).Inc()
+		a.logger.WithField("method", method).Warnf("Rejected unauthenticated call: %v", err)
+
+		return Identity{}, errUnauthenticated
+	}
+
+	metrics.AuthenticatedCallsTotal.WithLabelValues(id.metricsLabel(), id.Method).Inc()
+	a.logger.WithFields(logrus.Fields{"method": method, "client": id.Name, "auth": id.Method}).Debug("Authenticated call")
+
+	return id, nil
+}
+
+func (a *Authenticator) apiKeyIdentity(key string) (Identity, error) {
+	sum := sha256.Sum256([]byte(key))
+
+	// The lookup compares hashes, so its timing reveals nothing about the keys.
+	k, ok := a.keys[string(sum[:])]
+	if !ok {
+		return Identity{}, errors.New("unknown API key")
+	}
+
+	return Identity{
+		Name:   k.Name,
+		Method: AuthAPIKey,
+		Tier:   k.Tier,
+		Limits: service.ClientLimits{NLimit: k.NLimit, StreamNLimit: k.StreamNLimit},
+	}, nil
+}
+
+// jwtClaims are the claims of the accepted JWTs.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Tier         string `json:"tier"`
+	NLimit       int    `json:"n_limit"`
+	StreamNLimit int    `json:"stream_n_limit"`
+}
+
+// jwtMethods are the signing methods of the keys a JWKS can hold. Symmetric methods are excluded,
+// as a public key must never be accepted as an HMAC secret.
+var jwtMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+func (a *Authenticator) jwtIdentity(header string) (Identity, error) {
+	if a.jwt == nil {
+		return Identity{}, errors.New("JWTs are not accepted")
+	}
+
+	raw, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return Identity{}, errors.New("authorization is not a bearer token")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(jwtMethods), jwt.WithExpirationRequired()}
+	if a.jwt.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.jwt.Issuer))
+	}
+	if a.jwt.Audience != "" {
+		opts = append(opts, jwt.WithAudience(a.jwt.Audience))
+	}
+
+	var claims jwtClaims
+	if _, err := jwt.ParseWithClaims(raw, &claims, a.jwtKey, opts...); err != nil {
+		return Identity{}, err
+	}
+
+	if claims.Subject == "" {
+		return Identity{}, errors.New("token has no subject")
+	}
+
+	return Identity{
+		Name:   claims.Subject,
+		Method: AuthJWT,
+		Tier:   claims.Tier,
+		Limits: service.ClientLimits{NLimit: claims.NLimit, StreamNLimit: claims.StreamNLimit},
+	}, nil
+}
+
+// jwtKey returns the key of the JWKS a token names in its kid header, or the only key of the set if it names none.
+func (a *Authenticator) jwtKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" && len(a.jwks) == 1 {
+		for _, key := range a.jwks {
+			return key, nil
+		}
+	}
+
+	key, ok := a.jwks[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	return key, nil
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set, see RFC 7517 and RFC 7518.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// loadJWKS reads the RSA, EC and Ed25519 public keys of the JSON Web Key Set at path by key ID.
+// Keys meant for encryption rather than signatures are skipped.
+func loadJWKS(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %q: %w", path, k.Kid, err)
+		}
+
+		if _, ok := keys[k.Kid]; ok {
+			return nil, fmt.Errorf("%s: duplicate key %q", path, k.Kid)
+		}
+
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no signing keys", path)
+	}
+
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, e := decodeBase64URL(k.N), decodeBase64URL(k.E)
+		if n == nil || e == nil || len(e) > 4 {
+			return nil, errors.New("invalid RSA modulus or exponent")
+		}
+
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, y := decodeBase64URL(k.X), decodeBase64URL(k.Y)
+		if x == nil || y == nil {
+			return nil, errors.New("invalid EC coordinates")
+		}
+
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if _, err := key.ECDH(); err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	case "OKP":
+		x := decodeBase64URL(k.X)
+		if k.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("unsupported curve %q or invalid key", k.Crv)
+		}
+
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// decodeBase64URL decodes unpadded base64url, returning nil for invalid or empty input.
+func decodeBase64URL(s string) []byte {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+
+	return b
+}
@@ -0,0 +1,278 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/metrics"
+	"fibonacci/internal/server"
+	"fibonacci/internal/service"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthenticator(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": b64(edPublic)},
+		{"kty": "RSA", "kid": "enc", "use": "enc", "n": b64(rsaKey.N.Bytes()), "e": "AQAB"},
+	}})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(jwksPath, jwks, 0o644))
+
+	hash := func(key string) string {
+		sum := sha256.Sum256([]byte(key))
+		return hex.EncodeToString(sum[:])
+	}
+
+	auth, err := server.NewAuthenticator(server.AuthConfig{
+		APIKeys: []server.APIKey{
+			{Name: "acme", SHA256: hash("acme-key"), Tier: "pro", NLimit: 500},
+			{Name: "globex", SHA256: hash("globex-key")},
+		},
+		JWT: &server.JWTConfig{JWKS: jwksPath, Issuer: "https://auth.example.com", Audience: "fibonacci"},
+	}, logrus.New())
+	assert.NoError(t, err)
+
+	token := func(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+		base := jwt.MapClaims{
+			"sub": "alice",
+			"iss": "https://auth.example.com",
+			"aud": "fibonacci",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range claims {
+			base[k] = v
+		}
+
+		tok := jwt.NewWithClaims(method, base)
+		tok.Header["kid"] = kid
+
+		signed, err := tok.SignedString(key)
+		assert.NoError(t, err)
+
+		return "Bearer " + signed
+	}
+
+	// call makes a unary call w/ the metadata md, returning the identity the handler saw.
+	call := func(md metadata.MD) (server.Identity, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+
+		var id server.Identity
+		_, err := auth.UnaryInterceptor()(ctx, &api.FibonacciRequest{}, &grpc.UnaryServerInfo{FullMethod: "/api.FibonacciService/Fibonacci"},
+			func(ctx context.Context, req any) (any, error) {
+				id, _ = server.IdentityFromContext(ctx)
+				return &api.FibonacciResponse{}, nil
+			})
+
+		return id, err
+	}
+
+	t.Run("API keys", func(t *testing.T) {
+		id, err := call(metadata.Pairs(server.APIKeyHeader, "acme-key"))
+		assert.NoError(t, err)
+		assert.Equal(t, server.Identity{Name: "acme", Method: server.AuthAPIKey, Tier: "pro", Limits: service.ClientLimits{NLimit: 500}}, id)
+
+		id, err = call(metadata.Pairs(server.APIKeyHeader, "globex-key"))
+		assert.NoError(t, err)
+		assert.Equal(t, "globex", id.Name)
+	})
+
+	t.Run("JWTs", func(t *testing.T) {
+		subjects := metrics.AuthenticatedCallsTotal.WithLabelValues("jwt", server.AuthJWT)
+		before := testutil.ToFloat64(subjects)
+
+		for _, md := range []metadata.MD{
+			metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"tier": "pro", "stream_n_limit": 1000})),
+			metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodES256, "ec", ecKey, jwt.MapClaims{"tier": "pro", "stream_n_limit": 1000})),
+			metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodEdDSA, "ed", edKey, jwt.MapClaims{"tier": "pro", "stream_n_limit": 1000})),
+		} {
+			id, err := call(md)
+			assert.NoError(t, err)
+			assert.Equal(t, server.Identity{Name: "alice", Method: server.AuthJWT, Tier: "pro", Limits: service.ClientLimits{StreamNLimit: 1000}}, id)
+		}
+
+		// Subjects share a single series, as an issuer may mint any number of them.
+		assert.Equal(t, before+3, testutil.ToFloat64(subjects))
+		assert.Equal(t, 0.0, testutil.ToFloat64(metrics.AuthenticatedCallsTotal.WithLabelValues("alice", server.AuthJWT)))
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+
+		for name, md := range map[string]metadata.MD{
+			"none":            {},
+			"unknown key":     metadata.Pairs(server.APIKeyHeader, "unknown"),
+			"not bearer":      metadata.Pairs(server.AuthorizationHeader, "Basic YWxpY2U6c2VjcmV0"),
+			"malformed":       metadata.Pairs(server.AuthorizationHeader, "Bearer abc"),
+			"expired":         metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+			"no expiry":       metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"exp": nil})),
+			"no subject":      metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"sub": ""})),
+			"wrong issuer":    metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"iss": "https://evil.example.com"})),
+			"wrong audience":  metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", rsaKey, jwt.MapClaims{"aud": "other"})),
+			"wrong signature": metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "rsa", otherKey, nil)),
+			"unknown kid":     metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "other", rsaKey, nil)),
+			"encryption key":  metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodRS256, "enc", rsaKey, nil)),
+			"wrong key type":  metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodES256, "rsa", ecKey, nil)),
+			// A public key must not be accepted as an HMAC secret.
+			"hmac": metadata.Pairs(server.AuthorizationHeader, token(jwt.SigningMethodHS256, "rsa", rsaKey.N.Bytes(), nil)),
+		} {
+			_, err := call(md)
+			assert.Equal(t, codes.Unauthenticated, status.Code(err), name)
+		}
+	})
+
+	t.Run("claims override the service limits", func(t *testing.T) {
+		s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), service.NewService(10, 1, 100, 100, 1000), logrus.New())
+
+		fibonacci := func(md metadata.MD) error {
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			_, err := auth.UnaryInterceptor()(ctx, &api.FibonacciRequest{N: 300}, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req any) (any, error) {
+					return s.Fibonacci(ctx, req.(*api.FibonacciRequest))
+				})
+
+			return err
+		}
+
+		assert.NoError(t, fibonacci(metadata.Pairs(server.APIKeyHeader, "acme-key")))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(fibonacci(metadata.Pairs(server.APIKeyHeader, "globex-key"))))
+	})
+
+	t.Run("streams", func(t *testing.T) {
+		stream := &fakeStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.APIKeyHeader, "acme-key"))}
+
+		var id server.Identity
+		err := auth.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+			id, _ = server.IdentityFromContext(ss.Context())
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", id.Name)
+
+		stream = &fakeStream{ctx: context.Background()}
+		err = auth.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+			return nil
+		})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), service.NewService(10, 1, 100, 100, 1000), logrus.New())
+		router := mux.NewRouter()
+		server.NewGateway(s, server.WithGatewayAuth(auth)).Register(router)
+
+		rec := serve(router, "/v1/fibonacci?n=5")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/fibonacci?n=300", nil)
+		req.Header.Set("X-Api-Key", "acme-key")
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/v1/fibonacci?n=5", nil)
+		req.Header.Set("Authorization", token(jwt.SigningMethodRS256, "rsa", rsaKey, nil))
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"values":["0","1","1","2","3"]}`, rec.Body.String())
+	})
+
+	t.Run("limits apply to the identity", func(t *testing.T) {
+		limiter, err := server.NewLimiter(server.LimitsConfig{
+			DefaultTier: "free",
+			Tiers:       map[string]server.Tier{"free": {Rate: 0.001, Burst: 1}, "pro": {}},
+		})
+		assert.NoError(t, err)
+
+		chain := func(key string) error {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(server.APIKeyHeader, key))
+			_, err := auth.UnaryInterceptor()(ctx, &api.FibonacciRequest{}, &grpc.UnaryServerInfo{},
+				func(ctx context.Context, req any) (any, error) {
+					return limiter.UnaryInterceptor()(ctx, req, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+						return &api.FibonacciResponse{}, nil
+					})
+				})
+
+			return err
+		}
+
+		// acme has the pro tier from its key, globex the default tier.
+		assert.NoError(t, chain("acme-key"))
+		assert.NoError(t, chain("acme-key"))
+		assert.NoError(t, chain("globex-key"))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(chain("globex-key")))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		for _, cfg := range []server.AuthConfig{
+			{},
+			{APIKeys: []server.APIKey{{Name: "a", SHA256: "abc"}}},
+			{APIKeys: []server.APIKey{{SHA256: hash("k")}}},
+			{APIKeys: []server.APIKey{{Name: "a", SHA256: hash("k")}, {Name: "b", SHA256: hash("k")}}},
+			{JWT: &server.JWTConfig{JWKS: filepath.Join(t.TempDir(), "missing.json")}},
+		} {
+			_, err := server.NewAuthenticator(cfg, logrus.New())
+			assert.Error(t, err, "%+v", cfg)
+		}
+	})
+
+	t.Run("load config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "auth.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(`
+api_keys:
+  - {name: acme, sha256: "`+hash("acme-key")+`", tier: pro, n_limit: 500}
+jwt:
+  jwks: `+jwksPath+`
+  issuer: https://auth.example.com
+`), 0o644))
+
+		cfg, err := server.LoadAuth(path)
+		assert.NoError(t, err)
+		assert.Equal(t, server.AuthConfig{
+			APIKeys: []server.APIKey{{Name: "acme", SHA256: hash("acme-key"), Tier: "pro", NLimit: 500}},
+			JWT:     &server.JWTConfig{JWKS: jwksPath, Issuer: "https://auth.example.com"},
+		}, cfg)
+
+		_, err = server.NewAuthenticator(cfg, logrus.New())
+		assert.NoError(t, err)
+	})
+
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"strconv"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FibonacciBatch answers many range and nth-term requests at once. Items that fail carry their
+// error in place of a result, so only errors affecting the whole batch fail the call.
+func (s *FibonacciServer) FibonacciBatch(ctx context.Context, req *api.FibonacciBatchRequest) (*api.FibonacciBatchResponse, error) {
+	ctx, c := s.begin(ctx, "FibonacciBatch")
+	defer c.end()
+
+	c.started(logrus.Fields{"items": len(req.GetItems())})
+
+	ctx, cancel := MergeContexts(ctx, s.globalCtx)
+	defer cancel()
+
+	items := make([]domain.BatchItem, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		switch r := item.GetRequest().(type) {
+		case *api.BatchItem_Range:
+			rangeReq := fibonacciRequest(r.Range)
+			items[i].Range = &rangeReq
+		case *api.BatchItem_Nth:
+			nthReq := nthRequest(r.Nth)
+			items[i].Nth = &nthReq
+		}
+	}
+
+	results, err := s.service.GetFibonacciBatch(ctx, items)
+
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	res := &api.FibonacciBatchResponse{Results: make([]*api.BatchResult, len(results))}
+	for i, result := range results {
+		item := req.GetItems()[i]
+
+		key := item.GetKey()
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+
+		out := &api.BatchResult{Key: key}
+
+		switch {
+		case result.Err != nil:
+			st := errorStatus(s.globalCtx, result.Err).Proto()
+			out.Result = &api.BatchResult_Error{Error: &api.BatchError{
+				Code:    st.GetCode(),
+				Message: st.GetMessage(),
+				Details: st.GetDetails(),
+			}}
+		case item.GetNth() != nil:
+			out.Result = &api.BatchResult_Nth{Nth: &api.FibonacciNthResponse{N: item.GetNth().GetN(), Value: result.Value}}
+		default:
+			out.Result = &api.BatchResult_Range{Range: &api.FibonacciResponse{Values: result.Values}}
+		}
+
+		res.Results[i] = out
+	}
+
+	c.emit(res)
+
+	return res, nil
+}
@@ -0,0 +1,74 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFibonacciServer_FibonacciBatch(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+
+		mockService.EXPECT().
+			GetFibonacciBatch(mock.Anything, []domain.BatchItem{
+				{Range: &domain.FibonacciRequest{Start: 5, End: 8}},
+				{Nth: &domain.FibonacciNthRequest{N: 10, Modulus: 7}},
+				{Range: &domain.FibonacciRequest{End: 101}},
+				{},
+			}).
+			Return([]domain.BatchResult{
+				{Values: []string{"5", "8", "13"}},
+				{Value: "6"},
+				{Err: domain.NewFieldError("n", fmt.Errorf("%w: must not exceed 100", domain.ErrTooLargeN))},
+				{Err: domain.ErrInvalidBatchItem},
+			}, nil)
+
+		res, err := s.FibonacciBatch(context.Background(), &api.FibonacciBatchRequest{Items: []*api.BatchItem{
+			{Key: "range", Request: &api.BatchItem_Range{Range: &api.FibonacciRequest{Start: 5, End: proto.Int32(8)}}},
+			{Key: "nth", Request: &api.BatchItem_Nth{Nth: &api.FibonacciNthRequest{N: 10, Modulus: 7}}},
+			{Request: &api.BatchItem_Range{Range: &api.FibonacciRequest{N: 101}}},
+			{},
+		}})
+
+		assert.NoError(t, err)
+		assert.Len(t, res.GetResults(), 4)
+
+		assert.Equal(t, "range", res.GetResults()[0].GetKey())
+		assert.Equal(t, []string{"5", "8", "13"}, res.GetResults()[0].GetRange().GetValues())
+
+		assert.Equal(t, "nth", res.GetResults()[1].GetKey())
+		assert.Equal(t, int64(10), res.GetResults()[1].GetNth().GetN())
+		assert.Equal(t, "6", res.GetResults()[1].GetNth().GetValue())
+
+		batchErr := res.GetResults()[2].GetError()
+		assert.Equal(t, "2", res.GetResults()[2].GetKey())
+		assert.Equal(t, int32(codes.ResourceExhausted), batchErr.GetCode())
+		assert.Len(t, batchErr.GetDetails(), 1)
+
+		badRequest := &errdetails.BadRequest{}
+		assert.NoError(t, batchErr.GetDetails()[0].UnmarshalTo(badRequest))
+		assert.Equal(t, "n", badRequest.GetFieldViolations()[0].GetField())
+
+		assert.Equal(t, int32(codes.InvalidArgument), res.GetResults()[3].GetError().GetCode())
+	})
+
+	t.Run("batch too large", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+
+		mockService.EXPECT().GetFibonacciBatch(mock.Anything, mock.Anything).Return(nil, domain.ErrTooLargeBatch)
+
+		_, err := s.FibonacciBatch(context.Background(), &api.FibonacciBatchRequest{})
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
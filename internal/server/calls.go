@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/metrics"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// Attributes of the handler spans: the gRPC status code a handler failed with, and the authenticated caller,
+// the name of its API key or the subject of its JWT, and how it authenticated.
+const (
+	attrStatusCode = attribute.Key("rpc.grpc.status_code")
+	attrClient     = attribute.Key("enduser.id")
+	attrAuth       = attribute.Key("fibonacci.auth")
+)
+
+// streamCancelReasons labels the streams canceled by the status they end with.
+var streamCancelReasons = map[codes.Code]string{
+	codes.Canceled:         "client",
+	codes.DeadlineExceeded: "deadline",
+	codes.Unavailable:      "shutdown", // See errorStatus
+}
+
+// call tracks a handler invocation in the traces and the metrics: its span, whether it is in flight,
+// what it returns and how it fails.
+type call struct {
+	s      *FibonacciServer
+	method string
+	stream bool
+	span   trace.Span
+	logger logrus.FieldLogger
+}
+
+// begin starts tracking a unary call of method and returns the context of its span. The call must be ended.
+func (s *FibonacciServer) begin(ctx context.Context, method string) (context.Context, *call) {
+	ctx, span := tracer.Start(ctx, "FibonacciServer/"+method, trace.WithSpanKind(trace.SpanKindServer))
+	if id, ok := IdentityFromContext(ctx); ok {
+		span.SetAttributes(attrClient.String(id.Name), attrAuth.String(id.Method))
+	}
+	metrics.RequestsInFlight.WithLabelValues(method).Inc()
+
+	return ctx, &call{s: s, method: method, span: span, logger: s.log(ctx)}
+}
+
+// beginStream starts tracking a streaming call of method, see begin.
+func (s *FibonacciServer) beginStream(ctx context.Context, method string) (context.Context, *call) {
+	ctx, c := s.begin(ctx, method)
+	c.stream = true
+
+	return ctx, c
+}
+
+// started logs the parameters of the call at debug level, as the completed call is logged w/ them as well.
+func (c *call) started(fields logrus.Fields) {
+	c.logger.WithFields(fields).Debugf("%s called", c.method)
+}
+
+// end stops tracking the call.
+func (c *call) end() {
+	metrics.RequestsInFlight.WithLabelValues(c.method).Dec()
+	c.span.End()
+}
+
+// fail converts a service error like statusError, recording the status of the call.
+// Client errors are recorded but do not fail the span, as the server worked as intended.
+func (c *call) fail(err error) error {
+	st := errorStatus(c.s.globalCtx, err)
+
+	c.span.RecordError(err)
+	c.span.SetAttributes(attrStatusCode.Int(int(st.Code())))
+
+	switch st.Code() {
+	case codes.Internal, codes.Unavailable, codes.Unknown, codes.DeadlineExceeded:
+		c.span.SetStatus(otelcodes.Error, st.Message())
+	}
+
+	logger := c.logger.WithError(err).WithField("code", st.Code().String())
+	if outcome(st.Code()) == outcomeServerError {
+		logger.Errorf("%s failed", c.method)
+	} else {
+		logger.Debugf("%s failed", c.method)
+	}
+
+	metrics.ErrorsTotal.WithLabelValues(c.method, st.Code().String()).Inc()
+	if reason, ok := streamCancelReasons[st.Code()]; ok && c.stream {
+		metrics.StreamsCanceledTotal.WithLabelValues(c.method, reason).Inc()
+	}
+
+	return st.Err()
+}
+
+// emit counts a response or chunk returned by the call.
+func (c *call) emit(m proto.Message) {
+	metrics.EmittedDigitsTotal.WithLabelValues(c.method).Add(float64(responseDigits(m)))
+	metrics.EmittedBytesTotal.WithLabelValues(c.method).Add(float64(proto.Size(m)))
+}
+
+// sender returns send counting the chunks it sends.
+func (c *call) sender(send func(*api.FibonacciChunk) error) func(*api.FibonacciChunk) error {
+	return func(chunk *api.FibonacciChunk) error {
+		if err := send(chunk); err != nil {
+			return err
+		}
+
+		c.emit(chunk)
+
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/metrics"
+	internalMock "fibonacci/internal/mock"
+	"fibonacci/internal/server"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCallMetrics(t *testing.T) {
+	// The metrics are global, so the tests compare them before and after each call.
+	t.Run("emitted digits and bytes", func(t *testing.T) {
+		mockService := internalMock.NewService(t)
+		s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), mockService, logrus.New())
+
+		digits := metrics.EmittedDigitsTotal.WithLabelValues("Fibonacci")
+		bytes := metrics.EmittedBytesTotal.WithLabelValues("Fibonacci")
+		inFlight := metrics.RequestsInFlight.WithLabelValues("Fibonacci")
+		digitsBefore, bytesBefore := testutil.ToFloat64(digits), testutil.ToFloat64(bytes)
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, mock.Anything).
+			RunAndReturn(func(context.Context, domain.FibonacciRequest) ([]string, error) {
+				assert.Equal(t, 1.0, testutil.ToFloat64(inFlight))
+				return []string{"55", "89", "144"}, nil
+			})
+
+		res, err := s.Fibonacci(context.Background(), &api.FibonacciRequest{Start: 10, End: proto.Int32(13)})
+		assert.NoError(t, err)
+
+		assert.Equal(t, 7.0, testutil.ToFloat64(digits)-digitsBefore)
+		assert.Equal(t, float64(proto.Size(res)), testutil.ToFloat64(bytes)-bytesBefore)
+		assert.Equal(t, 0.0, testutil.ToFloat64(inFlight))
+	})
+
+	t.Run("errors by code", func(t *testing.T) {
+		mockService := internalMock.NewService(t)
+		s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), mockService, logrus.New())
+
+		errs := metrics.ErrorsTotal.WithLabelValues("FibonacciNth", "ResourceExhausted")
+		before := testutil.ToFloat64(errs)
+
+		mockService.EXPECT().GetNth(mock.Anything, mock.Anything).Return("", domain.ErrTooManyDigits)
+
+		_, err := s.FibonacciNth(context.Background(), &api.FibonacciNthRequest{N: 1_000_000})
+		assert.Error(t, err)
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(errs)-before)
+	})
+
+	t.Run("canceled streams", func(t *testing.T) {
+		globalCtx, shutdown := context.WithCancel(context.Background())
+		defer shutdown()
+
+		mockService := internalMock.NewService(t)
+		s := server.NewFibonacciServer(globalCtx, grpc.NewServer(), mockService, logrus.New())
+
+		client := metrics.StreamsCanceledTotal.WithLabelValues("FibonacciStream", "client")
+		shutdowns := metrics.StreamsCanceledTotal.WithLabelValues("FibonacciStream", "shutdown")
+		clientBefore, shutdownsBefore := testutil.ToFloat64(client), testutil.ToFloat64(shutdowns)
+
+		mockService.EXPECT().GetFibonacciStream(mock.Anything, mock.Anything).Return(domain.ErrContextCanceled)
+
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+		stream.EXPECT().Context().Return(context.Background())
+
+		req := &api.FibonacciStreamRequest{N: 10, ChunkSize: 5}
+		assert.Error(t, s.FibonacciStream(req, stream))
+
+		shutdown()
+		assert.Error(t, s.FibonacciStream(req, stream))
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(client)-clientBefore)
+		assert.Equal(t, 1.0, testutil.ToFloat64(shutdowns)-shutdownsBefore)
+	})
+}
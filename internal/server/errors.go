@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+
+	"fibonacci/internal/domain"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// validationErrors are rejected with InvalidArgument: retrying the same request cannot succeed.
+var validationErrors = []error{
+	domain.ErrInvalidChunkSize,
+	domain.ErrInvalidRange,
+	domain.ErrInvalidModulus,
+	domain.ErrInvalidSequence,
+	domain.ErrUndefinedIndex,
+	domain.ErrInvalidToken,
+	domain.ErrInvalidCommand,
+	domain.ErrInvalidBatchItem,
+	errInvalidQuery,
+}
+
+// limitErrors are rejected with ResourceExhausted: the request is valid but exceeds what the server allows.
+var limitErrors = []error{
+	domain.ErrTooLargeN,
+	domain.ErrTooManyDigits,
+	domain.ErrTooLargeResponse,
+	domain.ErrTooLargeBatch,
+	domain.ErrJobQueueFull,
+}
+
+// statusError converts a service error into a gRPC status error with a canonical code.
+// Errors caused by a request field carry an errdetails.BadRequest naming the field and its limits.
+func (s *FibonacciServer) statusError(err error) error {
+	return errorStatus(s.globalCtx, err).Err()
+}
+
+// errorStatus returns the status reported for a service error.
+//
+// Cancellation is reported as Unavailable when it comes from the server shutting down,
+// which clients may retry against another instance, and as Canceled otherwise.
+func errorStatus(globalCtx context.Context, err error) *status.Status {
+	var code codes.Code
+
+	switch {
+	case isAny(err, validationErrors):
+		code = codes.InvalidArgument
+	case isAny(err, limitErrors):
+		code = codes.ResourceExhausted
+	case errors.Is(err, domain.ErrJobNotFound):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrJobNotReady):
+		return status.New(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrJobsDisabled):
+		return status.New(codes.Unimplemented, err.Error())
+	case errors.Is(err, domain.ErrContextCanceled), errors.Is(err, context.Canceled):
+		if globalCtx.Err() != nil {
+			return status.Newf(codes.Unavailable, "service is shutting down: %s", err)
+		}
+
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	default:
+		// Errors that already are statuses, e.g. from a failed stream send, keep their code.
+		if st, ok := status.FromError(err); ok {
+			return st
+		}
+
+		return status.New(codes.Internal, err.Error())
+	}
+
+	st := status.New(code, err.Error())
+
+	var fieldErr *domain.FieldError
+	if !errors.As(err, &fieldErr) {
+		return st
+	}
+
+	detailed, detailsErr := st.WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Err.Error(),
+		}},
+	})
+	if detailsErr != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// isAny reports whether err matches any of targets.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
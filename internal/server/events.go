@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// wsWriteTimeout bounds how long a WebSocket write may block on a slow client.
+const wsWriteTimeout = 10 * time.Second
+
+// sseFormat frames chunks as Server-Sent Events: a "chunk" event per chunk, identified by its index,
+// followed by an "end" event, or an "error" event with the status if generation fails.
+// Clients should close the EventSource on either, as it would otherwise reconnect.
+var sseFormat = streamFormat{
+	contentType: "text/event-stream",
+	result: func(w io.Writer, chunk *api.FibonacciChunk, data []byte) error {
+		_, err := fmt.Fprintf(w, "id: %d\nevent: chunk\ndata: %s\n\n", chunk.GetIndex(), data)
+		return err
+	},
+	error: func(w io.Writer, data []byte) error {
+		_, err := fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+		return err
+	},
+	end: func(w io.Writer) error {
+		_, err := fmt.Fprint(w, "event: end\ndata: {}\n\n")
+		return err
+	},
+}
+
+// fibonacciEvents streams chunks as Server-Sent Events.
+func (g *Gateway) fibonacciEvents(w http.ResponseWriter, r *http.Request) {
+	g.writeStream(w, r, sseFormat)
+}
+
+// fibonacciWebSocket streams chunks over a WebSocket, one {"result": chunk} text message per chunk.
+//
+// The connection is upgraded when the first chunk is ready, so invalid requests are rejected
+// with a plain HTTP error just like the other transports. Once upgraded, a failure is sent as an
+// {"error": status} message before the connection is closed with a matching close code.
+// Clients send nothing; closing the connection cancels the generation.
+func (g *Gateway) fibonacciWebSocket(w http.ResponseWriter, r *http.Request) {
+	req := &api.FibonacciStreamRequest{}
+	if err := populateQuery(req, r.URL.Query()); err != nil {
+		g.writeError(w, g.server.statusError(err))
+
+		return
+	}
+
+	if !websocket.IsWebSocketUpgrade(r) {
+		g.writeError(w, status.Error(codes.InvalidArgument, "websocket upgrade required"))
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	var (
+		conn          *websocket.Conn
+		upgradeFailed bool
+		wg            sync.WaitGroup
+	)
+
+	defer func() {
+		if conn != nil {
+			conn.Close()
+			wg.Wait()
+		}
+	}()
+
+	upgrade := func() error {
+		var err error
+		if conn, err = g.upgrader.Upgrade(w, r, nil); err != nil {
+			// The upgrader has already answered the request.
+			upgradeFailed = true
+
+			return err
+		}
+
+		// The hijacked connection is no longer watched by the HTTP server,
+		// so reading is what notices the client going away.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer cancel()
+
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+
+		return nil
+	}
+
+	write := func(kind string, data []byte) error {
+		if err := conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+			return err
+		}
+
+		return conn.WriteMessage(websocket.TextMessage, fmt.Appendf(nil, "{%q:%s}", kind, data))
+	}
+
+	send := func(chunk *api.FibonacciChunk) error {
+		data, err := jsonOptions.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+
+		if conn == nil {
+			if err := upgrade(); err != nil {
+				return err
+			}
+		}
+
+		return write("result", data)
+	}
+
+	err := g.server.streamChunks(ctx, req, limitSend(ctx, send))
+	if conn == nil {
+		if err != nil {
+			if !upgradeFailed {
+				g.writeError(w, err)
+			}
+
+			return
+		}
+
+		// An empty range produces no chunks.
+		if err := upgrade(); err != nil {
+			return
+		}
+	}
+
+	closeCode, reason := websocket.CloseNormalClosure, ""
+	if err != nil {
+		st := status.Convert(err)
+		closeCode, reason = wsCloseCode(st.Code()), st.Message()
+
+		if data, marshalErr := jsonOptions.Marshal(st.Proto()); marshalErr == nil {
+			if writeErr := write("error", data); writeErr != nil {
+				g.server.log(ctx).WithError(writeErr).Warn("Failed to write stream error")
+			}
+		}
+	}
+
+	// Close reasons are limited to 123 bytes.
+	if len(reason) > 123 {
+		reason = reason[:123]
+	}
+
+	msg := websocket.FormatCloseMessage(closeCode, reason)
+	if err := conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout)); err != nil && ctx.Err() == nil {
+		g.server.log(ctx).WithError(err).Warn("Failed to close websocket")
+	}
+}
+
+// wsCloseCode maps a gRPC code to the WebSocket close code reported with it.
+func wsCloseCode(code codes.Code) int {
+	switch code {
+	case codes.OK, codes.Canceled:
+		return websocket.CloseNormalClosure
+	case codes.InvalidArgument, codes.ResourceExhausted, codes.Unauthenticated, codes.PermissionDenied:
+		return websocket.ClosePolicyViolation
+	case codes.Unavailable:
+		return websocket.CloseGoingAway
+	case codes.DeadlineExceeded:
+		return websocket.CloseTryAgainLater
+	default:
+		return websocket.CloseInternalServerErr
+	}
+}
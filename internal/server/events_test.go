@@ -0,0 +1,211 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"fibonacci/internal/domain"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGateway_FibonacciEvents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0", "1", "1"}, ContinuationToken: "t3"}); err != nil {
+					return err
+				}
+
+				return r.SendFunc(domain.FibonacciChunk{Index: 3, Values: []string{"2", "3"}})
+			})
+
+		rec := serve(router, "/v1/fibonacci/events?n=5&chunk_size=3")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+		events := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n")
+		assert.Len(t, events, 3)
+
+		for i, want := range []struct{ id, event, data string }{
+			{"0", "chunk", `{"index":0,"values":["0","1","1"],"continuation_token":"t3"}`},
+			{"3", "chunk", `{"index":3,"values":["2","3"],"continuation_token":""}`},
+			{"", "end", `{}`},
+		} {
+			lines := strings.Split(events[i], "\n")
+			if want.id != "" {
+				assert.Equal(t, "id: "+want.id, lines[0])
+				lines = lines[1:]
+			}
+
+			assert.Equal(t, "event: "+want.event, lines[0])
+			assert.JSONEq(t, want.data, strings.TrimPrefix(lines[1], "data: "))
+		}
+	})
+
+	t.Run("error mid-stream", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0"}}); err != nil {
+					return err
+				}
+
+				return context.DeadlineExceeded
+			})
+
+		rec := serve(router, "/v1/fibonacci/events?n=5&chunk_size=1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "event: error\ndata: {")
+		assert.Contains(t, strings.ReplaceAll(rec.Body.String(), " ", ""), `"code":4,`)
+		assert.NotContains(t, rec.Body.String(), "event: end")
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().GetFibonacciStream(mock.Anything, mock.Anything).Return(domain.ErrInvalidRange)
+
+		rec := serve(router, "/v1/fibonacci/events?n=-5&chunk_size=5")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	})
+}
+
+func TestGateway_FibonacciWebSocket(t *testing.T) {
+	dial := func(t *testing.T, srv *httptest.Server, query string) (*websocket.Conn, *http.Response, error) {
+		url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/v1/fibonacci/ws?" + query
+
+		conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+		}
+
+		return conn, resp, err
+	}
+
+	t.Run("success", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+		srv := httptest.NewServer(router)
+		defer srv.Close()
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0", "1", "1"}, ContinuationToken: "t3"}); err != nil {
+					return err
+				}
+
+				return r.SendFunc(domain.FibonacciChunk{Index: 3, Values: []string{"2", "3"}})
+			})
+
+		conn, _, err := dial(t, srv, "n=5&chunk_size=3")
+		assert.NoError(t, err)
+
+		_, msg, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"result":{"index":0,"values":["0","1","1"],"continuation_token":"t3"}}`, string(msg))
+
+		_, msg, err = conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"result":{"index":3,"values":["2","3"],"continuation_token":""}}`, string(msg))
+
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), err)
+	})
+
+	t.Run("validation error is rejected before upgrade", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+		srv := httptest.NewServer(router)
+		defer srv.Close()
+
+		mockService.EXPECT().GetFibonacciStream(mock.Anything, mock.Anything).Return(domain.ErrInvalidChunkSize)
+
+		_, resp, err := dial(t, srv, "n=5&chunk_size=500")
+
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("error mid-stream", func(t *testing.T) {
+		globalCtx, globalCancel := context.WithCancel(context.Background())
+		router, mockService := newGateway(t, globalCtx)
+		srv := httptest.NewServer(router)
+		defer srv.Close()
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0"}}); err != nil {
+					return err
+				}
+
+				globalCancel()
+
+				return domain.ErrContextCanceled
+			})
+
+		conn, _, err := dial(t, srv, "n=5&chunk_size=1")
+		assert.NoError(t, err)
+
+		_, _, err = conn.ReadMessage()
+		assert.NoError(t, err)
+
+		_, msg, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Contains(t, strings.ReplaceAll(string(msg), " ", ""), `{"error":{"code":14,`)
+
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+	})
+
+	t.Run("client disconnect cancels generation", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+		srv := httptest.NewServer(router)
+		defer srv.Close()
+
+		done := make(chan error, 1)
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0"}}); err != nil {
+					done <- err
+					return err
+				}
+
+				<-ctx.Done()
+				done <- ctx.Err()
+
+				return domain.ErrContextCanceled
+			})
+
+		conn, _, err := dial(t, srv, "n=100&chunk_size=1")
+		assert.NoError(t, err)
+
+		_, _, err = conn.ReadMessage()
+		assert.NoError(t, err)
+		conn.Close()
+
+		select {
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+		case <-time.After(5 * time.Second):
+			t.Fatal("generation was not canceled")
+		}
+	})
+}
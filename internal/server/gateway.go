@@ -0,0 +1,458 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// errInvalidQuery is returned for query parameters that do not match the request message.
+var errInvalidQuery = errors.New("invalid query parameter")
+
+// jsonOptions encodes messages with the same field names the query parameters use.
+var jsonOptions = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// Gateway serves the Fibonacci API as HTTP/JSON for clients that cannot speak gRPC.
+//
+// Requests are GET requests whose query parameters name the fields of the gRPC request
+// message, nested fields by their dotted path and repeated fields by repetition:
+//
+//	/v1/fibonacci?n=10&sequence.kind=SEQUENCE_KIND_LUCAS
+//	/v1/fibonacci?start=-5&end=5&modulus=7
+//	/v1/fibonacci/stream?n=1000&chunk_size=100
+//
+// Responses are the JSON encoding of the gRPC response messages, and errors the JSON
+// encoding of the gRPC status with the matching HTTP status code.
+//
+// Streams are also available as Server-Sent Events and over a WebSocket for browser clients.
+type Gateway struct {
+	server   *FibonacciServer
+	upgrader websocket.Upgrader
+	auth     *Authenticator // Nil if requests are not authenticated
+	limits   *Limiter       // Nil if requests are not limited
+	recovery bool           // Whether panics of the handlers are turned into Internal errors
+}
+
+// GatewayOption configures optional behavior of the gateway.
+type GatewayOption func(*Gateway)
+
+// WithGatewayAuth requires the same credentials as the gRPC API, passed in the x-api-key or Authorization header.
+func WithGatewayAuth(a *Authenticator) GatewayOption {
+	return func(g *Gateway) {
+		g.auth = a
+	}
+}
+
+// WithGatewayLimits applies the same rate limits and digit quotas as to the gRPC API, see Limiter.
+func WithGatewayLimits(l *Limiter) GatewayOption {
+	return func(g *Gateway) {
+		g.limits = l
+	}
+}
+
+// WithGatewayRecovery turns a panic of a handler into an Internal error, like RecoveryUnaryInterceptor.
+func WithGatewayRecovery() GatewayOption {
+	return func(g *Gateway) {
+		g.recovery = true
+	}
+}
+
+// NewGateway returns a gateway that handles requests with the given server.
+// WebSocket connections are only accepted from the origin of the gateway itself.
+func NewGateway(server *FibonacciServer, opts ...GatewayOption) *Gateway {
+	g := &Gateway{server: server}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g
+}
+
+// Register adds the gateway routes to router.
+func (g *Gateway) Register(router *mux.Router) {
+	v1 := router.PathPrefix("/v1").Methods(http.MethodGet).Subrouter()
+	v1.Use(traceMiddleware, g.requestMiddleware)
+	if g.recovery {
+		v1.Use(g.recoveryMiddleware)
+	}
+	if g.auth != nil {
+		v1.Use(g.auth.middleware(g.writeError))
+	}
+	// The limits run after the authentication, so that they apply to the authenticated identity.
+	if g.limits != nil {
+		v1.Use(g.limits.middleware(g.writeError))
+	}
+
+	v1.Path("/fibonacci").Handler(unary(g, g.server.Fibonacci))
+	v1.Path("/fibonacci/nth").Handler(unary(g, g.server.FibonacciNth))
+	v1.Path("/fibonacci/stream").HandlerFunc(g.fibonacciStream)
+	v1.Path("/fibonacci/events").HandlerFunc(g.fibonacciEvents)
+	v1.Path("/fibonacci/ws").HandlerFunc(g.fibonacciWebSocket)
+	v1.Path("/pisano-period").Handler(unary(g, g.server.PisanoPeriod))
+}
+
+// requestMiddleware gives every request a request-scoped logger, like LoggingUnaryInterceptor does for gRPC calls,
+// identified by the X-Request-Id header of the request or a generated ID, which is echoed in the response.
+func (g *Gateway) requestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, entry := requestLogger(r.Context(), g.server.logger, r.Header.Get(logging.RequestIDHeader), r.URL.Path, r.RemoteAddr)
+		w.Header().Set(logging.RequestIDHeader, entry.Data["request_id"].(string))
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// recoveryMiddleware writes an Internal error when a handler panics, see recovered. Panics with http.ErrAbortHandler
+// are left to the HTTP server, which aborts the response.
+func (g *Gateway) recoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			g.writeError(w, recovered(g.server.logger, r.URL.Path, p))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// unary returns a handler that decodes the request message from the query, calls the gRPC
+// handler and writes its response.
+func unary[Req, Res proto.Message](g *Gateway, call func(context.Context, Req) (Res, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Req
+		req = req.ProtoReflect().New().Interface().(Req)
+
+		if err := populateQuery(req, r.URL.Query()); err != nil {
+			g.writeError(w, g.server.statusError(err))
+
+			return
+		}
+
+		res, err := call(r.Context(), req)
+		if err != nil {
+			g.writeError(w, err)
+
+			return
+		}
+
+		chargeResponse(r.Context(), res)
+		g.writeMessage(w, http.StatusOK, res)
+	})
+}
+
+// streamFormat frames the chunks of a streaming HTTP response.
+type streamFormat struct {
+	contentType string
+	result      func(w io.Writer, chunk *api.FibonacciChunk, data []byte) error // Writes an encoded chunk
+	error       func(w io.Writer, data []byte) error                            // Writes an encoded status that ends the stream
+	end         func(w io.Writer) error                                         // Marks a successful end, if the format needs it
+}
+
+// ndjsonFormat writes one {"result": chunk} object per line and a final {"error": status} line on failure.
+var ndjsonFormat = streamFormat{
+	contentType: "application/x-ndjson",
+	result: func(w io.Writer, _ *api.FibonacciChunk, data []byte) error {
+		_, err := fmt.Fprintf(w, "{\"result\":%s}\n", data)
+		return err
+	},
+	error: func(w io.Writer, data []byte) error {
+		_, err := fmt.Fprintf(w, "{\"error\":%s}\n", data)
+		return err
+	},
+}
+
+// fibonacciStream streams chunks as newline-delimited JSON.
+func (g *Gateway) fibonacciStream(w http.ResponseWriter, r *http.Request) {
+	g.writeStream(w, r, ndjsonFormat)
+}
+
+// writeStream runs the stream request in the query and writes each chunk in format, flushing it
+// as soon as it is generated. An error before the first chunk is reported like a unary error,
+// so validation failures keep their HTTP status code; once the response has started,
+// an error ends the stream in the framing of the format.
+func (g *Gateway) writeStream(w http.ResponseWriter, r *http.Request, format streamFormat) {
+	req := &api.FibonacciStreamRequest{}
+	if err := populateQuery(req, r.URL.Query()); err != nil {
+		g.writeError(w, g.server.statusError(err))
+
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	started := false
+
+	start := func() {
+		w.Header().Set("Content-Type", format.contentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	send := func(chunk *api.FibonacciChunk) error {
+		data, err := jsonOptions.Marshal(chunk)
+		if err != nil {
+			return err
+		}
+
+		if !started {
+			start()
+		}
+
+		if err := format.result(w, chunk, data); err != nil {
+			return err
+		}
+
+		return rc.Flush()
+	}
+
+	err := g.server.streamChunks(r.Context(), req, limitSend(r.Context(), send))
+	if err != nil && !started {
+		g.writeError(w, err)
+
+		return
+	}
+
+	if !started {
+		// An empty range produces no chunks.
+		start()
+	}
+
+	if err == nil {
+		if format.end != nil {
+			err = format.end(w)
+		}
+	} else {
+		var data []byte
+		if data, err = jsonOptions.Marshal(status.Convert(err).Proto()); err == nil {
+			err = format.error(w, data)
+		}
+	}
+
+	if err != nil {
+		g.server.log(r.Context()).WithError(err).Warn("Failed to end stream")
+	}
+}
+
+// writeError writes a gRPC status error as JSON with the matching HTTP status code.
+func (g *Gateway) writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+
+	g.writeMessage(w, httpStatus(st.Code()), st.Proto())
+}
+
+func (g *Gateway) writeMessage(w http.ResponseWriter, code int, msg proto.Message) {
+	data, err := jsonOptions.Marshal(msg)
+	if err != nil {
+		g.server.logger.WithError(err).Error("Failed to encode response")
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	if _, err := w.Write(data); err != nil {
+		g.server.logger.WithError(err).Warn("Failed to write response")
+	}
+}
+
+// httpStatus maps a gRPC code to the HTTP status code conventionally used for it.
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// populateQuery sets the fields of msg from query parameters.
+func populateQuery(msg proto.Message, query url.Values) error {
+	for key, values := range query {
+		if err := populateField(msg.ProtoReflect(), strings.Split(key, "."), values); err != nil {
+			return domain.NewFieldError(key, err)
+		}
+	}
+
+	return nil
+}
+
+// populateField sets the field at path in m from values.
+func populateField(m protoreflect.Message, path []string, values []string) error {
+	fields := m.Descriptor().Fields()
+
+	fd := fields.ByName(protoreflect.Name(path[0]))
+	if fd == nil {
+		fd = fields.ByJSONName(path[0])
+	}
+
+	if fd == nil {
+		return fmt.Errorf("%w: unknown field", errInvalidQuery)
+	}
+
+	if len(path) > 1 {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return fmt.Errorf("%w: %s has no fields", errInvalidQuery, fd.Name())
+		}
+
+		return populateField(m.Mutable(fd).Message(), path[1:], values)
+	}
+
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind || fd.IsMap() {
+		return fmt.Errorf("%w: %s must be set field by field", errInvalidQuery, fd.Name())
+	}
+
+	if fd.IsList() {
+		list := m.Mutable(fd).List()
+		for _, value := range values {
+			v, err := parseScalar(fd, value)
+			if err != nil {
+				return err
+			}
+
+			list.Append(v)
+		}
+
+		return nil
+	}
+
+	if len(values) != 1 {
+		return fmt.Errorf("%w: %s must be given once", errInvalidQuery, fd.Name())
+	}
+
+	v, err := parseScalar(fd, values[0])
+	if err != nil {
+		return err
+	}
+
+	m.Set(fd, v)
+
+	return nil
+}
+
+// parseScalar parses a query value of a scalar or enum field.
+func parseScalar(fd protoreflect.FieldDescriptor, value string) (protoreflect.Value, error) {
+	invalid := fmt.Errorf("%w: %q is not a valid %s", errInvalidQuery, value, fd.Kind())
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		return protoreflect.ValueOfBool(b), nil
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		i, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		return protoreflect.ValueOfInt32(int32(i)), nil
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		return protoreflect.ValueOfInt64(i), nil
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		u, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		return protoreflect.ValueOfUint32(uint32(u)), nil
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		u, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		return protoreflect.ValueOfUint64(u), nil
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		if fd.Kind() == protoreflect.FloatKind {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+
+		return protoreflect.ValueOfFloat64(f), nil
+
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(value), nil
+
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(value)), nil
+
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(value)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+
+		i, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || fd.Enum().Values().ByNumber(protoreflect.EnumNumber(i)) == nil {
+			return protoreflect.Value{}, invalid
+		}
+
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(i)), nil
+	}
+
+	return protoreflect.Value{}, fmt.Errorf("%w: unsupported field type %s", errInvalidQuery, fd.Kind())
+}
@@ -0,0 +1,257 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/logging"
+	internalMock "fibonacci/internal/mock"
+	"fibonacci/internal/server"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+)
+
+func newGateway(t *testing.T, globalCtx context.Context) (*mux.Router, *internalMock.Service) {
+	mockService := internalMock.NewService(t)
+	s := server.NewFibonacciServer(globalCtx, grpc.NewServer(), mockService, logrus.New())
+
+	router := mux.NewRouter()
+	server.NewGateway(s).Register(router)
+
+	return router, mockService
+}
+
+func serve(router http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return rec
+}
+
+func TestGateway_Fibonacci(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 5}).Return([]string{"0", "1", "1", "2", "3"}, nil)
+
+		rec := serve(router, "/v1/fibonacci?n=5")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"values":["0","1","1","2","3"]}`, rec.Body.String())
+	})
+
+	t.Run("request ID", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, _ domain.FibonacciRequest) ([]string, error) {
+				entry, ok := logging.FromContext(ctx).(*logrus.Entry)
+				if assert.True(t, ok) {
+					assert.Equal(t, "abc", entry.Data["request_id"])
+					assert.Equal(t, "/v1/fibonacci", entry.Data["method"])
+				}
+
+				return []string{"0"}, nil
+			}).Once()
+		mockService.EXPECT().GetFibonacci(mock.Anything, mock.Anything).Return([]string{"0"}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/v1/fibonacci?n=1", nil)
+		req.Header.Set("X-Request-Id", "abc")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, "abc", rec.Header().Get("X-Request-Id"))
+
+		rec = serve(router, "/v1/fibonacci?n=1")
+		assert.Len(t, rec.Header().Get("X-Request-Id"), 32)
+	})
+
+	t.Run("range and sequence", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: "custom", Seeds: []string{"1", "1"}, Coefficients: []int64{2, 1}},
+			Start:    -2,
+			End:      0,
+			Modulus:  7,
+		}).Return([]string{"4", "6"}, nil)
+
+		rec := serve(router, "/v1/fibonacci?start=-2&end=0&modulus=7"+
+			"&sequence.kind=SEQUENCE_KIND_CUSTOM&sequence.seeds=1&sequence.seeds=1&sequence.coefficients=2&sequence.coefficients=1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"values":["4","6"]}`, rec.Body.String())
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		router, _ := newGateway(t, context.Background())
+
+		for _, target := range []string{
+			"/v1/fibonacci?n=ten",
+			"/v1/fibonacci?n=1&n=2",
+			"/v1/fibonacci?unknown=1",
+			"/v1/fibonacci?sequence=lucas",
+			"/v1/fibonacci?sequence.kind=SEQUENCE_KIND_UNKNOWN",
+		} {
+			rec := serve(router, target)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+			assert.Contains(t, strings.ReplaceAll(rec.Body.String(), " ", ""), `"code":3`, target)
+		}
+	})
+
+	t.Run("field violation", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 101}).
+			Return(nil, domain.NewFieldError("n", fmt.Errorf("%w: must not exceed 100", domain.ErrTooLargeN)))
+
+		rec := serve(router, "/v1/fibonacci?n=101")
+
+		var body struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Details []struct {
+				Type            string `json:"@type"`
+				FieldViolations []struct {
+					Field       string `json:"field"`
+					Description string `json:"description"`
+				} `json:"field_violations"`
+			} `json:"details"`
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, 8, body.Code)
+		assert.Equal(t, "type.googleapis.com/google.rpc.BadRequest", body.Details[0].Type)
+		assert.Equal(t, "n", body.Details[0].FieldViolations[0].Field)
+		assert.Equal(t, "to large n: must not exceed 100", body.Details[0].FieldViolations[0].Description)
+	})
+
+	t.Run("service shut down", func(t *testing.T) {
+		globalCtx, globalCancel := context.WithCancel(context.Background())
+		router, mockService := newGateway(t, globalCtx)
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 10}).Return(nil, domain.ErrContextCanceled)
+		globalCancel()
+
+		rec := serve(router, "/v1/fibonacci?n=10")
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+}
+
+func TestGateway_Recovery(t *testing.T) {
+	mockService := internalMock.NewService(t)
+	s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), mockService, logrus.New())
+
+	router := mux.NewRouter()
+	server.NewGateway(s, server.WithGatewayRecovery()).Register(router)
+
+	mockService.EXPECT().GetFibonacci(mock.Anything, mock.Anything).Run(func(context.Context, domain.FibonacciRequest) {
+		panic("boom")
+	})
+
+	rec := serve(router, "/v1/fibonacci?n=10")
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"code":13,"message":"internal error","details":[]}`, rec.Body.String())
+}
+
+func TestGateway_FibonacciNth(t *testing.T) {
+	router, mockService := newGateway(t, context.Background())
+
+	mockService.EXPECT().GetNth(mock.Anything, domain.FibonacciNthRequest{N: -10}).Return("-55", nil)
+	mockService.EXPECT().GetPisanoPeriod(mock.Anything, uint64(10)).Return(60, nil)
+
+	rec := serve(router, "/v1/fibonacci/nth?n=-10")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"n":"-10","value":"-55"}`, rec.Body.String())
+
+	rec = serve(router, "/v1/pisano-period?modulus=10")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"modulus":"10","period":"60"}`, rec.Body.String())
+}
+
+func TestGateway_FibonacciStream(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				assert.Equal(t, 0, r.Start)
+				assert.Equal(t, 5, r.End)
+				assert.Equal(t, 3, r.ChunkSize)
+
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0", "1", "1"}, ContinuationToken: "t3"}); err != nil {
+					return err
+				}
+
+				return r.SendFunc(domain.FibonacciChunk{Index: 3, Values: []string{"2", "3"}})
+			})
+
+		rec := serve(router, "/v1/fibonacci/stream?n=5&chunk_size=3")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.True(t, rec.Flushed)
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.JSONEq(t, `{"result":{"index":0,"values":["0","1","1"],"continuation_token":"t3"}}`, lines[0])
+		assert.JSONEq(t, `{"result":{"index":3,"values":["2","3"],"continuation_token":""}}`, lines[1])
+	})
+
+	t.Run("error before first chunk", func(t *testing.T) {
+		router, mockService := newGateway(t, context.Background())
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			Return(domain.NewFieldError("chunk_size", fmt.Errorf("%w: must be at least 5", domain.ErrInvalidChunkSize)))
+
+		rec := serve(router, "/v1/fibonacci/stream?n=5&chunk_size=1")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Contains(t, strings.ReplaceAll(rec.Body.String(), " ", ""), `"field":"chunk_size"`)
+	})
+
+	t.Run("error mid-stream", func(t *testing.T) {
+		globalCtx, globalCancel := context.WithCancel(context.Background())
+		router, mockService := newGateway(t, globalCtx)
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				if err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0", "1", "1"}, ContinuationToken: "t3"}); err != nil {
+					return err
+				}
+
+				globalCancel()
+
+				return domain.ErrContextCanceled
+			})
+
+		rec := serve(router, "/v1/fibonacci/stream?n=10&chunk_size=3")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.JSONEq(t, `{"result":{"index":0,"values":["0","1","1"],"continuation_token":"t3"}}`, lines[0])
+		assert.Contains(t, strings.ReplaceAll(lines[1], " ", ""), `{"error":{"code":14,`)
+	})
+}
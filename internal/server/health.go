@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// selfTestN and selfTestValue are the known term the self-test computes, F(100).
+const (
+	selfTestN     = 100
+	selfTestValue = "354224848179261915075"
+)
+
+// isHealthCheck reports whether fullMethod belongs to the health service, which orchestrators call
+// w/o credentials and which is neither authenticated nor limited.
+func isHealthCheck(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+grpc_health_v1.Health_ServiceDesc.ServiceName+"/")
+}
+
+// Health reports whether the server is alive and ready to take calls, over the standard grpc.health.v1
+// service and the /healthz and /readyz HTTP endpoints.
+//
+// The server is ready from the start until Shutdown is called, which orchestrators should see before the
+// graceful stop begins, so that they stop routing new calls to it while the running ones finish.
+// With a self-test, readiness additionally requires a known term to be computed correctly.
+type Health struct {
+	server   *health.Server
+	selfTest func(ctx context.Context) error // Nil if there is no self-test
+	logger   *logrus.Logger
+}
+
+// HealthOption configures optional behavior of the health checks.
+type HealthOption func(*Health)
+
+// WithSelfTest makes readiness depend on fibonacciService computing F(100) correctly, see Health.Watch.
+func WithSelfTest(fibonacciService service.Service) HealthOption {
+	return func(h *Health) {
+		h.selfTest = func(ctx context.Context) error {
+			value, err := fibonacciService.GetNth(ctx, domain.FibonacciNthRequest{N: selfTestN})
+			if err != nil {
+				return err
+			}
+
+			if value != selfTestValue {
+				return fmt.Errorf("F(%d) = %s, expected %s", selfTestN, value, selfTestValue)
+			}
+
+			return nil
+		}
+	}
+}
+
+// NewHealth registers the grpc.health.v1 service on s, reporting the server and the Fibonacci service as serving.
+func NewHealth(s *grpc.Server, logger *logrus.Logger, opts ...HealthOption) *Health {
+	h := &Health{server: health.NewServer(), logger: logger}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	grpc_health_v1.RegisterHealthServer(s, h.server)
+	h.setServing(true)
+
+	return h
+}
+
+// setServing sets the status of the server as a whole and of the Fibonacci service.
+func (h *Health) setServing(serving bool) {
+	status := grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	h.server.SetServingStatus("", status)
+	h.server.SetServingStatus(api.FibonacciService_ServiceDesc.ServiceName, status)
+}
+
+// Shutdown reports the server as not serving, for good: later self-tests no longer change the status.
+func (h *Health) Shutdown() {
+	h.server.Shutdown()
+}
+
+// Ready reports whether the server takes calls.
+func (h *Health) Ready(ctx context.Context) bool {
+	res, err := h.server.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+
+	return err == nil && res.GetStatus() == grpc_health_v1.HealthCheckResponse_SERVING
+}
+
+// Watch runs the self-test, if any, every interval until ctx is done, reporting the server as not serving
+// while it fails. Each run must complete within the interval.
+func (h *Health) Watch(ctx context.Context, interval time.Duration) {
+	if h.selfTest == nil {
+		return
+	}
+
+	h.check(ctx, interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				h.check(ctx, interval)
+			}
+		}
+	}()
+}
+
+// check runs the self-test once and updates the status accordingly.
+func (h *Health) check(ctx context.Context, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := h.selfTest(ctx)
+	if err != nil {
+		h.logger.WithError(err).Error("Self-test failed, reporting not ready")
+	}
+
+	h.setServing(err == nil)
+}
+
+// Register adds /healthz, which answers as long as the process does, and /readyz, which fails once the server
+// stops taking calls, to router.
+func (h *Health) Register(router *mux.Router) {
+	router.Path("/healthz").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, true)
+	})
+	router.Path("/readyz").Methods(http.MethodGet).HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, h.Ready(r.Context()))
+	})
+}
+
+func writeHealth(w http.ResponseWriter, ok bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("not ready\n"))
+
+		return
+	}
+
+	_, _ = w.Write([]byte("ok\n"))
+}
@@ -0,0 +1,88 @@
+package server_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"fibonacci/internal/domain"
+	internalMock "fibonacci/internal/mock"
+	"fibonacci/internal/server"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestHealth(t *testing.T) {
+	t.Run("ready until shutdown", func(t *testing.T) {
+		h := server.NewHealth(grpc.NewServer(), logrus.New())
+		router := mux.NewRouter()
+		h.Register(router)
+
+		assert.True(t, h.Ready(context.Background()))
+		assert.Equal(t, http.StatusOK, serve(router, "/healthz").Code)
+		assert.Equal(t, http.StatusOK, serve(router, "/readyz").Code)
+
+		h.Shutdown()
+
+		assert.False(t, h.Ready(context.Background()))
+		assert.Equal(t, http.StatusOK, serve(router, "/healthz").Code)
+		assert.Equal(t, http.StatusServiceUnavailable, serve(router, "/readyz").Code)
+	})
+
+	t.Run("self-test", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		mockService := internalMock.NewService(t)
+		mockService.EXPECT().GetNth(mock.Anything, domain.FibonacciNthRequest{N: 100}).Return("354224848179261915075", nil).Once()
+
+		h := server.NewHealth(grpc.NewServer(), logrus.New(), server.WithSelfTest(mockService))
+		h.Watch(ctx, time.Hour)
+		assert.True(t, h.Ready(ctx))
+
+		mockService = internalMock.NewService(t)
+		mockService.EXPECT().GetNth(mock.Anything, mock.Anything).Return("354224848179261915076", nil).Once()
+
+		h = server.NewHealth(grpc.NewServer(), logrus.New(), server.WithSelfTest(mockService))
+		h.Watch(ctx, time.Hour)
+		assert.False(t, h.Ready(ctx))
+	})
+
+	t.Run("health checks skip authentication and limits", func(t *testing.T) {
+		auth, err := server.NewAuthenticator(server.AuthConfig{
+			APIKeys: []server.APIKey{{Name: "acme", SHA256: strings.Repeat("00", 32)}},
+		}, logrus.New())
+		assert.NoError(t, err)
+
+		limiter, err := server.NewLimiter(server.LimitsConfig{
+			DefaultTier: "free",
+			Tiers:       map[string]server.Tier{"free": {Rate: 0.001, Burst: 1}},
+		})
+		assert.NoError(t, err)
+
+		info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+		handler := func(context.Context, any) (any, error) {
+			return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+		}
+
+		for range 3 {
+			_, err = auth.UnaryInterceptor()(context.Background(), nil, info, handler)
+			assert.NoError(t, err)
+
+			_, err = limiter.UnaryInterceptor()(context.Background(), nil, info, handler)
+			assert.NoError(t, err)
+		}
+
+		_, err = auth.UnaryInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/api.FibonacciService/Fibonacci"}, handler)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
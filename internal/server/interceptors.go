@@ -0,0 +1,313 @@
+package server
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/logging"
+	"fibonacci/internal/metrics"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Call types of the gRPC server metrics.
+const (
+	unaryCall        = "unary"
+	clientStreamCall = "client_stream"
+	serverStreamCall = "server_stream"
+	bidiStreamCall   = "bidi_stream"
+)
+
+// grpcMethod holds the labels of the gRPC server metrics of a method.
+type grpcMethod struct {
+	kind    string
+	service string
+	name    string
+}
+
+// newGRPCMethod splits a full method name, e.g. /api.FibonacciService/Fibonacci.
+func newGRPCMethod(kind, fullMethod string) grpcMethod {
+	service, name, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return grpcMethod{kind: kind, service: "unknown", name: fullMethod}
+	}
+
+	return grpcMethod{kind: kind, service: service, name: name}
+}
+
+func streamKind(info *grpc.StreamServerInfo) string {
+	switch {
+	case info.IsClientStream && info.IsServerStream:
+		return bidiStreamCall
+	case info.IsClientStream:
+		return clientStreamCall
+	default:
+		return serverStreamCall
+	}
+}
+
+// MetricsUnaryInterceptor counts the unary calls, their status codes and latency, including the calls that fail
+// or are rejected before they reach the handler.
+func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		m := newGRPCMethod(unaryCall, info.FullMethod)
+		start := m.started()
+		metrics.GRPCServerMsgReceivedTotal.WithLabelValues(m.kind, m.service, m.name).Inc()
+
+		res, err := handler(ctx, req)
+		if err == nil {
+			metrics.GRPCServerMsgSentTotal.WithLabelValues(m.kind, m.service, m.name).Inc()
+		}
+
+		m.handled(start, err)
+
+		return res, err
+	}
+}
+
+// MetricsStreamInterceptor counts the streaming calls like MetricsUnaryInterceptor, as well as the messages
+// sent and received on them.
+func MetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		m := newGRPCMethod(streamKind(info), info.FullMethod)
+		start := m.started()
+
+		err := handler(srv, &countingStream{ServerStream: ss, method: m})
+		m.handled(start, err)
+
+		return err
+	}
+}
+
+func (m grpcMethod) started() time.Time {
+	metrics.GRPCServerStartedTotal.WithLabelValues(m.kind, m.service, m.name).Inc()
+
+	return time.Now()
+}
+
+func (m grpcMethod) handled(start time.Time, err error) {
+	metrics.GRPCServerHandledTotal.WithLabelValues(m.kind, m.service, m.name, status.Code(err).String()).Inc()
+	metrics.GRPCServerHandlingSeconds.WithLabelValues(m.kind, m.service, m.name).Observe(time.Since(start).Seconds())
+}
+
+// countingStream counts the messages sent and received on a stream.
+type countingStream struct {
+	grpc.ServerStream
+	method grpcMethod
+}
+
+func (s *countingStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		metrics.GRPCServerMsgSentTotal.WithLabelValues(s.method.kind, s.method.service, s.method.name).Inc()
+	}
+
+	return err
+}
+
+func (s *countingStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		metrics.GRPCServerMsgReceivedTotal.WithLabelValues(s.method.kind, s.method.service, s.method.name).Inc()
+	}
+
+	return err
+}
+
+// RecoveryUnaryInterceptor turns a panic of the handler into an Internal error, logging it w/ its stack,
+// so that a single failing call does not take the whole server down.
+func RecoveryUnaryInterceptor(logger *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				res, err = nil, recovered(logger, info.FullMethod, p)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor turns a panic of a streaming handler into an Internal error, see RecoveryUnaryInterceptor.
+// Panics of goroutines started by the handler cannot be recovered.
+func RecoveryStreamInterceptor(logger *logrus.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(logger, info.FullMethod, p)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs the panic p of a handler of fullMethod and returns the error reported to the client,
+// which does not reveal the cause.
+func recovered(logger *logrus.Logger, fullMethod string, p any) error {
+	logger.WithField("method", fullMethod).Errorf("Recovered from panic: %v\n%s", p, debug.Stack())
+	metrics.PanicsRecoveredTotal.WithLabelValues(newGRPCMethod(unaryCall, fullMethod).name).Inc()
+
+	return status.Error(codes.Internal, "internal error")
+}
+
+// Outcomes of the calls, as logged.
+const (
+	outcomeOK          = "ok"
+	outcomeCanceled    = "canceled"     // The client went away or its deadline expired
+	outcomeClientError = "client_error" // The call was invalid, unauthenticated or over the limits
+	outcomeServerError = "server_error"
+)
+
+// outcome classifies a status code.
+func outcome(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return outcomeOK
+	case codes.Canceled, codes.DeadlineExceeded:
+		return outcomeCanceled
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.PermissionDenied, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated:
+		return outcomeClientError
+	default:
+		return outcomeServerError
+	}
+}
+
+// LoggingUnaryInterceptor gives every call a request-scoped logger, carried by its context, whose lines share
+// the request ID, method and peer of the call, see logging.FromContext. The request ID is taken from the
+// RequestIDHeader metadata if the caller passed one and generated otherwise, and is returned in the response header.
+//
+// Every completed call is logged w/ its parameters, duration and outcome. Successful calls are sampled per method
+// by sampler, so that high call rates do not flood the logs; failures are always logged.
+func LoggingUnaryInterceptor(logger *logrus.Logger, sampler *logging.Sampler) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, entry, header := grpcRequestLogger(ctx, logger, info.FullMethod)
+		_ = grpc.SetHeader(ctx, header)
+
+		start := time.Now()
+		res, err := handler(ctx, req)
+		logCall(entry.WithFields(requestFields(req)), sampler, info.FullMethod, start, err)
+
+		return res, err
+	}
+}
+
+// LoggingStreamInterceptor logs streaming calls like LoggingUnaryInterceptor, taking the parameters of the call
+// from the first message the client sends.
+func LoggingStreamInterceptor(logger *logrus.Logger, sampler *logging.Sampler) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, entry, header := grpcRequestLogger(ss.Context(), logger, info.FullMethod)
+		_ = ss.SetHeader(header)
+
+		stream := &loggedStream{ServerStream: ss, ctx: ctx}
+
+		start := time.Now()
+		err := handler(srv, stream)
+		logCall(entry.WithFields(stream.fields), sampler, info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+// requestLogger returns ctx carrying the request-scoped logger of a call of method from peer, and the logger.
+// The call is identified by requestID, or by a generated ID if it is empty.
+func requestLogger(ctx context.Context, logger *logrus.Logger, requestID, method, peer string) (context.Context, *logrus.Entry) {
+	if requestID == "" {
+		requestID = logging.NewRequestID()
+	}
+
+	fields := logrus.Fields{"request_id": requestID, "method": method}
+	if peer != "" {
+		fields["peer"] = peer
+	}
+
+	entry := logger.WithFields(fields)
+
+	return logging.NewContext(ctx, entry), entry
+}
+
+// grpcRequestLogger returns the request-scoped logger of a gRPC call, see requestLogger, and its request ID,
+// which is sent back in the response header.
+func grpcRequestLogger(ctx context.Context, logger *logrus.Logger, fullMethod string) (context.Context, *logrus.Entry, metadata.MD) {
+	var requestID, addr string
+	if ids := metadata.ValueFromIncomingContext(ctx, logging.RequestIDHeader); len(ids) > 0 {
+		requestID = ids[0]
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+
+	ctx, entry := requestLogger(ctx, logger, requestID, fullMethod, addr)
+
+	return ctx, entry, metadata.Pairs(logging.RequestIDHeader, entry.Data["request_id"].(string))
+}
+
+// loggedStream carries the request-scoped logger of a stream and records the parameters of its first message.
+type loggedStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	fields logrus.Fields
+}
+
+func (s *loggedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *loggedStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.fields == nil {
+		s.fields = requestFields(m)
+	}
+
+	return err
+}
+
+// requestFields returns the size parameters of a request message that are logged, n and chunk_size.
+func requestFields(req any) logrus.Fields {
+	if flow, ok := req.(*api.FibonacciFlowRequest); ok {
+		req = flow.GetStart()
+	}
+
+	fields := logrus.Fields{}
+	if r, ok := req.(interface{ GetN() int32 }); ok {
+		fields["n"] = r.GetN()
+	}
+
+	if r, ok := req.(interface{ GetChunkSize() int32 }); ok {
+		fields["chunk_size"] = r.GetChunkSize()
+	}
+
+	return fields
+}
+
+// logCall logs a completed call at a level depending on its outcome: server errors point to a fault of the server,
+// while the other failures point to the client.
+func logCall(entry *logrus.Entry, sampler *logging.Sampler, fullMethod string, start time.Time, err error) {
+	code := status.Code(err)
+	entry = entry.WithFields(logrus.Fields{
+		"code":        code.String(),
+		"outcome":     outcome(code),
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	})
+
+	switch outcome(code) {
+	case outcomeOK:
+		if sampler.Allow(fullMethod) {
+			entry.Info("Call completed")
+		}
+	case outcomeServerError:
+		entry.WithError(err).Error("Call failed")
+	default:
+		entry.WithError(err).Warn("Call failed")
+	}
+}
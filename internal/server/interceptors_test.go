@@ -0,0 +1,149 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/logging"
+	"fibonacci/internal/metrics"
+	"fibonacci/internal/server"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestInterceptors(t *testing.T) {
+	unaryInfo := &grpc.UnaryServerInfo{FullMethod: "/api.FibonacciService/Fibonacci"}
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/api.FibonacciService/FibonacciStream", IsServerStream: true}
+
+	t.Run("metrics count calls by code and messages sent", func(t *testing.T) {
+		handled := func(method, code string) float64 {
+			kind := "unary"
+			if method == "FibonacciStream" {
+				kind = "server_stream"
+			}
+
+			return testutil.ToFloat64(metrics.GRPCServerHandledTotal.WithLabelValues(kind, "api.FibonacciService", method, code))
+		}
+		sent := metrics.GRPCServerMsgSentTotal.WithLabelValues("server_stream", "api.FibonacciService", "FibonacciStream")
+
+		okBefore, failedBefore, sentBefore := handled("Fibonacci", "OK"), handled("Fibonacci", "InvalidArgument"), testutil.ToFloat64(sent)
+
+		unary := server.MetricsUnaryInterceptor()
+		_, err := unary(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+			return &api.FibonacciResponse{}, nil
+		})
+		assert.NoError(t, err)
+
+		_, err = unary(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.InvalidArgument, "invalid")
+		})
+		assert.Error(t, err)
+
+		canceledBefore := handled("FibonacciStream", "Canceled")
+		err = server.MetricsStreamInterceptor()(nil, &fakeStream{ctx: context.Background()}, streamInfo, func(srv any, ss grpc.ServerStream) error {
+			for range 3 {
+				assert.NoError(t, ss.SendMsg(&api.FibonacciChunk{}))
+			}
+
+			return status.Error(codes.Canceled, "canceled")
+		})
+		assert.Error(t, err)
+
+		assert.Equal(t, 1.0, handled("Fibonacci", "OK")-okBefore)
+		assert.Equal(t, 1.0, handled("Fibonacci", "InvalidArgument")-failedBefore)
+		assert.Equal(t, 1.0, handled("FibonacciStream", "Canceled")-canceledBefore)
+		assert.Equal(t, 3.0, testutil.ToFloat64(sent)-sentBefore)
+	})
+
+	t.Run("recovery turns panics into internal errors", func(t *testing.T) {
+		logger, hook := logtest.NewNullLogger()
+
+		_, err := server.RecoveryUnaryInterceptor(logger)(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+			panic("boom")
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.NotContains(t, err.Error(), "boom")
+
+		err = server.RecoveryStreamInterceptor(logger)(nil, &fakeStream{ctx: context.Background()}, streamInfo, func(any, grpc.ServerStream) error {
+			var values []string
+			_ = values[len(values)]
+			return nil
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+
+		assert.Len(t, hook.AllEntries(), 2)
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		assert.Contains(t, hook.LastEntry().Message, "index out of range")
+	})
+
+	t.Run("logging logs completed calls", func(t *testing.T) {
+		logger, hook := logtest.NewNullLogger()
+		unary := server.LoggingUnaryInterceptor(logger, nil)
+
+		_, _ = unary(context.Background(), &api.FibonacciRequest{N: 10}, unaryInfo, func(context.Context, any) (any, error) {
+			return &api.FibonacciResponse{}, nil
+		})
+		assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+		assert.Equal(t, "OK", hook.LastEntry().Data["code"])
+		assert.Equal(t, "ok", hook.LastEntry().Data["outcome"])
+		assert.Equal(t, unaryInfo.FullMethod, hook.LastEntry().Data["method"])
+		assert.Equal(t, int32(10), hook.LastEntry().Data["n"])
+		assert.Len(t, hook.LastEntry().Data["request_id"], 32)
+
+		_, _ = unary(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.ResourceExhausted, "too large")
+		})
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+		assert.Equal(t, "client_error", hook.LastEntry().Data["outcome"])
+
+		stream := &fakeStream{ctx: context.Background()}
+		_ = server.LoggingStreamInterceptor(logger, nil)(nil, stream, streamInfo, func(any, grpc.ServerStream) error {
+			return status.Error(codes.Internal, "internal error")
+		})
+		assert.Equal(t, []string{hook.LastEntry().Data["request_id"].(string)}, stream.header.Get("x-request-id"))
+		assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+		assert.Equal(t, "Internal", hook.LastEntry().Data["code"])
+		assert.Equal(t, "server_error", hook.LastEntry().Data["outcome"])
+	})
+
+	t.Run("logging scopes the logger to the request", func(t *testing.T) {
+		logger, hook := logtest.NewNullLogger()
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "abc"))
+
+		_, _ = server.LoggingUnaryInterceptor(logger, nil)(ctx, nil, unaryInfo, func(ctx context.Context, _ any) (any, error) {
+			logging.FromContext(ctx).Info("computing")
+			return &api.FibonacciResponse{}, nil
+		})
+
+		for _, entry := range hook.AllEntries() {
+			assert.Equal(t, "abc", entry.Data["request_id"])
+		}
+		assert.Equal(t, "computing", hook.AllEntries()[0].Message)
+	})
+
+	t.Run("logging samples successful calls", func(t *testing.T) {
+		logger, hook := logtest.NewNullLogger()
+		unary := server.LoggingUnaryInterceptor(logger, logging.NewSampler(1, 0))
+
+		for range 3 {
+			_, _ = unary(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+				return &api.FibonacciResponse{}, nil
+			})
+		}
+		_, _ = unary(context.Background(), nil, unaryInfo, func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.InvalidArgument, "invalid")
+		})
+
+		// The calls may straddle a second, which logs one more.
+		assert.LessOrEqual(t, len(hook.AllEntries()), 3)
+		assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+	})
+}
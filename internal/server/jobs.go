@@ -0,0 +1,132 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// jobStates maps the job states of the service to the API.
+var jobStates = map[domain.JobState]api.JobState{
+	domain.JobQueued:    api.JobState_JOB_STATE_QUEUED,
+	domain.JobRunning:   api.JobState_JOB_STATE_RUNNING,
+	domain.JobSucceeded: api.JobState_JOB_STATE_SUCCEEDED,
+	domain.JobFailed:    api.JobState_JOB_STATE_FAILED,
+	domain.JobCanceled:  api.JobState_JOB_STATE_CANCELED,
+}
+
+// SubmitJob queues a range to be computed in the background. The job outlives the call,
+// so only the server shutting down cancels it. The estimated digits of the result count against the quota of the caller.
+func (s *FibonacciServer) SubmitJob(ctx context.Context, req *api.SubmitJobRequest) (*api.SubmitJobResponse, error) {
+	ctx, c := s.begin(ctx, "SubmitJob")
+	defer c.end()
+
+	c.started(logrus.Fields{"n": req.GetN(), "start": req.GetStart(), "end": req.GetEnd(), "modulus": req.GetModulus()})
+
+	id, err := s.service.SubmitJob(ctx, domain.JobRequest{
+		Sequence: sequenceSpec(req.GetSequence()),
+		Start:    int(req.GetStart()),
+		End:      rangeEnd(req.GetN(), req.GetStart(), req.End),
+		Modulus:  req.GetModulus(),
+		ChargeFunc: func(digits int64) {
+			chargeDigits(ctx, digits)
+		},
+	})
+
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	return &api.SubmitJobResponse{Id: id}, nil
+}
+
+// GetJobStatus reports the progress of a job.
+func (s *FibonacciServer) GetJobStatus(ctx context.Context, req *api.GetJobStatusRequest) (*api.JobStatus, error) {
+	ctx, c := s.begin(ctx, "GetJobStatus")
+	defer c.end()
+
+	st, err := s.service.GetJobStatus(ctx, req.GetId())
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	return jobStatus(st), nil
+}
+
+// CancelJob cancels a job and returns its status.
+func (s *FibonacciServer) CancelJob(ctx context.Context, req *api.CancelJobRequest) (*api.JobStatus, error) {
+	ctx, c := s.begin(ctx, "CancelJob")
+	defer c.end()
+
+	c.started(logrus.Fields{"job_id": req.GetId()})
+
+	st, err := s.service.CancelJob(ctx, req.GetId())
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	return jobStatus(st), nil
+}
+
+// FetchJobResult streams the result of a succeeded job.
+func (s *FibonacciServer) FetchJobResult(req *api.FetchJobResultRequest, stream grpc.ServerStreamingServer[api.FibonacciChunk]) error {
+	spanCtx, c := s.beginStream(stream.Context(), "FetchJobResult")
+	defer c.end()
+
+	c.started(logrus.Fields{"job_id": req.GetId(), "chunk_size": req.GetChunkSize()})
+
+	ctx, cancel := MergeContexts(spanCtx, s.globalCtx)
+	defer cancel()
+
+	send := c.sender(stream.Send)
+
+	err := s.service.FetchJobResult(ctx, domain.FetchJobResultRequest{
+		ID:        req.GetId(),
+		ChunkSize: int(req.GetChunkSize()),
+		SendFunc: func(chunk domain.FibonacciChunk) error {
+			return send(&api.FibonacciChunk{Index: int32(chunk.Index), Values: chunk.Values})
+		},
+	})
+
+	if err != nil {
+		return c.fail(err)
+	}
+
+	return nil
+}
+
+func jobStatus(st domain.JobStatus) *api.JobStatus {
+	res := &api.JobStatus{
+		Id:          st.ID,
+		State:       jobStates[st.State],
+		Start:       int32(st.Start),
+		End:         int32(st.End),
+		Computed:    int64(st.Computed),
+		Error:       st.Error,
+		SubmittedAt: timestamp(st.SubmittedAt),
+		StartedAt:   timestamp(st.StartedAt),
+		FinishedAt:  timestamp(st.FinishedAt),
+	}
+
+	if st.ETA > 0 {
+		res.Eta = durationpb.New(st.ETA)
+	}
+
+	return res
+}
+
+// timestamp converts t, leaving the zero time unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+
+	return timestamppb.New(t)
+}
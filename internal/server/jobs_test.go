@@ -0,0 +1,149 @@
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	internalMock "fibonacci/internal/mock"
+	"fibonacci/internal/server"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestServer(t *testing.T) (*server.FibonacciServer, *internalMock.Service) {
+	mockService := internalMock.NewService(t)
+
+	return server.NewFibonacciServer(context.Background(), grpc.NewServer(), mockService, logrus.New()), mockService
+}
+
+func TestFibonacciServer_SubmitJob(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+
+		mockService.EXPECT().
+			SubmitJob(mock.Anything, mock.MatchedBy(func(req domain.JobRequest) bool {
+				return req.Start == 10 && req.End == 1000010 && req.Modulus == 7 && req.ChargeFunc != nil
+			})).
+			Return("job-id", nil)
+
+		res, err := s.SubmitJob(context.Background(), &api.SubmitJobRequest{N: 1000000, Start: 10, Modulus: 7})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "job-id", res.GetId())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for err, code := range map[error]codes.Code{
+			domain.ErrJobQueueFull: codes.ResourceExhausted,
+			domain.ErrJobsDisabled: codes.Unimplemented,
+			domain.ErrInvalidRange: codes.InvalidArgument,
+		} {
+			s, mockService := newTestServer(t)
+
+			mockService.EXPECT().SubmitJob(mock.Anything, mock.Anything).Return("", err)
+
+			_, statusErr := s.SubmitJob(context.Background(), &api.SubmitJobRequest{Start: 10, End: proto.Int32(5)})
+			assert.Equal(t, code, status.Code(statusErr), err)
+		}
+	})
+}
+
+func TestFibonacciServer_GetJobStatus(t *testing.T) {
+	t.Run("running", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+		submitted := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+		mockService.EXPECT().GetJobStatus(mock.Anything, "job-id").Return(domain.JobStatus{
+			ID:          "job-id",
+			State:       domain.JobRunning,
+			End:         1000,
+			Computed:    250,
+			ETA:         3 * time.Second,
+			SubmittedAt: submitted,
+			StartedAt:   submitted.Add(time.Second),
+		}, nil)
+
+		res, err := s.GetJobStatus(context.Background(), &api.GetJobStatusRequest{Id: "job-id"})
+
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(&api.JobStatus{
+			Id:          "job-id",
+			State:       api.JobState_JOB_STATE_RUNNING,
+			End:         1000,
+			Computed:    250,
+			Eta:         durationpb.New(3 * time.Second),
+			SubmittedAt: timestamppb.New(submitted),
+			StartedAt:   timestamppb.New(submitted.Add(time.Second)),
+		}, res), res)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+
+		mockService.EXPECT().GetJobStatus(mock.Anything, "unknown").Return(domain.JobStatus{}, domain.ErrJobNotFound)
+
+		_, err := s.GetJobStatus(context.Background(), &api.GetJobStatusRequest{Id: "unknown"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestFibonacciServer_CancelJob(t *testing.T) {
+	s, mockService := newTestServer(t)
+
+	mockService.EXPECT().CancelJob(mock.Anything, "job-id").Return(domain.JobStatus{
+		ID:    "job-id",
+		State: domain.JobCanceled,
+		Error: "canceled by the client",
+	}, nil)
+
+	res, err := s.CancelJob(context.Background(), &api.CancelJobRequest{Id: "job-id"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, api.JobState_JOB_STATE_CANCELED, res.GetState())
+	assert.Equal(t, "canceled by the client", res.GetError())
+	assert.Nil(t, res.GetFinishedAt())
+}
+
+func TestFibonacciServer_FetchJobResult(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+
+		mockService.EXPECT().
+			FetchJobResult(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FetchJobResultRequest) error {
+				assert.Equal(t, "job-id", r.ID)
+				assert.Equal(t, 2, r.ChunkSize)
+
+				return r.SendFunc(domain.FibonacciChunk{Index: 5, Values: []string{"5", "8"}})
+			})
+
+		stream.EXPECT().Context().Return(context.Background())
+		stream.EXPECT().Send(&api.FibonacciChunk{Index: 5, Values: []string{"5", "8"}}).Return(nil).Once()
+
+		err := s.FetchJobResult(&api.FetchJobResultRequest{Id: "job-id", ChunkSize: 2}, stream)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		s, mockService := newTestServer(t)
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+
+		mockService.EXPECT().FetchJobResult(mock.Anything, mock.Anything).Return(domain.ErrJobNotReady)
+		stream.EXPECT().Context().Return(context.Background())
+
+		err := s.FetchJobResult(&api.FetchJobResultRequest{Id: "job-id", ChunkSize: 2}, stream)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+}
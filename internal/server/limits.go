@@ -0,0 +1,438 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/metrics"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// APIKeyHeader is the metadata key clients pass their API key in.
+const APIKeyHeader = "x-api-key"
+
+// anonymousClient labels the metrics of the clients identified by their address rather than an API key,
+// as addresses are too many to be labels of their own.
+const anonymousClient = "anonymous"
+
+// LimitsConfig holds the limits of every tier and the clients assigned to one, see LoadLimits for the file format.
+type LimitsConfig struct {
+	// DefaultTier applies to unknown clients, which are told apart by their identity if they are authenticated
+	// and by their address otherwise.
+	DefaultTier string          `yaml:"default_tier"`
+	Tiers       map[string]Tier `yaml:"tiers"`
+	Clients     []Client        `yaml:"clients"`
+}
+
+// Tier limits the clients assigned to it. Zero values are not enforced.
+type Tier struct {
+	Rate        float64 `yaml:"rate"`         // Calls per second, each stream counting once
+	Burst       int     `yaml:"burst"`        // Calls allowed at once on top of the rate, at least 1
+	DailyDigits int64   `yaml:"daily_digits"` // Decimal digits of the terms returned per UTC day
+}
+
+// Client assigns a client to a tier. Authenticated clients are known by their name, see Identity,
+// and the others by their API key, which is only needed if the server does not authenticate calls.
+type Client struct {
+	Name string `yaml:"name"` // Labels the metrics of the client
+	Key  string `yaml:"key"`
+	Tier string `yaml:"tier"`
+}
+
+// LoadLimits reads the limits from the YAML file at path, e.g.
+//
+//	default_tier: free
+//	tiers:
+//	  free: {rate: 2, burst: 5, daily_digits: 10000000}
+//	  pro: {rate: 50, burst: 100}
+//	clients:
+//	  - {name: acme, key: "...", tier: pro}
+func LoadLimits(path string) (LimitsConfig, error) {
+	var cfg LimitsConfig
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Limiter enforces the rate limits and daily digit quotas of the tiers on the gRPC calls and gateway requests
+// of each client.
+//
+// Calls over the rate are rejected rather than delayed. The digits of the terms a client receives are counted
+// against its quota as they are sent, so a call is only rejected once the quota is used up: the response
+// or chunk exhausting it is still sent, and streams fail with the next chunk.
+type Limiter struct {
+	cfg  LimitsConfig
+	keys map[string]Client
+	now  func() time.Time
+
+	mu      sync.Mutex
+	day     string                    // UTC date the usage is counted for
+	clients map[clientID]*clientUsage // Usage by client, reset every day
+}
+
+// clientUsage is the state of the limits of a single client.
+type clientUsage struct {
+	bucket *rate.Limiter // Nil when the tier has no rate limit
+	digits int64         // Digits received today
+}
+
+// NewLimiter returns a Limiter enforcing cfg, which must define the default tier and the tier of every client.
+func NewLimiter(cfg LimitsConfig) (*Limiter, error) {
+	if _, ok := cfg.Tiers[cfg.DefaultTier]; !ok {
+		return nil, fmt.Errorf("default tier %q is not defined", cfg.DefaultTier)
+	}
+
+	for name, tier := range cfg.Tiers {
+		if tier.Rate < 0 || tier.Burst < 0 || tier.DailyDigits < 0 {
+			return nil, fmt.Errorf("tier %q: limits must not be negative", name)
+		}
+	}
+
+	keys := make(map[string]Client, len(cfg.Clients))
+	for _, c := range cfg.Clients {
+		if c.Name == "" {
+			return nil, errors.New("client without a name")
+		}
+
+		if _, ok := cfg.Tiers[c.Tier]; !ok {
+			return nil, fmt.Errorf("client %q: tier %q is not defined", c.Name, c.Tier)
+		}
+
+		if c.Key == "" {
+			continue
+		}
+
+		if _, ok := keys[c.Key]; ok {
+			return nil, fmt.Errorf("client %q: key is already used by another client", c.Name)
+		}
+
+		keys[c.Key] = c
+	}
+
+	return &Limiter{cfg: cfg, keys: keys, now: time.Now, clients: make(map[clientID]*clientUsage)}, nil
+}
+
+// UnaryInterceptor limits unary calls other than the health checks, counting the digits of their response
+// and those charged by the handler, see chargeDigits.
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isHealthCheck(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		id := l.identify(ctx)
+		if err := l.admit(id); err != nil {
+			return nil, err
+		}
+
+		ctx = context.WithValue(ctx, limitedClientKey{}, &limitedClient{limiter: l, id: id})
+
+		res, err := handler(ctx, req)
+		if err == nil {
+			l.charge(id, responseDigits(res))
+		}
+
+		return res, err
+	}
+}
+
+// StreamInterceptor limits streaming calls, counting the digits of every message sent.
+func (l *Limiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isHealthCheck(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		id := l.identify(ss.Context())
+		if err := l.admit(id); err != nil {
+			return err
+		}
+
+		return handler(srv, &limitedStream{ServerStream: ss, limiter: l, id: id})
+	}
+}
+
+// middleware limits the gateway requests like the gRPC calls, writing rejections with onError. The client is
+// identified by the X-Api-Key header or the remote address; requests authenticated by the Authenticator middleware
+// by their identity. The responses and chunks are counted by the gateway, see limitSend and chargeResponse.
+func (l *Limiter) middleware(onError func(http.ResponseWriter, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := l.identifyClient(r.Context(), r.Header.Values(APIKeyHeader), r.RemoteAddr)
+			if err := l.admit(id); err != nil {
+				onError(w, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), limitedClientKey{}, &limitedClient{limiter: l, id: id})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type limitedClientKey struct{}
+
+// limitedClient is the client of a unary call or gateway request admitted by a Limiter.
+type limitedClient struct {
+	limiter *Limiter
+	id      clientID
+}
+
+// limitSend wraps the send function of a gateway stream, so that its chunks count against the quota of the client
+// admitted for ctx, if any, like limitedStream does for gRPC streams.
+func limitSend(ctx context.Context, send func(*api.FibonacciChunk) error) func(*api.FibonacciChunk) error {
+	c, ok := ctx.Value(limitedClientKey{}).(*limitedClient)
+	if !ok {
+		return send
+	}
+
+	return func(chunk *api.FibonacciChunk) error {
+		if err := c.limiter.checkQuota(c.id); err != nil {
+			return err
+		}
+
+		if err := send(chunk); err != nil {
+			return err
+		}
+
+		c.limiter.charge(c.id, responseDigits(chunk))
+
+		return nil
+	}
+}
+
+// chargeResponse counts the digits of a gateway response against the quota of the client admitted for ctx, if any.
+func chargeResponse(ctx context.Context, m proto.Message) {
+	chargeDigits(ctx, responseDigits(m))
+}
+
+// chargeDigits counts digits that are not part of the response, such as the result of a job,
+// against the quota of the client admitted for ctx, if any.
+func chargeDigits(ctx context.Context, digits int64) {
+	if c, ok := ctx.Value(limitedClientKey{}).(*limitedClient); ok {
+		c.limiter.charge(c.id, digits)
+	}
+}
+
+// limitedStream counts the digits sent on a stream against the quota of its client.
+type limitedStream struct {
+	grpc.ServerStream
+	limiter *Limiter
+	id      clientID
+}
+
+func (s *limitedStream) SendMsg(m any) error {
+	if err := s.limiter.checkQuota(s.id); err != nil {
+		return err
+	}
+
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+
+	s.limiter.charge(s.id, responseDigits(m))
+
+	return nil
+}
+
+// clientID identifies a client and the tier it is limited by.
+type clientID struct {
+	key  string // Authenticated identity, API key or address
+	name string // Label of the client in metrics
+	tier string
+}
+
+// identify returns the client of a gRPC call, see identifyClient.
+func (l *Limiter) identify(ctx context.Context) clientID {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+	}
+
+	return l.identifyClient(ctx, metadata.ValueFromIncomingContext(ctx, APIKeyHeader), addr)
+}
+
+// identifyClient returns the client of a call: the identity authenticated by an Authenticator, the client with
+// one of the API keys of the call, or else the address of the caller. Unknown keys are ignored, so they cannot be
+// used to get a fresh quota.
+func (l *Limiter) identifyClient(ctx context.Context, keys []string, addr string) clientID {
+	if id, ok := IdentityFromContext(ctx); ok {
+		return clientID{key: "id:" + id.Name, name: id.metricsLabel(), tier: l.identityTier(id)}
+	}
+
+	for _, key := range keys {
+		if c, ok := l.lookup(key); ok {
+			return clientID{key: "key:" + c.Key, name: c.Name, tier: c.Tier}
+		}
+	}
+
+	if addr == "" {
+		addr = "unknown"
+	}
+	// Every connection comes from a different port.
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	return clientID{key: "addr:" + addr, name: anonymousClient, tier: l.cfg.DefaultTier}
+}
+
+// identityTier returns the tier of an authenticated identity: the tier set by its credentials if it is defined,
+// otherwise the tier of the client of the same name, and the default tier if there is none.
+func (l *Limiter) identityTier(id Identity) string {
+	if _, ok := l.cfg.Tiers[id.Tier]; ok {
+		return id.Tier
+	}
+
+	for _, c := range l.cfg.Clients {
+		if c.Name == id.Name {
+			return c.Tier
+		}
+	}
+
+	return l.cfg.DefaultTier
+}
+
+// lookup returns the client with key, comparing keys in constant time.
+func (l *Limiter) lookup(key string) (Client, bool) {
+	for k, c := range l.keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return c, true
+		}
+	}
+
+	return Client{}, false
+}
+
+// usage returns the usage of the client id. It must be called with l.mu held.
+func (l *Limiter) usage(id clientID) *clientUsage {
+	// Usage is only kept for the current day, which also drops the clients that are gone.
+	if day := l.now().UTC().Format(time.DateOnly); day != l.day {
+		l.day = day
+		clear(l.clients)
+	}
+
+	u, ok := l.clients[id]
+	if ok {
+		return u
+	}
+
+	u = &clientUsage{}
+	if tier := l.cfg.Tiers[id.tier]; tier.Rate > 0 {
+		u.bucket = rate.NewLimiter(rate.Limit(tier.Rate), max(tier.Burst, 1))
+	}
+
+	l.clients[id] = u
+
+	return u
+}
+
+// admit takes a token from the bucket of the client id, failing with ResourceExhausted when it is empty
+// or the quota of the client is used up.
+func (l *Limiter) admit(id clientID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	u := l.usage(id)
+	metrics.ClientRequestsTotal.WithLabelValues(id.tier, id.name).Inc()
+
+	if err := l.quotaError(id, u); err != nil {
+		return err
+	}
+
+	if u.bucket != nil && !u.bucket.AllowN(l.now(), 1) {
+		metrics.ClientRejectionsTotal.WithLabelValues(id.tier, id.name, "rate").Inc()
+		return status.Errorf(codes.ResourceExhausted, "rate limit of %g calls per second exceeded", l.cfg.Tiers[id.tier].Rate)
+	}
+
+	return nil
+}
+
+// checkQuota fails with ResourceExhausted when the quota of the client id is used up.
+func (l *Limiter) checkQuota(id clientID) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.quotaError(id, l.usage(id))
+}
+
+// quotaError returns the error rejecting a call of the client id with usage u once its quota is used up, or nil.
+// It must be called with l.mu held.
+func (l *Limiter) quotaError(id clientID, u *clientUsage) error {
+	limit := l.cfg.Tiers[id.tier].DailyDigits
+	if limit == 0 || u.digits < limit {
+		return nil
+	}
+
+	metrics.ClientRejectionsTotal.WithLabelValues(id.tier, id.name, "quota").Inc()
+
+	return status.Errorf(codes.ResourceExhausted, "daily quota of %d digits used up", limit)
+}
+
+// charge counts digits against the quota of the client id.
+func (l *Limiter) charge(id clientID, digits int64) {
+	if digits == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.usage(id).digits += digits
+	metrics.ClientDigitsTotal.WithLabelValues(id.tier, id.name).Add(float64(digits))
+}
+
+// responseDigits returns the number of decimal digits of the terms in a response message, without signs.
+func responseDigits(m any) int64 {
+	switch m := m.(type) {
+	case *api.FibonacciResponse:
+		return valuesDigits(m.GetValues())
+	case *api.FibonacciChunk:
+		return valuesDigits(m.GetValues())
+	case *api.FibonacciNthResponse:
+		return valuesDigits([]string{m.GetValue()})
+	case *api.FibonacciBatchResponse:
+		var digits int64
+		for _, r := range m.GetResults() {
+			digits += valuesDigits(r.GetRange().GetValues()) + valuesDigits([]string{r.GetNth().GetValue()})
+		}
+
+		return digits
+	default:
+		return 0
+	}
+}
+
+func valuesDigits(values []string) int64 {
+	var digits int64
+	for _, v := range values {
+		digits += int64(len(strings.TrimPrefix(v, "-")))
+	}
+
+	return digits
+}
@@ -0,0 +1,238 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/metrics"
+	"fibonacci/internal/server"
+	"fibonacci/internal/service"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream is a server stream recording the header and messages sent on it.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+	sent   []any
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *fakeStream) SendMsg(m any) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestLimiter(t *testing.T) {
+	cfg := server.LimitsConfig{
+		DefaultTier: "free",
+		Tiers: map[string]server.Tier{
+			"free":      {Rate: 0.001, Burst: 2},
+			"metered":   {DailyDigits: 10},
+			"unlimited": {},
+		},
+		Clients: []server.Client{
+			{Name: "acme", Key: "acme-key", Tier: "metered"},
+			{Name: "globex", Key: "globex-key", Tier: "unlimited"},
+		},
+	}
+
+	callerCtx := func(addr string, key string) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 40000}})
+		if key != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(server.APIKeyHeader, key))
+		}
+
+		return ctx
+	}
+
+	unary := func(l *server.Limiter, ctx context.Context, values ...string) error {
+		_, err := l.UnaryInterceptor()(ctx, &api.FibonacciRequest{}, &grpc.UnaryServerInfo{},
+			func(ctx context.Context, req any) (any, error) {
+				return &api.FibonacciResponse{Values: values}, nil
+			})
+
+		return err
+	}
+
+	assertExhausted := func(t *testing.T, err error, msg string) {
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), msg)
+	}
+
+	t.Run("rate limits per address", func(t *testing.T) {
+		l, err := server.NewLimiter(cfg)
+		assert.NoError(t, err)
+
+		before := testutil.ToFloat64(metrics.ClientRejectionsTotal.WithLabelValues("free", "anonymous", "rate"))
+
+		assert.NoError(t, unary(l, callerCtx("10.0.0.1", "")))
+		assert.NoError(t, unary(l, callerCtx("10.0.0.1", "")))
+		assertExhausted(t, unary(l, callerCtx("10.0.0.1", "")), "rate limit of 0.001 calls per second exceeded")
+
+		// Other addresses and unknown keys from other addresses have buckets of their own.
+		assert.NoError(t, unary(l, callerCtx("10.0.0.2", "")))
+		assert.NoError(t, unary(l, callerCtx("10.0.0.3", "unknown-key")))
+
+		// Known keys are not limited by the address they call from.
+		assert.NoError(t, unary(l, callerCtx("10.0.0.1", "globex-key")))
+
+		assert.Equal(t, before+1, testutil.ToFloat64(metrics.ClientRejectionsTotal.WithLabelValues("free", "anonymous", "rate")))
+	})
+
+	t.Run("daily digit quota", func(t *testing.T) {
+		l, err := server.NewLimiter(cfg)
+		assert.NoError(t, err)
+
+		before := testutil.ToFloat64(metrics.ClientDigitsTotal.WithLabelValues("metered", "acme"))
+
+		assert.NoError(t, unary(l, callerCtx("10.0.0.1", "acme-key"), "-13", "21", "34"))
+		assert.NoError(t, unary(l, callerCtx("10.0.0.2", "acme-key"), "55", "89", "144"))
+		assertExhausted(t, unary(l, callerCtx("10.0.0.1", "acme-key")), "daily quota of 10 digits used up")
+
+		assert.Equal(t, before+13, testutil.ToFloat64(metrics.ClientDigitsTotal.WithLabelValues("metered", "acme")))
+	})
+
+	t.Run("jobs count against the quota", func(t *testing.T) {
+		l, err := server.NewLimiter(cfg)
+		assert.NoError(t, err)
+
+		s, mockService := newTestServer(t)
+		mockService.EXPECT().SubmitJob(mock.Anything, mock.Anything).
+			Run(func(ctx context.Context, req domain.JobRequest) { req.ChargeFunc(12) }).
+			Return("job-id", nil)
+
+		_, err = l.UnaryInterceptor()(callerCtx("10.0.0.1", "acme-key"), &api.SubmitJobRequest{N: 20}, &grpc.UnaryServerInfo{},
+			func(ctx context.Context, req any) (any, error) {
+				return s.SubmitJob(ctx, req.(*api.SubmitJobRequest))
+			})
+		assert.NoError(t, err)
+
+		assertExhausted(t, unary(l, callerCtx("10.0.0.1", "acme-key")), "daily quota of 10 digits used up")
+	})
+
+	t.Run("stream fails once the quota is used up", func(t *testing.T) {
+		l, err := server.NewLimiter(cfg)
+		assert.NoError(t, err)
+
+		stream := &fakeStream{ctx: callerCtx("10.0.0.1", "acme-key")}
+		err = l.StreamInterceptor()(nil, stream, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+			for _, values := range [][]string{{"0", "1", "1", "2"}, {"13", "21", "34"}, {"55", "89"}} {
+				if err := ss.SendMsg(&api.FibonacciChunk{Values: values}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		assertExhausted(t, err, "daily quota")
+		assert.Len(t, stream.sent, 2)
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		l, err := server.NewLimiter(cfg)
+		assert.NoError(t, err)
+
+		s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), service.NewService(10, 1, 100, 100, 1000), logrus.New())
+		router := mux.NewRouter()
+		server.NewGateway(s, server.WithGatewayLimits(l)).Register(router)
+
+		request := func(target, key string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req.RemoteAddr = "10.0.0.1:40000"
+			if key != "" {
+				req.Header.Set("X-Api-Key", key)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			return rec
+		}
+
+		assert.Equal(t, http.StatusOK, request("/v1/fibonacci?n=5", "").Code)
+		assert.Equal(t, http.StatusOK, request("/v1/fibonacci/stream?n=5&chunk_size=5", "").Code)
+		rec := request("/v1/fibonacci?n=5", "")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Contains(t, rec.Body.String(), "rate limit")
+
+		// F(0)..F(9) have 11 digits, using up the quota of 10.
+		assert.Equal(t, http.StatusOK, request("/v1/fibonacci?n=10", "acme-key").Code)
+		rec = request("/v1/fibonacci/stream?n=10&chunk_size=5", "acme-key")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Contains(t, rec.Body.String(), "daily quota")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		for _, cfg := range []server.LimitsConfig{
+			{DefaultTier: "missing", Tiers: map[string]server.Tier{"free": {}}},
+			{DefaultTier: "free", Tiers: map[string]server.Tier{"free": {Rate: -1}}},
+			{DefaultTier: "free", Tiers: map[string]server.Tier{"free": {}}, Clients: []server.Client{{Name: "a", Key: "k", Tier: "missing"}}},
+			{DefaultTier: "free", Tiers: map[string]server.Tier{"free": {}}, Clients: []server.Client{{Key: "k", Tier: "free"}}},
+			{DefaultTier: "free", Tiers: map[string]server.Tier{"free": {}}, Clients: []server.Client{
+				{Name: "a", Key: "k", Tier: "free"}, {Name: "b", Key: "k", Tier: "free"},
+			}},
+		} {
+			_, err := server.NewLimiter(cfg)
+			assert.Error(t, err, "%+v", cfg)
+		}
+	})
+
+	t.Run("load config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "limits.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(`
+default_tier: free
+tiers:
+  free: {rate: 2, burst: 5, daily_digits: 1000}
+  pro: {rate: 50}
+clients:
+  - {name: acme, key: secret, tier: pro}
+`), 0o644))
+
+		loaded, err := server.LoadLimits(path)
+		assert.NoError(t, err)
+		assert.Equal(t, server.LimitsConfig{
+			DefaultTier: "free",
+			Tiers: map[string]server.Tier{
+				"free": {Rate: 2, Burst: 5, DailyDigits: 1000},
+				"pro":  {Rate: 50},
+			},
+			Clients: []server.Client{{Name: "acme", Key: "secret", Tier: "pro"}},
+		}, loaded)
+
+		_, err = server.NewLimiter(loaded)
+		assert.NoError(t, err)
+
+		// The example shipped w/ the service is valid.
+		example, err := server.LoadLimits("../../config/limits.yaml")
+		assert.NoError(t, err)
+
+		_, err = server.NewLimiter(example)
+		assert.NoError(t, err)
+	})
+}
@@ -3,16 +3,17 @@ package server
 import (
 	"context"
 	"errors"
-	"net/http"
+	"fmt"
+	"io"
 
 	"fibonacci/internal/domain"
 	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/logging"
 	"fibonacci/internal/service"
 
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
-	"google.golang.org/grpc/status"
 )
 
 // FibonacciServer handles gRPC requests for the Fibonacci service.
@@ -31,6 +32,13 @@ type FibonacciChunkStreamServer interface {
 	grpc.ServerStreamingServer[api.FibonacciChunk]
 }
 
+//go:generate mockery --name=FibonacciFlowServer --with-expecter --output=../mock --outpkg=mock --case=underscore
+
+// FibonacciFlowServer provides an interface to enable mock generation for the bidirectional flow stream.
+type FibonacciFlowServer interface {
+	grpc.BidiStreamingServer[api.FibonacciFlowRequest, api.FibonacciChunk]
+}
+
 func NewFibonacciServer(ctx context.Context, s *grpc.Server, fibonacciService service.Service, logger *logrus.Logger) *FibonacciServer {
 	if logger == nil {
 		logger = logrus.New()
@@ -50,81 +58,293 @@ func NewFibonacciServer(ctx context.Context, s *grpc.Server, fibonacciService se
 
 // FibonacciStream streams chunks of Fibonacci numbers to the client.
 func (s *FibonacciServer) FibonacciStream(req *api.FibonacciStreamRequest, stream grpc.ServerStreamingServer[api.FibonacciChunk]) error {
-	s.logger.Printf("FibonacciStream called with N=%d, ChunkSize=%d", req.GetN(), req.GetChunkSize())
+	return s.streamChunks(stream.Context(), req, stream.Send)
+}
 
-	sendFunc := func(values []string, i int) error {
-		return stream.Send(&api.FibonacciChunk{
-			Index:  int32(i),
-			Values: values,
-		})
-	}
+// streamChunks generates the requested chunks and passes each to send, which must not retain it.
+// It backs the gRPC stream as well as the HTTP streaming transports, so all of them share
+// validation, cancellation and error semantics.
+func (s *FibonacciServer) streamChunks(ctx context.Context, req *api.FibonacciStreamRequest, send func(*api.FibonacciChunk) error) error {
+	ctx, c := s.beginStream(ctx, "FibonacciStream")
+	defer c.end()
+
+	c.started(streamFields(req))
 
-	ctx, cancel := MergeContexts(stream.Context(), s.globalCtx)
+	ctx, cancel := MergeContexts(ctx, s.globalCtx)
 	defer cancel()
 
-	err := s.service.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-		N:         int(req.GetN()),
-		ChunkSize: int(req.GetChunkSize()),
-		SendFunc:  sendFunc,
-	})
+	err := s.service.GetFibonacciStream(ctx, streamRequest(req, c.sender(send)))
+
+	if err != nil {
+		return c.fail(err)
+	}
+
+	return nil
+}
 
+// FibonacciFlow streams chunks as the client requests them. The first message opens the stream,
+// the following ones request chunks, change their size, pause or seek; see api.FibonacciFlowRequest.
+func (s *FibonacciServer) FibonacciFlow(stream grpc.BidiStreamingServer[api.FibonacciFlowRequest, api.FibonacciChunk]) error {
+	first, err := stream.Recv()
 	if err != nil {
-		s.logger.Printf("Error getting fibonacci stream: %v", err)
-
-		if errors.Is(err, domain.ErrInvalidChunkSize) || errors.Is(err, domain.ErrNegativeN) || errors.Is(err, domain.ErrTooLargeN) {
-			return status.Errorf(http.StatusBadRequest, "Bad Request: %s", err)
-		} else if errors.Is(err, domain.ErrContextCanceled) && s.globalCtx.Err() != nil {
-			return status.Errorf(http.StatusServiceUnavailable, "Service unavailable: %s", err)
-		} else if errors.Is(err, domain.ErrContextCanceled) {
-			return status.Errorf(http.StatusBadRequest, "Context canceled: %s", err)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		return err
+	}
+
+	req := first.GetStart()
+	if req == nil {
+		return s.statusError(domain.NewFieldError("start", fmt.Errorf("%w: the first message must start the stream", domain.ErrInvalidCommand)))
+	}
+
+	spanCtx, c := s.beginStream(stream.Context(), "FibonacciFlow")
+	defer c.end()
+
+	c.started(streamFields(req))
+
+	ctx, cancel := MergeContexts(spanCtx, s.globalCtx)
+	defer cancel()
+
+	// Commands are received concurrently, so that they can take effect between chunks.
+	// The goroutine ends with the stream, which fails Recv once the handler has returned.
+	commands := make(chan domain.FlowCommand)
+	go func() {
+		defer close(commands)
+
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					cancel()
+				}
+
+				return
+			}
+
+			select {
+			case commands <- flowCommand(msg):
+			case <-ctx.Done():
+				return
+			}
 		}
+	}()
+
+	err = s.service.GetFibonacciFlow(ctx, domain.FibonacciFlowRequest{
+		Stream:   streamRequest(req, c.sender(stream.Send)),
+		Commands: commands,
+	})
 
-		return status.Errorf(http.StatusInternalServerError, "Internal server error: %s", err)
+	if err != nil {
+		return c.fail(err)
 	}
 
 	return nil
 }
 
-// Fibonacci calculates the entire Fibonacci sequence up to n and returns it.
+// Fibonacci calculates the requested range of the Fibonacci sequence and returns it.
 func (s *FibonacciServer) Fibonacci(ctx context.Context, req *api.FibonacciRequest) (*api.FibonacciResponse, error) {
-	s.logger.Printf("Fibonacci called with N=%d", req.GetN())
+	ctx, c := s.begin(ctx, "Fibonacci")
+	defer c.end()
+
+	c.started(logrus.Fields{"n": req.GetN(), "start": req.GetStart(), "end": req.GetEnd(), "modulus": req.GetModulus()})
+
+	ctx, cancel := MergeContexts(ctx, s.globalCtx)
+	defer cancel()
+
+	res, err := s.service.GetFibonacci(ctx, fibonacciRequest(req))
+
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	resp := &api.FibonacciResponse{Values: res}
+	c.emit(resp)
+
+	return resp, nil
+}
+
+// FibonacciNth calculates the single Fibonacci number F(n).
+func (s *FibonacciServer) FibonacciNth(ctx context.Context, req *api.FibonacciNthRequest) (*api.FibonacciNthResponse, error) {
+	ctx, c := s.begin(ctx, "FibonacciNth")
+	defer c.end()
+
+	c.started(logrus.Fields{"n": req.GetN(), "modulus": req.GetModulus()})
+
+	ctx, cancel := MergeContexts(ctx, s.globalCtx)
+	defer cancel()
+
+	res, err := s.service.GetNth(ctx, nthRequest(req))
+
+	if err != nil {
+		return nil, c.fail(err)
+	}
+
+	resp := &api.FibonacciNthResponse{N: req.GetN(), Value: res}
+	c.emit(resp)
+
+	return resp, nil
+}
+
+// PisanoPeriod calculates the period of the Fibonacci sequence modulo the requested modulus.
+func (s *FibonacciServer) PisanoPeriod(ctx context.Context, req *api.PisanoPeriodRequest) (*api.PisanoPeriodResponse, error) {
+	ctx, c := s.begin(ctx, "PisanoPeriod")
+	defer c.end()
+
+	c.started(logrus.Fields{"modulus": req.GetModulus()})
 
 	ctx, cancel := MergeContexts(ctx, s.globalCtx)
 	defer cancel()
 
-	res, err := s.service.GetFibonacci(ctx, int(req.GetN()))
+	res, err := s.service.GetPisanoPeriod(ctx, req.GetModulus())
 
 	if err != nil {
-		s.logger.Printf("Error getting fibonacci stream: %v", err)
-
-		if errors.Is(err, domain.ErrInvalidChunkSize) || errors.Is(err, domain.ErrNegativeN) || errors.Is(err, domain.ErrTooLargeN) {
-			return nil, status.Errorf(http.StatusBadRequest, "Bad Request: %s", err)
-		} else if errors.Is(err, domain.ErrContextCanceled) && s.globalCtx.Err() != nil {
-			return nil, status.Errorf(http.StatusServiceUnavailable, "Service unavailable: %s", err)
-		} else if errors.Is(err, domain.ErrContextCanceled) {
-			return nil, status.Errorf(http.StatusBadRequest, "Context canceled: %s", err)
+		return nil, c.fail(err)
+	}
+
+	resp := &api.PisanoPeriodResponse{Modulus: req.GetModulus(), Period: res}
+	c.emit(resp)
+
+	return resp, nil
+}
+
+// fibonacciRequest converts a range request.
+func fibonacciRequest(req *api.FibonacciRequest) domain.FibonacciRequest {
+	return domain.FibonacciRequest{
+		Sequence: sequenceSpec(req.GetSequence()),
+		Start:    int(req.GetStart()),
+		End:      rangeEnd(req.GetN(), req.GetStart(), req.End),
+		Modulus:  req.GetModulus(),
+	}
+}
+
+// nthRequest converts a single term request.
+func nthRequest(req *api.FibonacciNthRequest) domain.FibonacciNthRequest {
+	return domain.FibonacciNthRequest{
+		Sequence: sequenceSpec(req.GetSequence()),
+		N:        int(req.GetN()),
+		Modulus:  req.GetModulus(),
+	}
+}
+
+// streamRequest converts a stream request whose chunks are passed to send.
+func streamRequest(req *api.FibonacciStreamRequest, send func(*api.FibonacciChunk) error) domain.FibonacciStreamRequest {
+	return domain.FibonacciStreamRequest{
+		Sequence:    sequenceSpec(req.GetSequence()),
+		Start:       int(req.GetStart()),
+		End:         rangeEnd(req.GetN(), req.GetStart(), req.End),
+		Modulus:     req.GetModulus(),
+		ChunkSize:   int(req.GetChunkSize()),
+		ResumeToken: req.GetResumeToken(),
+		SendFunc: func(chunk domain.FibonacciChunk) error {
+			return send(&api.FibonacciChunk{
+				Index:             int32(chunk.Index),
+				Values:            chunk.Values,
+				ContinuationToken: chunk.ContinuationToken,
+			})
+		},
+	}
+}
+
+// flowCommand converts a flow control message. A message without a command, a second start or a pause
+// set to false becomes a command of no kind, which the service rejects.
+func flowCommand(msg *api.FibonacciFlowRequest) domain.FlowCommand {
+	switch cmd := msg.GetCommand().(type) {
+	case *api.FibonacciFlowRequest_Next:
+		return domain.FlowCommand{Kind: domain.FlowNext, Value: int(cmd.Next)}
+	case *api.FibonacciFlowRequest_ChunkSize:
+		return domain.FlowCommand{Kind: domain.FlowChunkSize, Value: int(cmd.ChunkSize)}
+	case *api.FibonacciFlowRequest_Pause:
+		if !cmd.Pause {
+			return domain.FlowCommand{}
 		}
 
-		return nil, status.Errorf(http.StatusInternalServerError, "Internal server error: %s", err)
+		return domain.FlowCommand{Kind: domain.FlowPause}
+	case *api.FibonacciFlowRequest_Seek:
+		return domain.FlowCommand{Kind: domain.FlowSeek, Value: int(cmd.Seek)}
+	default:
+		return domain.FlowCommand{}
+	}
+}
+
+// rangeEnd returns the exclusive end of a requested range, which defaults to start + n when end is omitted.
+// Presence is checked rather than zero, since 0 is a valid end for a range of negative indices.
+func rangeEnd(n, start int32, end *int32) int {
+	if end == nil {
+		return int(start) + int(n)
+	}
+
+	return int(*end)
+}
+
+// sequenceNames maps the built-in sequence kinds to the names the service registers them under.
+var sequenceNames = map[api.SequenceKind]string{
+	api.SequenceKind_SEQUENCE_KIND_FIBONACCI:  service.SequenceFibonacci,
+	api.SequenceKind_SEQUENCE_KIND_LUCAS:      service.SequenceLucas,
+	api.SequenceKind_SEQUENCE_KIND_PELL:       service.SequencePell,
+	api.SequenceKind_SEQUENCE_KIND_TRIBONACCI: service.SequenceTribonacci,
+	api.SequenceKind_SEQUENCE_KIND_KBONACCI:   service.SequenceKBonacci,
+	api.SequenceKind_SEQUENCE_KIND_CUSTOM:     service.SequenceCustom,
+}
+
+// sequenceSpec converts the requested sequence. A missing sequence selects Fibonacci.
+func sequenceSpec(seq *api.Sequence) domain.SequenceSpec {
+	if seq == nil {
+		return domain.SequenceSpec{}
+	}
+
+	name := seq.GetName()
+	if name == "" {
+		name = sequenceNames[seq.GetKind()]
+	}
+
+	if name == service.SequenceFibonacci {
+		name = ""
 	}
 
-	return &api.FibonacciResponse{Values: res}, nil
+	return domain.SequenceSpec{
+		Name:         name,
+		K:            int(seq.GetK()),
+		Seeds:        seq.GetSeeds(),
+		Coefficients: seq.GetCoefficients(),
+	}
 }
 
-// MergeContexts combines two contexts into a single context.
+// MergeContexts combines two contexts into a single context that is done as soon as either is.
+// It keeps the values and deadline of c1, so an expired client deadline is reported as
+// context.DeadlineExceeded rather than a plain cancellation.
 func MergeContexts(c1, c2 context.Context) (context.Context, func()) {
-	mergedCtx, cancel := context.WithCancel(context.Background())
+	mergedCtx, cancel := context.WithCancel(c1)
+	stop := context.AfterFunc(c2, cancel)
 
-	go func() {
-		select {
-		case <-c1.Done():
-			cancel()
-		case <-c2.Done():
-			cancel()
-		case <-mergedCtx.Done():
-			return
-		}
-	}()
+	return mergedCtx, func() {
+		stop()
+		cancel()
+	}
+}
 
-	return mergedCtx, cancel
+// log returns the logger for a call: the request-scoped logger of its context, see LoggingUnaryInterceptor,
+// or else the server logger, naming the authenticated caller if there is one.
+func (s *FibonacciServer) log(ctx context.Context) logrus.FieldLogger {
+	logger := logging.Logger(ctx, s.logger)
+
+	id, ok := IdentityFromContext(ctx)
+	if !ok {
+		return logger
+	}
+
+	return logger.WithFields(logrus.Fields{"client": id.Name, "auth": id.Method})
+}
+
+// streamFields returns the log fields of a stream request.
+func streamFields(req *api.FibonacciStreamRequest) logrus.Fields {
+	return logrus.Fields{
+		"n":          req.GetN(),
+		"start":      req.GetStart(),
+		"end":        req.GetEnd(),
+		"modulus":    req.GetModulus(),
+		"chunk_size": req.GetChunkSize(),
+		"resumed":    req.GetResumeToken() != "",
+	}
 }
@@ -3,8 +3,10 @@ package server_test
 import (
 	"context"
 	"errors"
-	"net/http"
+	"fmt"
+	"io"
 	"testing"
+	"time"
 
 	"fibonacci/internal/domain"
 	"fibonacci/internal/genproto/fibonacci-service/api"
@@ -14,8 +16,11 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestFibonacciServer_Fibonacci(t *testing.T) {
@@ -29,7 +34,7 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 
 		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
 
-		mockService.EXPECT().GetFibonacci(mock.Anything, 10).Return([]string{"0", "1", "1", "2", "3", "5", "8", "13", "21", "34"}, nil)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 10}).Return([]string{"0", "1", "1", "2", "3", "5", "8", "13", "21", "34"}, nil)
 
 		req := &api.FibonacciRequest{N: 10}
 		res, err := s.Fibonacci(ctx, req)
@@ -39,6 +44,76 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 		assert.Equal(t, []string{"0", "1", "1", "2", "3", "5", "8", "13", "21", "34"}, res.Values)
 	})
 
+	t.Run("range", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{Start: 10, End: 13}).Return([]string{"55", "89", "144"}, nil)
+
+		res, err := s.Fibonacci(ctx, &api.FibonacciRequest{Start: 10, End: proto.Int32(13)})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"55", "89", "144"}, res.Values)
+
+		res, err = s.Fibonacci(ctx, &api.FibonacciRequest{Start: 10, N: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"55", "89", "144"}, res.Values)
+	})
+
+	t.Run("negative range ending at zero", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{Start: -3, End: 0}).Return([]string{"2", "-1", "1"}, nil)
+
+		res, err := s.Fibonacci(ctx, &api.FibonacciRequest{Start: -3, End: proto.Int32(0)})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2", "-1", "1"}, res.Values)
+	})
+
+	t.Run("sequence", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: "lucas"},
+			End:      3,
+		}).Return([]string{"2", "1", "3"}, nil)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: "custom", Seeds: []string{"1", "1"}, Coefficients: []int64{2, 1}},
+			End:      3,
+		}).Return([]string{"1", "1", "3"}, nil)
+
+		res, err := s.Fibonacci(ctx, &api.FibonacciRequest{N: 3, Sequence: &api.Sequence{Kind: api.SequenceKind_SEQUENCE_KIND_LUCAS}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2", "1", "3"}, res.Values)
+
+		res, err = s.Fibonacci(ctx, &api.FibonacciRequest{N: 3, Sequence: &api.Sequence{
+			Kind:         api.SequenceKind_SEQUENCE_KIND_CUSTOM,
+			Seeds:        []string{"1", "1"},
+			Coefficients: []int64{2, 1},
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "1", "3"}, res.Values)
+	})
+
 	t.Run("negative N", func(t *testing.T) {
 		ctx := context.Background()
 		globalCtx := context.Background()
@@ -49,13 +124,13 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 
 		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
 
-		mockService.EXPECT().GetFibonacci(mock.Anything, -5).Return(nil, domain.ErrNegativeN)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: -5}).Return(nil, domain.ErrInvalidRange)
 
 		req := &api.FibonacciRequest{N: -5}
 		res, err := s.Fibonacci(ctx, req)
 
 		assert.Nil(t, res)
-		assert.Equal(t, status.Errorf(http.StatusBadRequest, "Bad Request: %s", domain.ErrNegativeN).Error(), err.Error())
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 
 	t.Run("N too large", func(t *testing.T) {
@@ -68,13 +143,13 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 
 		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
 
-		mockService.EXPECT().GetFibonacci(mock.Anything, 101).Return(nil, domain.ErrTooLargeN)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 101}).Return(nil, domain.ErrTooLargeN)
 
 		req := &api.FibonacciRequest{N: 101}
 		res, err := s.Fibonacci(ctx, req)
 
 		assert.Nil(t, res)
-		assert.Equal(t, status.Errorf(http.StatusBadRequest, "Bad Request: %s", domain.ErrTooLargeN).Error(), err.Error())
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
 	})
 
 	t.Run("N too large", func(t *testing.T) {
@@ -87,13 +162,13 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 
 		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
 
-		mockService.EXPECT().GetFibonacci(mock.Anything, 101).Return(nil, domain.ErrTooLargeN)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 101}).Return(nil, domain.ErrTooLargeN)
 
 		req := &api.FibonacciRequest{N: 101}
 		res, err := s.Fibonacci(ctx, req)
 
 		assert.Nil(t, res)
-		assert.Equal(t, status.Errorf(http.StatusBadRequest, "Bad Request: %s", domain.ErrTooLargeN).Error(), err.Error())
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
 	})
 
 	t.Run("context canceled", func(t *testing.T) {
@@ -107,7 +182,7 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 
 		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
 
-		mockService.EXPECT().GetFibonacci(mock.Anything, 10).Return(nil, domain.ErrContextCanceled)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 10}).Return(nil, domain.ErrContextCanceled)
 
 		cancel()
 
@@ -115,7 +190,7 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 		res, err := s.Fibonacci(ctx, req)
 
 		assert.Nil(t, res)
-		assert.Equal(t, status.Errorf(http.StatusBadRequest, "Context canceled: %s", domain.ErrContextCanceled).Error(), err.Error())
+		assert.Equal(t, codes.Canceled, status.Code(err))
 	})
 
 	t.Run("service shut down", func(t *testing.T) {
@@ -128,14 +203,14 @@ func TestFibonacciServer_Fibonacci(t *testing.T) {
 
 		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
 
-		mockService.EXPECT().GetFibonacci(mock.Anything, 10).Return(nil, domain.ErrContextCanceled)
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 10}).Return(nil, domain.ErrContextCanceled)
 		globalCancel()
 
 		req := &api.FibonacciRequest{N: 10}
 		res, err := s.Fibonacci(ctx, req)
 
 		assert.Nil(t, res)
-		assert.Equal(t, status.Errorf(http.StatusServiceUnavailable, "Service unavailable: %s", domain.ErrContextCanceled).Error(), err.Error())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
 	})
 }
 
@@ -154,11 +229,11 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		mockService.EXPECT().
 			GetFibonacciStream(mock.Anything, mock.Anything).
 			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
-				assert.Equal(t, 10, r.N)
+				assert.Equal(t, 10, r.End)
 				assert.Equal(t, 4, r.ChunkSize)
-				err := r.SendFunc([]string{"0", "1", "1", "2"}, 0)
+				err := r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0", "1", "1", "2"}})
 				assert.NoError(t, err)
-				err = r.SendFunc([]string{"3", "5", "8", "13"}, 4)
+				err = r.SendFunc(domain.FibonacciChunk{Index: 4, Values: []string{"3", "5", "8", "13"}})
 				assert.NoError(t, err)
 				return nil
 			})
@@ -170,6 +245,57 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("range", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+		req := &api.FibonacciStreamRequest{Start: 10, End: proto.Int32(14), ChunkSize: 4}
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				assert.Equal(t, 10, r.Start)
+				assert.Equal(t, 14, r.End)
+				return r.SendFunc(domain.FibonacciChunk{Index: 10, Values: []string{"55", "89", "144", "233"}})
+			})
+
+		stream.EXPECT().Context().Return(context.Background())
+		stream.EXPECT().Send(&api.FibonacciChunk{Index: 10, Values: []string{"55", "89", "144", "233"}}).Return(nil).Once()
+
+		err := s.FibonacciStream(req, stream)
+		assert.NoError(t, err)
+	})
+
+	t.Run("resume", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+		req := &api.FibonacciStreamRequest{ChunkSize: 4, ResumeToken: "token"}
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				assert.Equal(t, "token", r.ResumeToken)
+				return r.SendFunc(domain.FibonacciChunk{Index: 10, Values: []string{"55", "89", "144", "233"}, ContinuationToken: "next"})
+			})
+
+		stream.EXPECT().Context().Return(context.Background())
+		stream.EXPECT().Send(&api.FibonacciChunk{Index: 10, Values: []string{"55", "89", "144", "233"}, ContinuationToken: "next"}).Return(nil).Once()
+
+		err := s.FibonacciStream(req, stream)
+		assert.NoError(t, err)
+	})
+
 	t.Run("invalid chunk size", func(t *testing.T) {
 		globalCtx := context.Background()
 
@@ -188,7 +314,7 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		stream.EXPECT().Context().Return(context.Background())
 
 		err := s.FibonacciStream(req, stream)
-		assert.EqualError(t, err, status.Errorf(http.StatusBadRequest, "Bad Request: %s", domain.ErrInvalidChunkSize).Error())
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 
 	t.Run("negative N", func(t *testing.T) {
@@ -204,12 +330,12 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 
 		mockService.EXPECT().
 			GetFibonacciStream(mock.Anything, mock.Anything).
-			Return(domain.ErrNegativeN)
+			Return(domain.ErrInvalidRange)
 
 		stream.EXPECT().Context().Return(context.Background())
 
 		err := s.FibonacciStream(req, stream)
-		assert.EqualError(t, err, status.Errorf(http.StatusBadRequest, "Bad Request: %s", domain.ErrNegativeN).Error())
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 
 	t.Run("N too large", func(t *testing.T) {
@@ -230,7 +356,7 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		stream.EXPECT().Context().Return(context.Background())
 
 		err := s.FibonacciStream(req, stream)
-		assert.EqualError(t, err, status.Errorf(http.StatusBadRequest, "Bad Request: %s", domain.ErrTooLargeN).Error())
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
 	})
 
 	t.Run("context canceled by request", func(t *testing.T) {
@@ -254,7 +380,7 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		stream.EXPECT().Context().Return(ctx)
 
 		err := s.FibonacciStream(req, stream)
-		assert.EqualError(t, err, status.Errorf(http.StatusBadRequest, "Context canceled: %s", domain.ErrContextCanceled).Error())
+		assert.Equal(t, codes.Canceled, status.Code(err))
 	})
 
 	t.Run("service shut down", func(t *testing.T) {
@@ -277,7 +403,7 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		stream.EXPECT().Context().Return(context.Background())
 
 		err := s.FibonacciStream(req, stream)
-		assert.EqualError(t, err, status.Errorf(http.StatusServiceUnavailable, "Service unavailable: %s", domain.ErrContextCanceled).Error())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
 	})
 
 	t.Run("internal server error", func(t *testing.T) {
@@ -298,6 +424,312 @@ func TestFibonacciServer_FibonacciStream(t *testing.T) {
 		stream.EXPECT().Context().Return(context.Background())
 
 		err := s.FibonacciStream(req, stream)
-		assert.EqualError(t, err, status.Errorf(http.StatusInternalServerError, "Internal server error: %s", "some internal error").Error())
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Equal(t, "some internal error", status.Convert(err).Message())
+	})
+}
+
+func TestFibonacciServer_FibonacciFlow(t *testing.T) {
+	start := &api.FibonacciFlowRequest{Command: &api.FibonacciFlowRequest_Start{Start: &api.FibonacciStreamRequest{N: 10, ChunkSize: 4}}}
+
+	t.Run("success", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciFlowServer(t)
+
+		stream.EXPECT().Recv().Return(start, nil).Once()
+		stream.EXPECT().Recv().Return(&api.FibonacciFlowRequest{Command: &api.FibonacciFlowRequest_ChunkSize{ChunkSize: 5}}, nil).Once()
+		stream.EXPECT().Recv().Return(&api.FibonacciFlowRequest{Command: &api.FibonacciFlowRequest_Seek{Seek: 3}}, nil).Once()
+		stream.EXPECT().Recv().Return(&api.FibonacciFlowRequest{Command: &api.FibonacciFlowRequest_Pause{Pause: true}}, nil).Once()
+		stream.EXPECT().Recv().Return(&api.FibonacciFlowRequest{Command: &api.FibonacciFlowRequest_Next{Next: 1}}, nil).Once()
+		stream.EXPECT().Recv().Return(nil, io.EOF).Once()
+		stream.EXPECT().Context().Return(context.Background())
+
+		mockService.EXPECT().
+			GetFibonacciFlow(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciFlowRequest) error {
+				assert.Equal(t, 10, r.Stream.End)
+				assert.Equal(t, 4, r.Stream.ChunkSize)
+
+				commands := []domain.FlowCommand{}
+				for cmd := range r.Commands {
+					commands = append(commands, cmd)
+				}
+
+				assert.Equal(t, []domain.FlowCommand{
+					{Kind: domain.FlowChunkSize, Value: 5},
+					{Kind: domain.FlowSeek, Value: 3},
+					{Kind: domain.FlowPause},
+					{Kind: domain.FlowNext, Value: 1},
+				}, commands)
+
+				return r.Stream.SendFunc(domain.FibonacciChunk{Index: 3, Values: []string{"2", "3", "5", "8", "13"}, ContinuationToken: "next"})
+			})
+
+		stream.EXPECT().Send(&api.FibonacciChunk{Index: 3, Values: []string{"2", "3", "5", "8", "13"}, ContinuationToken: "next"}).Return(nil).Once()
+
+		err := s.FibonacciFlow(stream)
+		assert.NoError(t, err)
+	})
+
+	t.Run("first message must start the stream", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciFlowServer(t)
+
+		stream.EXPECT().Recv().Return(&api.FibonacciFlowRequest{Command: &api.FibonacciFlowRequest_Next{Next: 1}}, nil).Once()
+
+		err := s.FibonacciFlow(stream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("invalid command", func(t *testing.T) {
+		for name, cmd := range map[string]*api.FibonacciFlowRequest{
+			"second start": {Command: &api.FibonacciFlowRequest_Start{}},
+			"pause false":  {Command: &api.FibonacciFlowRequest_Pause{Pause: false}},
+		} {
+			t.Run(name, func(t *testing.T) {
+				globalCtx := context.Background()
+
+				grpcServer := grpc.NewServer()
+				mockService := internalMock.NewService(t)
+				log := logrus.New()
+
+				s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+				stream := internalMock.NewFibonacciFlowServer(t)
+
+				stream.EXPECT().Recv().Return(start, nil).Once()
+				stream.EXPECT().Recv().Return(cmd, nil).Once()
+				stream.EXPECT().Recv().Return(nil, io.EOF).Maybe()
+				stream.EXPECT().Context().Return(context.Background())
+
+				mockService.EXPECT().
+					GetFibonacciFlow(mock.Anything, mock.Anything).
+					RunAndReturn(func(ctx context.Context, r domain.FibonacciFlowRequest) error {
+						cmd := <-r.Commands
+						assert.Equal(t, domain.FlowCommand{}, cmd)
+
+						return domain.ErrInvalidCommand
+					})
+
+				err := s.FibonacciFlow(stream)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			})
+		}
+	})
+
+	t.Run("broken stream cancels generation", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciFlowServer(t)
+
+		stream.EXPECT().Recv().Return(start, nil).Once()
+		stream.EXPECT().Recv().Return(nil, status.Error(codes.Canceled, "context canceled")).Once()
+		stream.EXPECT().Context().Return(context.Background())
+
+		mockService.EXPECT().
+			GetFibonacciFlow(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciFlowRequest) error {
+				<-ctx.Done()
+				return domain.ErrContextCanceled
+			})
+
+		err := s.FibonacciFlow(stream)
+		assert.Equal(t, codes.Canceled, status.Code(err))
+	})
+}
+
+func TestFibonacciServer_FibonacciNth(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetNth(mock.Anything, domain.FibonacciNthRequest{N: 100}).Return("354224848179261915075", nil)
+
+		req := &api.FibonacciNthRequest{N: 100}
+		res, err := s.FibonacciNth(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), res.N)
+		assert.Equal(t, "354224848179261915075", res.Value)
+	})
+
+	t.Run("too many digits", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetNth(mock.Anything, domain.FibonacciNthRequest{N: 10000000}).Return("", domain.ErrTooManyDigits)
+
+		req := &api.FibonacciNthRequest{N: 10000000}
+		res, err := s.FibonacciNth(ctx, req)
+
+		assert.Nil(t, res)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	})
+}
+
+func TestFibonacciServer_PisanoPeriod(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetPisanoPeriod(mock.Anything, uint64(10)).Return(60, nil)
+
+		res, err := s.PisanoPeriod(ctx, &api.PisanoPeriodRequest{Modulus: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), res.Modulus)
+		assert.Equal(t, uint64(60), res.Period)
+	})
+
+	t.Run("invalid modulus", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetPisanoPeriod(mock.Anything, uint64(0)).Return(0, domain.ErrInvalidModulus)
+
+		res, err := s.PisanoPeriod(ctx, &api.PisanoPeriodRequest{Modulus: 0})
+
+		assert.Nil(t, res)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestFibonacciServer_Errors(t *testing.T) {
+	t.Run("field violation", func(t *testing.T) {
+		ctx := context.Background()
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetFibonacci(mock.Anything, domain.FibonacciRequest{End: 101}).
+			Return(nil, domain.NewFieldError("n", fmt.Errorf("%w: must not exceed 100", domain.ErrTooLargeN)))
+
+		_, err := s.Fibonacci(ctx, &api.FibonacciRequest{N: 101})
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.ResourceExhausted, st.Code())
+		assert.Len(t, st.Details(), 1)
+
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		assert.Equal(t, "n", badRequest.GetFieldViolations()[0].GetField())
+		assert.Equal(t, "to large n: must not exceed 100", badRequest.GetFieldViolations()[0].GetDescription())
+	})
+
+	t.Run("chunk size violation", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			Return(domain.NewFieldError("chunk_size", fmt.Errorf("%w: must be at least 5", domain.ErrInvalidChunkSize)))
+
+		stream.EXPECT().Context().Return(context.Background())
+
+		err := s.FibonacciStream(&api.FibonacciStreamRequest{N: 10, ChunkSize: 1}, stream)
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+
+		badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		assert.Equal(t, "chunk_size", badRequest.GetFieldViolations()[0].GetField())
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+
+		mockService.EXPECT().GetNth(mock.Anything, domain.FibonacciNthRequest{N: 10}).
+			RunAndReturn(func(ctx context.Context, _ domain.FibonacciNthRequest) (string, error) {
+				<-ctx.Done()
+				return "", ctx.Err()
+			})
+
+		_, err := s.FibonacciNth(ctx, &api.FibonacciNthRequest{N: 10})
+
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("send error keeps its status", func(t *testing.T) {
+		globalCtx := context.Background()
+
+		grpcServer := grpc.NewServer()
+		mockService := internalMock.NewService(t)
+		log := logrus.New()
+
+		s := server.NewFibonacciServer(globalCtx, grpcServer, mockService, log)
+		stream := internalMock.NewFibonacciChunkStreamServer(t)
+
+		mockService.EXPECT().
+			GetFibonacciStream(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, r domain.FibonacciStreamRequest) error {
+				return r.SendFunc(domain.FibonacciChunk{Index: 0, Values: []string{"0"}})
+			})
+
+		stream.EXPECT().Context().Return(context.Background())
+		stream.EXPECT().Send(mock.Anything).Return(status.Error(codes.Unavailable, "transport is closing"))
+
+		err := s.FibonacciStream(&api.FibonacciStreamRequest{N: 1, ChunkSize: 4}, stream)
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
 	})
 }
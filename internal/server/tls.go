@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+)
+
+// reloadDelay is how long the files must stay unchanged before they are reloaded, so that a certificate
+// is not read while its files are still being written.
+const reloadDelay = 100 * time.Millisecond
+
+// TLSConfig selects the certificate a listener presents and, for mutual TLS, the CAs that must have issued
+// the certificates of its clients.
+type TLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string // PEM bundle of client CAs; clients need no certificate if empty
+	MinVersion   string // "1.2" or "1.3", 1.2 if empty
+}
+
+// CertReloader serves the certificate and client CAs of a TLSConfig, reloading them when their files change,
+// so that certificates can be rotated without a restart. Handshakes keep using the previous files
+// until the new ones load.
+type CertReloader struct {
+	cfg        TLSConfig
+	minVersion uint16
+	logger     *logrus.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool // Nil unless clients must present a certificate
+}
+
+// NewCertReloader loads the files of cfg.
+func NewCertReloader(cfg TLSConfig, logger *logrus.Logger) (*CertReloader, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	r := &CertReloader{cfg: cfg, logger: logger}
+
+	switch cfg.MinVersion {
+	case "", "1.2":
+		r.minVersion = tls.VersionTLS12
+	case "1.3":
+		r.minVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unsupported minimum TLS version %q, expected 1.2 or 1.3", cfg.MinVersion)
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the certificate and client CAs from their files.
+func (r *CertReloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("%s: no certificates found", r.cfg.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCAs = &cert, clientCAs
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Watch reloads the files whenever they change, until ctx is done. Failed reloads are logged.
+//
+// The directories of the files are watched rather than the files themselves, as files are usually
+// replaced rather than written in place, e.g. Kubernetes swaps a symlink to a new directory.
+func (r *CertReloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	dirs := map[string]bool{}
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if path == "" || dirs[filepath.Dir(path)] {
+			continue
+		}
+
+		dirs[filepath.Dir(path)] = true
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		// Changes come in bursts, e.g. the certificate and then its key, so the files are only reloaded once they settle.
+		timer := time.NewTimer(0)
+		<-timer.C
+
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-watcher.Events:
+				timer.Reset(reloadDelay)
+			case err := <-watcher.Errors:
+				r.logger.Errorf("Error watching TLS files: %v", err)
+			case <-timer.C:
+				if err := r.Reload(); err != nil {
+					r.logger.Errorf("Failed to reload TLS files, keeping the previous ones: %v", err)
+				} else {
+					r.logger.Infof("Reloaded TLS certificate %s", r.cfg.CertFile)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// TLSConfig returns a config presenting the current certificate and, for mutual TLS,
+// requiring client certificates issued by the current client CAs.
+func (r *CertReloader) TLSConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: r.minVersion,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return r.cert, nil
+		},
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		// The client CAs may change, so the chain is verified against the current ones rather than fixed ClientCAs.
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyConnection = r.verifyClient
+	}
+
+	return cfg
+}
+
+// verifyClient checks that the client certificate was issued by one of the current client CAs.
+func (r *CertReloader) verifyClient(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("client certificate required")
+	}
+
+	r.mu.RLock()
+	roots := r.clientCAs
+	r.mu.RUnlock()
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range cs.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	return err
+}
@@ -0,0 +1,212 @@
+package server_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"fibonacci/internal/server"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCert is a certificate w/ its key, issued by parent or self-signed if parent is nil.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, serial int64, parent *testCert, usage x509.ExtKeyUsage) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	issuer, signer := tmpl, key
+	if parent == nil {
+		tmpl.IsCA, tmpl.BasicConstraintsValid = true, true
+	} else {
+		issuer, signer = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, &key.PublicKey, signer)
+	assert.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// write writes the certificate and its key as PEM files.
+func (c *testCert) write(t *testing.T, certFile, keyFile string) {
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o644))
+	if keyFile != "" {
+		assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	}
+}
+
+func (c *testCert) tls() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+
+	ca := newTestCert(t, 1, nil, x509.ExtKeyUsageClientAuth)
+	ca.write(t, caFile, "")
+
+	serverCA := newTestCert(t, 2, nil, x509.ExtKeyUsageServerAuth)
+	newTestCert(t, 10, serverCA, x509.ExtKeyUsageServerAuth).write(t, certFile, keyFile)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCA.cert)
+
+	// serve accepts TLS connections w/ cfg, completing the handshake of each.
+	serve := func(t *testing.T, cfg *tls.Config) string {
+		lis, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+		assert.NoError(t, err)
+		t.Cleanup(func() { lis.Close() })
+
+		go func() {
+			for {
+				conn, err := lis.Accept()
+				if err != nil {
+					return
+				}
+
+				_ = conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}
+		}()
+
+		return lis.Addr().String()
+	}
+
+	// dial returns the serial number of the server certificate, or the handshake error.
+	dial := func(addr string, client *tls.Config) (int64, error) {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: time.Second}, "tcp", addr, client)
+		if err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+
+		// TLS 1.3 reports a rejected client certificate only once the client reads.
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		if _, err := conn.Read(make([]byte, 1)); err != nil && !isEOF(err) {
+			return 0, err
+		}
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+	}
+
+	t.Run("server certificate reloads on change", func(t *testing.T) {
+		r, err := server.NewCertReloader(server.TLSConfig{CertFile: certFile, KeyFile: keyFile}, logrus.New())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		assert.NoError(t, r.Watch(ctx))
+
+		addr := serve(t, r.TLSConfig())
+
+		serial, err := dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), serial)
+
+		// Invalid files are ignored until they are complete.
+		assert.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+		newTestCert(t, 11, serverCA, x509.ExtKeyUsageServerAuth).write(t, certFile, keyFile)
+
+		assert.Eventually(t, func() bool {
+			serial, err := dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+			return err == nil && serial == 11
+		}, 5*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("mutual TLS", func(t *testing.T) {
+		r, err := server.NewCertReloader(server.TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}, logrus.New())
+		assert.NoError(t, err)
+
+		addr := serve(t, r.TLSConfig())
+		client := newTestCert(t, 20, ca, x509.ExtKeyUsageClientAuth).tls()
+		otherCA := newTestCert(t, 3, nil, x509.ExtKeyUsageClientAuth)
+		other := newTestCert(t, 21, otherCA, x509.ExtKeyUsageClientAuth).tls()
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{client}})
+		assert.NoError(t, err)
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.Error(t, err)
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{other}})
+		assert.Error(t, err)
+
+		// Reloaded client CAs apply to the following handshakes.
+		otherCA.write(t, caFile, "")
+		assert.NoError(t, r.Reload())
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{other}})
+		assert.NoError(t, err)
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{client}})
+		assert.Error(t, err)
+	})
+
+	t.Run("minimum version", func(t *testing.T) {
+		r, err := server.NewCertReloader(server.TLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"}, logrus.New())
+		assert.NoError(t, err)
+
+		addr := serve(t, r.TLSConfig())
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost", MaxVersion: tls.VersionTLS12})
+		assert.Error(t, err)
+
+		_, err = dial(addr, &tls.Config{RootCAs: roots, ServerName: "localhost"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		for _, cfg := range []server.TLSConfig{
+			{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.1"},
+			{CertFile: certFile, KeyFile: filepath.Join(dir, "missing.key")},
+			{CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile},
+		} {
+			_, err := server.NewCertReloader(cfg, logrus.New())
+			assert.Error(t, err, "%+v", cfg)
+		}
+	})
+}
+
+// isEOF reports whether a read ended w/o a TLS alert, as the server closed the connection or sent nothing.
+func isEOF(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, io.EOF) || (errors.As(err, &netErr) && netErr.Timeout())
+}
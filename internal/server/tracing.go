@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// tracer traces the handlers with the global tracer provider, see tracing.Setup.
+var tracer = otel.Tracer("fibonacci/internal/server")
+
+// TraceUnaryInterceptor continues the trace of the caller, whose context is propagated in the metadata of the call.
+func TraceUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(extractTrace(ctx), req)
+	}
+}
+
+// TraceStreamInterceptor continues the trace of the caller of a streaming call, see TraceUnaryInterceptor.
+func TraceStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{ServerStream: ss, ctx: extractTrace(ss.Context())})
+	}
+}
+
+// extractTrace returns ctx w/ the trace context propagated in its incoming metadata, if any.
+func extractTrace(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+}
+
+// traceMiddleware continues the trace of the caller of HTTP requests, propagated in their headers.
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// metadataCarrier reads and writes the propagated trace context in gRPC metadata.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
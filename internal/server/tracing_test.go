@@ -0,0 +1,140 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fibonacci/internal/genproto/fibonacci-service/api"
+	"fibonacci/internal/server"
+	"fibonacci/internal/service"
+	"fibonacci/internal/tracing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// exportedSpan is a span as written by the file exporter.
+type exportedSpan struct {
+	Name        string
+	SpanContext struct{ TraceID, SpanID string }
+	Parent      struct{ TraceID, SpanID string }
+	Attributes  []struct {
+		Key   string
+		Value struct{ Value any }
+	}
+	Status struct{ Code string }
+}
+
+func (s exportedSpan) attr(key string) any {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value.Value
+		}
+	}
+
+	return nil
+}
+
+// The tracer provider can only be installed once per process, so tracing is tested as a whole here.
+func TestTracing(t *testing.T) {
+	const (
+		traceID  = "4bf92f3577b34da6a3ce929d0e0e4736"
+		parentID = "00f067aa0ba902b7"
+	)
+
+	file := filepath.Join(t.TempDir(), "traces.jsonl")
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterFile, File: file, ServiceName: "test", SampleRatio: 1})
+	assert.NoError(t, err)
+
+	s := server.NewFibonacciServer(context.Background(), grpc.NewServer(), service.NewService(10, 1, 100, 100, 1000), logrus.New())
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", "00-"+traceID+"-"+parentID+"-01"))
+
+	err = server.TraceStreamInterceptor()(nil, &fakeStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		return s.FibonacciStream(&api.FibonacciStreamRequest{N: 25, ChunkSize: 10}, &grpc.GenericServerStream[api.FibonacciStreamRequest, api.FibonacciChunk]{ServerStream: ss})
+	})
+	assert.NoError(t, err)
+
+	_, err = server.TraceUnaryInterceptor()(ctx, &api.FibonacciNthRequest{N: 10000}, &grpc.UnaryServerInfo{},
+		func(ctx context.Context, req any) (any, error) {
+			return s.FibonacciNth(ctx, req.(*api.FibonacciNthRequest))
+		})
+	assert.Error(t, err)
+
+	assert.NoError(t, shutdown(context.Background()))
+
+	f, err := os.Open(file)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	byName := map[string][]exportedSpan{}
+	byID := map[string]exportedSpan{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var span exportedSpan
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		assert.Equal(t, traceID, span.SpanContext.TraceID, span.Name)
+
+		byName[span.Name] = append(byName[span.Name], span)
+		byID[span.SpanContext.SpanID] = span
+	}
+	assert.NoError(t, scanner.Err())
+
+	parent := func(span exportedSpan) string {
+		return byID[span.Parent.SpanID].Name
+	}
+
+	t.Run("handlers continue the trace of the caller", func(t *testing.T) {
+		assert.Len(t, byName["FibonacciServer/FibonacciStream"], 1)
+		assert.Len(t, byName["FibonacciServer/FibonacciNth"], 1)
+
+		for _, span := range append(byName["FibonacciServer/FibonacciStream"], byName["FibonacciServer/FibonacciNth"]...) {
+			assert.Equal(t, parentID, span.Parent.SpanID)
+		}
+	})
+
+	t.Run("chunks record compute and send time", func(t *testing.T) {
+		stream := byName["GetFibonacciStream"]
+		assert.Len(t, stream, 1)
+		assert.Equal(t, "FibonacciServer/FibonacciStream", parent(stream[0]))
+		assert.EqualValues(t, 25, stream[0].attr("fibonacci.end"))
+
+		chunks := byName["chunk"]
+		assert.Len(t, chunks, 3)
+
+		// F(0)..F(9) have 13 digits, F(10)..F(19) 31 and F(20)..F(24) 24.
+		for i, want := range []struct{ index, terms, digits int }{{0, 10, 13}, {10, 10, 31}, {20, 5, 24}} {
+			chunk := chunks[i]
+			assert.Equal(t, "GetFibonacciStream", parent(chunk))
+			assert.EqualValues(t, i, chunk.attr("fibonacci.chunk.number"))
+			assert.EqualValues(t, want.index, chunk.attr("fibonacci.chunk.index"))
+			assert.EqualValues(t, want.terms, chunk.attr("fibonacci.chunk.terms"))
+			assert.EqualValues(t, want.digits, chunk.attr("fibonacci.digits"))
+		}
+
+		for _, name := range []string{"compute", "send"} {
+			assert.Len(t, byName[name], 3, name)
+			for _, span := range byName[name] {
+				assert.Equal(t, "chunk", parent(span))
+			}
+		}
+	})
+
+	t.Run("errors are recorded", func(t *testing.T) {
+		nth := byName["GetNth"]
+		assert.Len(t, nth, 1)
+		assert.Equal(t, "FibonacciServer/FibonacciNth", parent(nth[0]))
+		assert.Equal(t, "Error", nth[0].Status.Code)
+
+		// Requests over the limits are the fault of the client rather than the handler.
+		handler := byName["FibonacciServer/FibonacciNth"][0]
+		assert.EqualValues(t, 8, handler.attr("rpc.grpc.status_code"))
+		assert.Equal(t, "Unset", handler.Status.Code)
+	})
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"fibonacci/internal/domain"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// batchSeekThreshold is the largest gap between two spans of a batch that is walked through term by term.
+// Larger gaps are skipped by seeding the state at the next span, which costs about as much as
+// a few hundred additions of large terms.
+const batchSeekThreshold = 1000
+
+// batchCheckInterval is the number of terms generated between cancellation checks.
+const batchCheckInterval = 1000
+
+// batchQuery is an item of a batch that passed validation: the terms a(first)..a(last) it needs.
+type batchQuery struct {
+	item    int // Index of the item in the batch
+	spec    domain.SequenceSpec
+	seq     Sequence
+	modulus uint64
+	first   int
+	last    int
+	empty   bool  // Whether the item is an empty range, which needs no terms
+	nth     bool  // Whether the item requests a single term
+	digits  int64 // Estimated number of digits the item returns, see rangeDigits and termDigits
+}
+
+// batchGroup collects the queries on the same sequence and modulus, which are answered by a single pass.
+type batchGroup struct {
+	key     string // See sequenceKey
+	seq     Sequence
+	modulus uint64
+	queries []batchQuery
+	spans   []batchSpan // Sorted, disjoint and non-adjacent ranges of terms covering the queries
+}
+
+// batchSpan is a range of consecutive terms a(first)..a(last) needed by the queries of a group.
+type batchSpan struct {
+	first int
+	last  int
+	terms []string
+}
+
+func (s *fibonacciService) GetFibonacciBatch(ctx context.Context, items []domain.BatchItem) (_ []domain.BatchResult, err error) {
+	ctx, span := tracer.Start(ctx, "GetFibonacciBatch", trace.WithAttributes(attrItems.Int(len(items))))
+	defer func() { endSpan(span, err) }()
+
+	if len(items) > s.batchItemsLimit {
+		return nil, domain.NewFieldError("items", fmt.Errorf("%w: must not exceed %d items", domain.ErrTooLargeBatch, s.batchItemsLimit))
+	}
+
+	results := make([]domain.BatchResult, len(items))
+
+	var (
+		groups = map[string]*batchGroup{}
+		order  []*batchGroup // Groups in order of appearance, so the work done does not depend on map order
+		digits int64         // Estimated digits of the response, counting the terms repeated by items
+	)
+
+	for i, item := range items {
+		q, err := s.batchQuery(ctx, item)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		q.item = i
+		digits += q.digits
+
+		key := sequenceKey(q.spec, q.modulus)
+		g, ok := groups[key]
+		if !ok {
+			g = &batchGroup{key: key, seq: q.seq, modulus: q.modulus}
+			groups[key] = g
+			order = append(order, g)
+		}
+
+		g.queries = append(g.queries, q)
+	}
+
+	terms := 0
+	for _, g := range order {
+		g.merge()
+
+		for _, bs := range g.spans {
+			terms += bs.last - bs.first + 1
+		}
+	}
+
+	span.SetAttributes(attrTerms.Int(terms))
+
+	start := time.Now()
+
+	if terms > s.batchTermsLimit {
+		return nil, domain.NewFieldError("items", fmt.Errorf("%w: needs %d distinct terms, must not exceed %d", domain.ErrTooLargeBatch, terms, s.batchTermsLimit))
+	}
+
+	if s.batchDigitsLimit > 0 && digits > s.batchDigitsLimit {
+		return nil, domain.NewFieldError("items", fmt.Errorf("%w: returns an estimated %d digits, must not exceed %d", domain.ErrTooLargeBatch, digits, s.batchDigitsLimit))
+	}
+
+	// A batch is a single request, so its items share the response budget.
+	if s.requestBytesLimit > 0 && digits > s.requestBytesLimit {
+		return nil, domain.NewFieldError("items", fmt.Errorf("%w: estimated %d bytes, must not exceed %d", domain.ErrTooLargeResponse, digits, s.requestBytesLimit))
+	}
+
+	for _, g := range order {
+		if err := g.compute(ctx, s); err != nil {
+			return nil, err
+		}
+
+		for _, q := range g.queries {
+			results[q.item] = g.result(q)
+		}
+	}
+
+	observe("FibonacciBatch", uint64(terms), start)
+
+	return results, nil
+}
+
+// batchQuery validates an item like the equivalent single call and returns the terms it needs.
+func (s *fibonacciService) batchQuery(ctx context.Context, item domain.BatchItem) (batchQuery, error) {
+	switch {
+	case item.Range != nil && item.Nth == nil:
+		req := item.Range
+
+		r, err := s.newSequenceRange(req.Sequence, req.Start, req.End, req.Modulus, s.nLimit(ctx))
+		if err != nil {
+			return batchQuery{}, err
+		}
+
+		return batchQuery{
+			spec:    req.Sequence,
+			seq:     r.seq,
+			modulus: req.Modulus,
+			first:   r.start,
+			last:    r.end - 1,
+			empty:   r.end == r.start,
+			digits:  rangeDigits(r.seq, r.start, r.end, req.Modulus),
+		}, nil
+
+	case item.Nth != nil && item.Range == nil:
+		req := item.Nth
+
+		seq, err := s.nthSequence(*req)
+		if err != nil {
+			return batchQuery{}, err
+		}
+
+		return batchQuery{
+			spec:    req.Sequence,
+			seq:     seq,
+			modulus: req.Modulus,
+			first:   req.N,
+			last:    req.N,
+			nth:     true,
+			digits:  termDigits(seq, req.N, req.Modulus),
+		}, nil
+
+	default:
+		return batchQuery{}, fmt.Errorf("%w: exactly one of range and nth must be set", domain.ErrInvalidBatchItem)
+	}
+}
+
+// merge computes the spans covering the queries, joining those that overlap or touch.
+func (g *batchGroup) merge() {
+	for _, q := range g.queries {
+		if !q.empty {
+			g.spans = append(g.spans, batchSpan{first: q.first, last: q.last})
+		}
+	}
+
+	slices.SortFunc(g.spans, func(a, b batchSpan) int {
+		return cmp.Compare(a.first, b.first)
+	})
+
+	merged := g.spans[:0]
+	for _, span := range g.spans {
+		if n := len(merged); n > 0 && merged[n-1].last < math.MaxInt && span.first <= merged[n-1].last+1 {
+			merged[n-1].last = max(merged[n-1].last, span.last)
+			continue
+		}
+
+		merged = append(merged, span)
+	}
+
+	g.spans = merged
+}
+
+// compute generates the terms of every span in a single pass, walking through small gaps between
+// spans and seeding the state again at the start of a span after a large one.
+func (g *batchGroup) compute(ctx context.Context, s *fibonacciService) error {
+	var (
+		state sequenceState
+		pos   int // Index of the current term of state
+	)
+
+	for i := range g.spans {
+		span := &g.spans[i]
+
+		if state == nil || uint64(span.first-pos) > batchSeekThreshold {
+			var err error
+			if state, err = s.seed(ctx, g.key, g.seq, span.first, g.modulus); err != nil {
+				return err
+			}
+
+			pos = span.first
+		}
+
+		for ; pos < span.first; pos++ {
+			state.advance()
+		}
+
+		span.terms = make([]string, 0, span.last-span.first+1)
+
+		for {
+			if len(span.terms)%batchCheckInterval == 0 {
+				if err := contextError(ctx); err != nil {
+					return err
+				}
+			}
+
+			span.terms = append(span.terms, state.text())
+			if pos == span.last {
+				break
+			}
+
+			state.advance()
+			pos++
+		}
+	}
+
+	return nil
+}
+
+// result returns the terms of a query from the span containing them.
+func (g *batchGroup) result(q batchQuery) domain.BatchResult {
+	if q.empty {
+		return domain.BatchResult{Values: []string{}}
+	}
+
+	i := sort.Search(len(g.spans), func(i int) bool {
+		return g.spans[i].last >= q.first
+	})
+	span := g.spans[i]
+
+	if q.nth {
+		return domain.BatchResult{Value: span.terms[q.first-span.first]}
+	}
+
+	// Results share the terms of their span, clipped so that appending to one cannot overwrite another.
+	return domain.BatchResult{Values: slices.Clip(span.terms[q.first-span.first : q.last-span.first+1])}
+}
@@ -0,0 +1,166 @@
+package service_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFibonacciBatch(t *testing.T) {
+	s := service.NewService(10, 2, 5000, 100, 1000, service.WithBatchLimits(100, 20000, 1_000_000))
+
+	rangeItem := func(req domain.FibonacciRequest) domain.BatchItem { return domain.BatchItem{Range: &req} }
+	nthItem := func(req domain.FibonacciNthRequest) domain.BatchItem { return domain.BatchItem{Nth: &req} }
+
+	// assertMatchesSingleCalls checks every result against the equivalent single call.
+	assertMatchesSingleCalls := func(t *testing.T, items []domain.BatchItem, results []domain.BatchResult) {
+		assert.Len(t, results, len(items))
+
+		for i, item := range items {
+			if item.Range != nil {
+				values, err := s.GetFibonacci(context.Background(), *item.Range)
+				assert.Equal(t, err, results[i].Err, "item %d", i)
+				assert.Equal(t, values, results[i].Values, "item %d", i)
+			} else {
+				value, err := s.GetNth(context.Background(), *item.Nth)
+				assert.Equal(t, err, results[i].Err, "item %d", i)
+				assert.Equal(t, value, results[i].Value, "item %d", i)
+			}
+		}
+	}
+
+	t.Run("mixed items", func(t *testing.T) {
+		lucas := domain.SequenceSpec{Name: service.SequenceLucas}
+		items := []domain.BatchItem{
+			rangeItem(domain.FibonacciRequest{End: 10}),
+			rangeItem(domain.FibonacciRequest{Start: 5, End: 15}),
+			rangeItem(domain.FibonacciRequest{Start: 15, End: 20}),
+			rangeItem(domain.FibonacciRequest{Start: -10, End: -5}),
+			rangeItem(domain.FibonacciRequest{Start: 3000, End: 3010}),
+			rangeItem(domain.FibonacciRequest{Start: 7, End: 7}),
+			nthItem(domain.FibonacciNthRequest{N: 12}),
+			nthItem(domain.FibonacciNthRequest{N: 4000}),
+			nthItem(domain.FibonacciNthRequest{N: -3}),
+			rangeItem(domain.FibonacciRequest{End: 30, Modulus: 7}),
+			nthItem(domain.FibonacciNthRequest{N: 1_000_000_000_000, Modulus: 7}),
+			rangeItem(domain.FibonacciRequest{Sequence: lucas, Start: 2, End: 12}),
+			nthItem(domain.FibonacciNthRequest{Sequence: lucas, N: 5}),
+		}
+
+		results, err := s.GetFibonacciBatch(context.Background(), items)
+
+		assert.NoError(t, err)
+		assertMatchesSingleCalls(t, items, results)
+		assert.Equal(t, "144", results[6].Value)
+		assert.Equal(t, []string{}, results[5].Values)
+	})
+
+	t.Run("random items", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1))
+		specs := []domain.SequenceSpec{{}, {Name: service.SequencePell}, {Name: service.SequenceTribonacci}}
+
+		items := make([]domain.BatchItem, 100)
+		for i := range items {
+			spec := specs[rng.Intn(len(specs))]
+			modulus := []uint64{0, 0, 1_000_003}[rng.Intn(3)]
+			start := rng.Intn(1200) - 200
+
+			if rng.Intn(3) == 0 {
+				items[i] = nthItem(domain.FibonacciNthRequest{Sequence: spec, N: start, Modulus: modulus})
+			} else {
+				items[i] = rangeItem(domain.FibonacciRequest{Sequence: spec, Start: start, End: start + rng.Intn(100), Modulus: modulus})
+			}
+		}
+
+		results, err := s.GetFibonacciBatch(context.Background(), items)
+
+		assert.NoError(t, err)
+		assertMatchesSingleCalls(t, items, results)
+	})
+
+	t.Run("items fail individually", func(t *testing.T) {
+		items := []domain.BatchItem{
+			rangeItem(domain.FibonacciRequest{End: 5}),
+			rangeItem(domain.FibonacciRequest{Start: 10, End: 5}),
+			rangeItem(domain.FibonacciRequest{End: 5001}),
+			nthItem(domain.FibonacciNthRequest{N: 10000}),
+			nthItem(domain.FibonacciNthRequest{Sequence: domain.SequenceSpec{Name: "unknown"}, N: 1}),
+			{},
+			nthItem(domain.FibonacciNthRequest{N: 6}),
+		}
+
+		results, err := s.GetFibonacciBatch(context.Background(), items)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0", "1", "1", "2", "3"}, results[0].Values)
+		assert.ErrorIs(t, results[1].Err, domain.ErrInvalidRange)
+		assert.ErrorIs(t, results[2].Err, domain.ErrTooLargeN)
+		assert.ErrorIs(t, results[3].Err, domain.ErrTooManyDigits)
+		assert.ErrorIs(t, results[4].Err, domain.ErrInvalidSequence)
+		assert.ErrorIs(t, results[5].Err, domain.ErrInvalidBatchItem)
+		assert.Equal(t, "8", results[6].Value)
+	})
+
+	t.Run("too many items", func(t *testing.T) {
+		items := make([]domain.BatchItem, 101)
+		for i := range items {
+			items[i] = nthItem(domain.FibonacciNthRequest{N: i})
+		}
+
+		_, err := s.GetFibonacciBatch(context.Background(), items)
+		assert.ErrorIs(t, err, domain.ErrTooLargeBatch)
+	})
+
+	t.Run("too many terms", func(t *testing.T) {
+		items := make([]domain.BatchItem, 5)
+		for i := range items {
+			items[i] = rangeItem(domain.FibonacciRequest{Start: i * 5000, End: i*5000 + 4500, Modulus: 10})
+		}
+
+		_, err := s.GetFibonacciBatch(context.Background(), items)
+		assert.ErrorIs(t, err, domain.ErrTooLargeBatch)
+
+		// Overlapping ranges count once.
+		for i := range items {
+			items[i] = rangeItem(domain.FibonacciRequest{Start: i, End: i + 4500, Modulus: 10})
+		}
+
+		_, err = s.GetFibonacciBatch(context.Background(), items)
+		assert.NoError(t, err)
+	})
+
+	t.Run("too many digits", func(t *testing.T) {
+		// F(450) has 94 digits, so the items stay within their own limits while repeating the same term.
+		items := make([]domain.BatchItem, 100)
+		for i := range items {
+			items[i] = nthItem(domain.FibonacciNthRequest{N: 450})
+		}
+
+		_, err := s.GetFibonacciBatch(context.Background(), items)
+		assert.NoError(t, err)
+
+		// The same range repeated is computed once, but returned by every item.
+		for i := range items {
+			items[i] = rangeItem(domain.FibonacciRequest{Start: 0, End: 500})
+		}
+
+		_, err = s.GetFibonacciBatch(context.Background(), items[:1])
+		assert.NoError(t, err)
+
+		_, err = s.GetFibonacciBatch(context.Background(), items)
+		assert.ErrorIs(t, err, domain.ErrTooLargeBatch)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := s.GetFibonacciBatch(ctx, []domain.BatchItem{rangeItem(domain.FibonacciRequest{End: 10})})
+		assert.ErrorIs(t, err, domain.ErrContextCanceled)
+	})
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/metrics"
+)
+
+// CacheConfig configures the checkpoint cache, see WithCache.
+type CacheConfig struct {
+	Interval        int   // Distance between checkpoints, disables them unless positive
+	CheckpointBytes int64 // Maximum size of the saved checkpoints
+	PrefixBytes     int64 // Maximum size of the cached prefixes, disables them unless positive
+}
+
+// WithCache makes the service remember the state of every unreduced sequence at the multiples of
+// cfg.Interval it passes, and the longest prefix a(0)..a(n-1) of each sequence GetFibonacci returned.
+// Requests starting near a checkpoint then advance from it instead of seeding the state from scratch,
+// and GetFibonacci answers ranges within a cached prefix without computing anything.
+// Both caches evict the least recently used entries once they exceed their size.
+func WithCache(cfg CacheConfig) Option {
+	return func(s *fibonacciService) {
+		c := &checkpointCache{interval: cfg.Interval}
+
+		if cfg.Interval > 0 {
+			c.checkpoints = newLRU[checkpointKey, []byte](cfg.CheckpointBytes)
+		}
+
+		if cfg.PrefixBytes > 0 {
+			c.prefixes = newLRU[string, []string](cfg.PrefixBytes)
+		}
+
+		s.cache = c
+	}
+}
+
+// checkpointKey identifies the saved state of a sequence at an index.
+type checkpointKey struct {
+	seq   string // See sequenceKey
+	index int
+}
+
+// checkpointCache holds the checkpoints and prefixes of the sequences computed by the service.
+// It is safe for concurrent use, and a nil cache caches nothing.
+type checkpointCache struct {
+	interval    int
+	checkpoints *lru[checkpointKey, []byte] // States saved by sequenceState.save, nil if disabled
+	prefixes    *lru[string, []string]      // Longest prefix of each sequence, nil if disabled
+}
+
+// sequenceKey identifies a sequence reduced modulo modulus, so that requests for the same one share cached terms.
+func sequenceKey(spec domain.SequenceSpec, modulus uint64) string {
+	return fmt.Sprintf("%q %d %q %v %d", spec.Name, spec.K, spec.Seeds, spec.Coefficients, modulus)
+}
+
+// stateAt returns a state positioned at a(start), advanced from the checkpoint just before start if there is one,
+// which records the checkpoints it passes.
+// Reduced sequences are seeded directly, as that takes a few arithmetic operations on machine words.
+func (c *checkpointCache) stateAt(ctx context.Context, key string, seq Sequence, start int, modulus uint64) (sequenceState, error) {
+	if !c.tracks(modulus) {
+		return newStateAt(ctx, seq, start, modulus)
+	}
+
+	state := c.restore(key, seq, start)
+	if state == nil {
+		var err error
+		if state, err = newStateAt(ctx, seq, start, modulus); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.track(key, state, start, modulus), nil
+}
+
+// tracks reports whether checkpoints are kept for sequences reduced modulo modulus.
+func (c *checkpointCache) tracks(modulus uint64) bool {
+	return c != nil && c.checkpoints != nil && modulus == 0
+}
+
+// restore returns the state at a(start) advanced from the checkpoint at or below start,
+// or nil when it is not cached.
+func (c *checkpointCache) restore(key string, seq Sequence, start int) sequenceState {
+	index := start - mod(start, c.interval)
+	if index == 0 {
+		// Seeds are always at hand, so there is nothing to look up.
+		return nil
+	}
+
+	saved, ok := c.checkpoints.get(checkpointKey{seq: key, index: index})
+	if !ok {
+		metrics.CacheMissesTotal.WithLabelValues("checkpoint").Inc()
+		return nil
+	}
+
+	state, err := restoreState(seq, 0, saved)
+	if err != nil {
+		// Checkpoints are saved by the service itself, so this cannot happen short of a bug,
+		// in which case seeding the state from scratch is still correct.
+		return nil
+	}
+
+	metrics.CacheHitsTotal.WithLabelValues("checkpoint").Inc()
+
+	for ; index < start; index++ {
+		state.advance()
+	}
+
+	return state
+}
+
+// track wraps a state at a(position) so that it records the checkpoints it passes.
+func (c *checkpointCache) track(key string, state sequenceState, position int, modulus uint64) sequenceState {
+	if !c.tracks(modulus) {
+		return state
+	}
+
+	return &checkpointState{sequenceState: state, cache: c, key: key, position: position}
+}
+
+// record saves state as the checkpoint at index, unless it is already cached.
+func (c *checkpointCache) record(key string, index int, state sequenceState) {
+	k := checkpointKey{seq: key, index: index}
+	if c.checkpoints.contains(k) {
+		return
+	}
+
+	saved := state.save(nil)
+	c.checkpoints.add(k, saved, int64(len(saved)))
+}
+
+// prefix returns the terms a(start)..a(end-1) from the cached prefix of the sequence, if it covers them.
+// The result is a copy, so callers may modify it.
+func (c *checkpointCache) prefix(key string, start, end int) ([]string, bool) {
+	if c == nil || c.prefixes == nil || start < 0 {
+		return nil, false
+	}
+
+	terms, ok := c.prefixes.get(key)
+	if !ok || end > len(terms) {
+		metrics.CacheMissesTotal.WithLabelValues("prefix").Inc()
+		return nil, false
+	}
+
+	metrics.CacheHitsTotal.WithLabelValues("prefix").Inc()
+
+	return slices.Clone(terms[start:end]), true
+}
+
+// addPrefix caches the terms a(0)..a(n-1) of the sequence unless a longer prefix is cached already.
+// The terms are copied, so callers may keep modifying them.
+func (c *checkpointCache) addPrefix(key string, terms []string) {
+	if c == nil || c.prefixes == nil {
+		return
+	}
+
+	if cached, ok := c.prefixes.get(key); ok && len(cached) >= len(terms) {
+		return
+	}
+
+	size := int64(0)
+	for _, term := range terms {
+		size += int64(len(term)) + stringHeaderSize
+	}
+
+	c.prefixes.add(key, slices.Clone(terms), size)
+}
+
+// stringHeaderSize is the memory taken by a string besides its bytes.
+const stringHeaderSize = 16
+
+// checkpointState is a sequence state that saves itself into the cache whenever it reaches a checkpoint.
+type checkpointState struct {
+	sequenceState
+	cache    *checkpointCache
+	key      string
+	position int // Index of the current term
+}
+
+func (s *checkpointState) advance() {
+	s.sequenceState.advance()
+	s.position++
+
+	if s.position%s.cache.interval == 0 {
+		s.cache.record(s.key, s.position, s.sequenceState)
+	}
+}
+
+// mod returns x modulo m in [0, m), also for negative x.
+func mod(x, m int) int {
+	return (x%m + m) % m
+}
+
+// lru is a map holding values up to a total size, evicting the least recently used ones to make room.
+// It is safe for concurrent use.
+type lru[K comparable, V any] struct {
+	mu      sync.Mutex
+	limit   int64
+	size    int64
+	order   *list.List // Entries from the most to the least recently used
+	entries map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+	size  int64
+}
+
+func newLRU[K comparable, V any](limit int64) *lru[K, V] {
+	return &lru[K, V]{limit: limit, order: list.New(), entries: make(map[K]*list.Element)}
+}
+
+// get returns the value of key and marks it as recently used.
+func (c *lru[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	c.order.MoveToFront(e)
+
+	return e.Value.(*lruEntry[K, V]).value, true
+}
+
+// contains reports whether key is cached without marking it as used.
+func (c *lru[K, V]) contains(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.entries[key]
+
+	return ok
+}
+
+// add sets the value of key, evicting the least recently used entries until the cache fits its limit.
+// Values larger than the whole cache are not added.
+func (c *lru[K, V]) add(key K, value V, size int64) {
+	if size > c.limit {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, size: size})
+	c.size += size
+
+	for c.size > c.limit {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *lru[K, V]) remove(e *list.Element) {
+	entry := c.order.Remove(e).(*lruEntry[K, V])
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/metrics"
+	"fibonacci/internal/service"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckpointCache(t *testing.T) {
+	newService := func(cfg service.CacheConfig) service.Service {
+		return service.NewService(50, 1, 5000, 5000, 1000, service.WithCache(cfg))
+	}
+
+	plain := service.NewService(50, 1, 5000, 5000, 1000)
+
+	// collect streams the range and returns its values.
+	collect := func(t *testing.T, s service.Service, req domain.FibonacciStreamRequest) []string {
+		values := []string{}
+		req.SendFunc = func(chunk domain.FibonacciChunk) error {
+			values = append(values, chunk.Values...)
+			return nil
+		}
+
+		assert.NoError(t, s.GetFibonacciStream(context.Background(), req))
+
+		return values
+	}
+
+	hits := func(cache string) float64 {
+		return testutil.ToFloat64(metrics.CacheHitsTotal.WithLabelValues(cache))
+	}
+
+	misses := func(cache string) float64 {
+		return testutil.ToFloat64(metrics.CacheMissesTotal.WithLabelValues(cache))
+	}
+
+	t.Run("cached terms match computed ones", func(t *testing.T) {
+		s := newService(service.CacheConfig{Interval: 10, CheckpointBytes: 1 << 20, PrefixBytes: 1 << 20})
+		tribonacci := domain.SequenceSpec{Name: service.SequenceTribonacci}
+
+		for _, req := range []domain.FibonacciRequest{
+			{End: 100},
+			{Start: 35, End: 57},
+			{Start: 95, End: 130},
+			{Start: 125, End: 140},
+			{Start: -45, End: -20},
+			{Start: -38, End: -31},
+			{End: 60, Modulus: 7},
+			{Start: 23, End: 48, Modulus: 7},
+			{Sequence: tribonacci, Start: 5, End: 80},
+			{Sequence: tribonacci, Start: 47, End: 52},
+		} {
+			want, err := plain.GetFibonacci(context.Background(), req)
+			assert.NoError(t, err)
+
+			got, err := s.GetFibonacci(context.Background(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "%+v", req)
+
+			stream := domain.FibonacciStreamRequest{Sequence: req.Sequence, Start: req.Start, End: req.End, Modulus: req.Modulus, ChunkSize: 7}
+			assert.Equal(t, want, collect(t, s, stream), "%+v", req)
+		}
+	})
+
+	t.Run("requests start from the nearest checkpoint", func(t *testing.T) {
+		s := newService(service.CacheConfig{Interval: 100, CheckpointBytes: 1 << 20})
+		before := hits("checkpoint")
+
+		// Streaming the range records the checkpoints at 2100, 2200 and 2300.
+		collect(t, s, domain.FibonacciStreamRequest{Start: 2050, End: 2350, ChunkSize: 50})
+		assert.Equal(t, before, hits("checkpoint"))
+
+		want, err := plain.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 2242, End: 2260})
+		assert.NoError(t, err)
+
+		got, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 2242, End: 2260})
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, before+1, hits("checkpoint"))
+
+		missed := misses("checkpoint")
+
+		_, err = s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 2442, End: 2460})
+		assert.NoError(t, err)
+		assert.Equal(t, missed+1, misses("checkpoint"))
+	})
+
+	t.Run("evicts least recently used checkpoints", func(t *testing.T) {
+		// The checkpoints at 400, 500 and 600 take 84, 100 and 116 bytes saved, so they are the only ones that fit.
+		s := newService(service.CacheConfig{Interval: 100, CheckpointBytes: 320})
+
+		_, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 50, End: 650})
+		assert.NoError(t, err)
+
+		before, missed := hits("checkpoint"), misses("checkpoint")
+
+		for _, start := range []int{610, 510, 410, 110} {
+			want, err := plain.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: start, End: start + 5})
+			assert.NoError(t, err)
+
+			got, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: start, End: start + 5})
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "start %d", start)
+		}
+
+		assert.Equal(t, before+3, hits("checkpoint"))
+		assert.Equal(t, missed+1, misses("checkpoint"))
+	})
+
+	t.Run("answers ranges within a returned prefix", func(t *testing.T) {
+		s := newService(service.CacheConfig{PrefixBytes: 1 << 20})
+		before, missed := hits("prefix"), misses("prefix")
+
+		prefix, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{End: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, missed+1, misses("prefix"))
+
+		// The cache keeps its own copy of the prefix.
+		prefix[10] = "changed"
+
+		got, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 5, End: 15})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"5", "8", "13", "21", "34", "55", "89", "144", "233", "377"}, got)
+		assert.Equal(t, before+1, hits("prefix"))
+
+		got[0] = "changed"
+
+		got, err = s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 5, End: 6})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"5"}, got)
+
+		// Other sequences and longer ranges are computed.
+		got, err = s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 5, End: 8, Modulus: 7})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"5", "1", "6"}, got)
+
+		got, err = s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 18, End: 22})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2584", "4181", "6765", "10946"}, got)
+		assert.Equal(t, before+2, hits("prefix"))
+		assert.Equal(t, missed+3, misses("prefix"))
+	})
+}
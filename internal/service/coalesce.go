@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/logging"
+	"fibonacci/internal/metrics"
+)
+
+// WithCoalescing makes concurrent identical calls share their computation. GetFibonacci calls wait for
+// the one already computing the same range, and GetFibonacciStream calls for the same range and chunk size
+// subscribe to a single generator. The generator keeps the last backlog chunks, so subscribers proceed
+// at their own pace as long as they stay within backlog chunks of each other, and streams can be joined
+// until their first chunk is dropped.
+//
+// A caller that cancels or fails to send only leaves; the computation stops once every caller has left.
+func WithCoalescing(backlog int) Option {
+	return func(s *fibonacciService) {
+		s.calls = &flightGroup{calls: make(map[string]*flight)}
+		s.streams = &broadcastGroup{backlog: max(backlog, 1), streams: make(map[string]*broadcast)}
+	}
+}
+
+// rangeKey identifies the terms of a range, see sequenceKey.
+func rangeKey(r sequenceRange) string {
+	return fmt.Sprintf("%s %d %d", sequenceKey(r.spec, r.modulus), r.start, r.end)
+}
+
+// flightGroup deduplicates concurrent calls computing the same terms.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight // Calls in progress by key
+}
+
+// flight is a computation shared by the callers waiting for it.
+type flight struct {
+	done    chan struct{} // Closed once res and err are set
+	cancel  context.CancelFunc
+	waiters int  // Callers waiting for the result, guarded by flightGroup.mu
+	shared  bool // Whether the result went to more than one caller, set before done is closed
+	res     []string
+	err     error
+}
+
+// do returns the result of fn for key, sharing it with the concurrent calls for the same key.
+// fn runs with a context that is only canceled once every caller has given up waiting for it,
+// so one caller canceling does not fail the others.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]string, error)) ([]string, error) {
+	g.mu.Lock()
+
+	f, ok := g.calls[key]
+	if ok {
+		metrics.FibonacciCoalescedCallsTotal.WithLabelValues("Fibonacci").Inc()
+		logging.FromContext(ctx).WithField("key", key).Debug("Joined a coalesced call")
+	} else {
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = f
+
+		go g.run(fctx, key, f, fn)
+	}
+
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.shared && f.err == nil {
+			// Every caller gets its own slice, as callers are free to modify their result.
+			return slices.Clone(f.res), nil
+		}
+
+		return f.res, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		if f.waiters--; f.waiters == 0 {
+			f.cancel()
+			g.forget(key, f)
+		}
+
+		return nil, contextError(ctx)
+	}
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) ([]string, error)) {
+	res, err := fn(ctx)
+	f.cancel()
+
+	g.mu.Lock()
+	g.forget(key, f)
+	f.res, f.err, f.shared = res, err, f.waiters > 1
+	g.mu.Unlock()
+
+	close(f.done)
+}
+
+// forget removes f from the calls in progress, so that later calls start a computation of their own.
+// It must be called with g.mu held.
+func (g *flightGroup) forget(key string, f *flight) {
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
+
+// broadcastGroup feeds the concurrent streams of the same chunks from a single generator each.
+type broadcastGroup struct {
+	backlog int // Chunks kept for subscribers lagging behind the generator
+
+	mu      sync.Mutex
+	streams map[string]*broadcast // Streams that can still be joined, by key
+}
+
+// broadcast is a generator of chunks and the subscribers streaming them.
+type broadcast struct {
+	group  *broadcastGroup
+	key    string
+	cancel context.CancelFunc // Stops the generator
+
+	mu      sync.Mutex
+	chunks  []domain.FibonacciChunk // Generated chunks still needed by a subscriber, or kept for those joining
+	first   int                     // Number of the first chunk of chunks, counting from the start of the stream
+	subs    map[*subscription]struct{}
+	closed  bool          // Whether every subscriber has left, so the generator has been stopped
+	done    bool          // Whether the generator has finished, with err
+	err     error         // Error the generator failed with
+	changed chan struct{} // Closed and replaced whenever chunks are added, consumed or the broadcast finishes
+}
+
+// subscription is the position of a subscriber in the stream.
+type subscription struct {
+	next int // Number of the next chunk to send
+}
+
+// stream sends the chunks produced by a cursor from newCursor to send, sharing the cursor with the concurrent
+// streams for the same key. The chunk values are shared between subscribers, so send must not modify them.
+func (g *broadcastGroup) stream(ctx context.Context, key string, chunkSize int, send func(domain.FibonacciChunk) error,
+	newCursor func(ctx context.Context) (*chunkCursor, error)) error {
+	b, sub := g.subscribe(ctx, key, chunkSize, newCursor)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if sub.next < b.first+len(b.chunks) {
+			chunk := b.chunks[sub.next-b.first]
+
+			b.mu.Unlock()
+			err := contextError(ctx)
+			if err == nil {
+				err = sendSharedChunk(ctx, sub.next, chunk, send)
+			}
+			b.mu.Lock()
+
+			if err != nil {
+				b.leave(sub)
+				return err
+			}
+
+			sub.next++
+			b.trim()
+			b.notify()
+
+			continue
+		}
+
+		if b.done {
+			delete(b.subs, sub)
+			return b.err
+		}
+
+		if err := b.wait(ctx); err != nil {
+			b.leave(sub)
+			return err
+		}
+	}
+}
+
+// subscribe joins the stream for key or, when there is none that can still be joined, starts one.
+func (g *broadcastGroup) subscribe(ctx context.Context, key string, chunkSize int,
+	newCursor func(ctx context.Context) (*chunkCursor, error)) (*broadcast, *subscription) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	sub := &subscription{}
+
+	if b, ok := g.streams[key]; ok {
+		b.mu.Lock()
+		joinable := b.first == 0 && !b.closed
+		if joinable {
+			b.subs[sub] = struct{}{}
+		}
+		b.mu.Unlock()
+
+		if joinable {
+			metrics.FibonacciCoalescedCallsTotal.WithLabelValues("FibonacciStream").Inc()
+			logging.FromContext(ctx).WithField("key", key).Debug("Joined a coalesced stream")
+			return b, sub
+		}
+	}
+
+	bctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	b := &broadcast{
+		group:   g,
+		key:     key,
+		cancel:  cancel,
+		subs:    map[*subscription]struct{}{sub: {}},
+		changed: make(chan struct{}),
+	}
+	g.streams[key] = b
+
+	go b.generate(bctx, chunkSize, newCursor)
+
+	return b, sub
+}
+
+// forget removes b from the streams that can be joined. It must be called with g.mu held.
+func (g *broadcastGroup) forget(b *broadcast) {
+	if g.streams[b.key] == b {
+		delete(g.streams, b.key)
+	}
+}
+
+// generate produces the chunks of the stream, staying at most backlog chunks ahead of the slowest subscriber.
+func (b *broadcast) generate(ctx context.Context, chunkSize int, newCursor func(ctx context.Context) (*chunkCursor, error)) {
+	err := b.produce(ctx, chunkSize, newCursor)
+	b.cancel()
+
+	b.group.mu.Lock()
+	b.group.forget(b)
+	b.group.mu.Unlock()
+
+	b.mu.Lock()
+	b.done, b.err = true, err
+	b.notify()
+	b.mu.Unlock()
+}
+
+func (b *broadcast) produce(ctx context.Context, chunkSize int, newCursor func(ctx context.Context) (*chunkCursor, error)) error {
+	c, err := newCursor(ctx)
+	if err != nil {
+		return err
+	}
+
+	for !c.done() {
+		b.mu.Lock()
+		for b.first+len(b.chunks)-b.slowest() >= b.group.backlog {
+			if err := b.wait(ctx); err != nil {
+				b.mu.Unlock()
+				return err
+			}
+		}
+		b.mu.Unlock()
+
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		chunk := computeChunk(ctx, c, chunkSize)
+		// The cursor reuses its values, while subscribers may still be sending earlier chunks.
+		chunk.Values = slices.Clone(chunk.Values)
+
+		b.mu.Lock()
+		b.chunks = append(b.chunks, chunk)
+		b.trim()
+		b.notify()
+		b.mu.Unlock()
+	}
+
+	return nil
+}
+
+// slowest returns the number of the next chunk of the subscriber furthest behind.
+// It must be called with b.mu held.
+func (b *broadcast) slowest() int {
+	slowest := b.first + len(b.chunks)
+	for sub := range b.subs {
+		slowest = min(slowest, sub.next)
+	}
+
+	return slowest
+}
+
+// trim drops the chunks that every subscriber has sent, except for the last backlog ones
+// which are kept for subscribers joining while the first chunk is among them.
+// It must be called with b.mu held.
+func (b *broadcast) trim() {
+	drop := min(b.slowest(), b.first+len(b.chunks)-b.group.backlog) - b.first
+	if drop <= 0 {
+		return
+	}
+
+	clear(b.chunks[:drop])
+	b.chunks = b.chunks[drop:]
+	b.first += drop
+}
+
+// leave removes a subscriber that stops before the end of the stream, stopping the generator if it was the last one.
+// It must be called with b.mu held.
+func (b *broadcast) leave(sub *subscription) {
+	delete(b.subs, sub)
+
+	if len(b.subs) > 0 {
+		// The generator may be waiting for the subscriber to catch up.
+		b.trim()
+		b.notify()
+
+		return
+	}
+
+	b.closed = true
+	b.cancel()
+}
+
+// wait blocks until the broadcast changes or ctx is done. It must be called with b.mu held, which it releases while waiting.
+func (b *broadcast) wait(ctx context.Context) error {
+	changed := b.changed
+
+	b.mu.Unlock()
+	defer b.mu.Lock()
+
+	select {
+	case <-changed:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx)
+	}
+}
+
+// notify wakes up everyone waiting for the broadcast to change. It must be called with b.mu held.
+func (b *broadcast) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
@@ -0,0 +1,218 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/metrics"
+	"fibonacci/internal/service"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoalescing(t *testing.T) {
+	plain := service.NewService(10, 1, 1000, 1000, 100000)
+
+	coalesced := func(method string) float64 {
+		return testutil.ToFloat64(metrics.FibonacciCoalescedCallsTotal.WithLabelValues(method))
+	}
+
+	t.Run("concurrent calls share the result", func(t *testing.T) {
+		s := service.NewService(10, 1, 1000, 1000, 100000, service.WithCoalescing(4))
+		req := domain.FibonacciRequest{Start: 300000, End: 300050}
+
+		want, err := plain.GetFibonacci(context.Background(), req)
+		assert.NoError(t, err)
+
+		before := coalesced("Fibonacci")
+
+		// Calls only share a result when they overlap, so try again on the off chance that none did.
+		for attempt := 0; attempt < 10 && coalesced("Fibonacci") == before; attempt++ {
+			var wg sync.WaitGroup
+			results := make([][]string, 8)
+
+			for i := range results {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					res, err := s.GetFibonacci(context.Background(), req)
+					assert.NoError(t, err)
+					results[i] = res
+				}()
+			}
+
+			wg.Wait()
+
+			for _, res := range results {
+				assert.Equal(t, want, res)
+			}
+
+			// Every caller may modify its own result.
+			results[0][0] = "changed"
+			assert.Equal(t, want[0], results[1][0])
+		}
+
+		assert.Greater(t, coalesced("Fibonacci"), before)
+	})
+
+	t.Run("canceled call does not fail the others", func(t *testing.T) {
+		s := service.NewService(10, 1, 1000, 1000, 100000, service.WithCoalescing(4))
+		req := domain.FibonacciRequest{Start: 300000, End: 300050}
+
+		want, err := plain.GetFibonacci(context.Background(), req)
+		assert.NoError(t, err)
+
+		for attempt := 0; attempt < 10; attempt++ {
+			before := coalesced("Fibonacci")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			canceled := make(chan error, 1)
+
+			go func() {
+				_, err := s.GetFibonacci(ctx, req)
+				canceled <- err
+			}()
+
+			done := make(chan []string, 1)
+
+			go func() {
+				res, err := s.GetFibonacci(context.Background(), req)
+				assert.NoError(t, err)
+				done <- res
+			}()
+
+			// Cancel the first call only once the second has joined it.
+			for coalesced("Fibonacci") == before && len(done) == 0 {
+				runtime.Gosched()
+			}
+
+			cancel()
+
+			assert.Equal(t, want, <-done)
+
+			if err := <-canceled; err != nil {
+				assert.ErrorIs(t, err, domain.ErrContextCanceled)
+				return
+			}
+		}
+	})
+
+	// subscriber streams the range, blocking in SendFunc on the chunk at block until it is released.
+	type subscriber struct {
+		values  []string
+		err     error
+		blocked chan struct{} // Closed once the subscriber is blocked
+		release chan struct{}
+		done    chan struct{}
+	}
+
+	subscribe := func(s service.Service, ctx context.Context, req domain.FibonacciStreamRequest, block int, fail error) *subscriber {
+		sub := &subscriber{blocked: make(chan struct{}), release: make(chan struct{}), done: make(chan struct{})}
+
+		req.SendFunc = func(chunk domain.FibonacciChunk) error {
+			if chunk.Index == req.Start+block*req.ChunkSize {
+				close(sub.blocked)
+				<-sub.release
+
+				if fail != nil {
+					return fail
+				}
+			}
+
+			sub.values = append(sub.values, chunk.Values...)
+
+			return nil
+		}
+
+		go func() {
+			defer close(sub.done)
+			sub.err = s.GetFibonacciStream(ctx, req)
+		}()
+
+		return sub
+	}
+
+	req := domain.FibonacciStreamRequest{Start: 1000, End: 1100, ChunkSize: 5}
+
+	want, err := plain.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: req.Start, End: req.End})
+	assert.NoError(t, err)
+
+	t.Run("streams share a generator", func(t *testing.T) {
+		s := service.NewService(10, 1, 1000, 1000, 100000, service.WithCoalescing(4))
+		before := coalesced("FibonacciStream")
+
+		a := subscribe(s, context.Background(), req, 0, nil)
+		<-a.blocked
+
+		b := subscribe(s, context.Background(), req, 0, nil)
+		<-b.blocked
+
+		close(a.release)
+		close(b.release)
+		<-a.done
+		<-b.done
+
+		assert.NoError(t, a.err)
+		assert.NoError(t, b.err)
+		assert.Equal(t, want, a.values)
+		assert.Equal(t, want, b.values)
+		assert.Equal(t, before+1, coalesced("FibonacciStream"))
+	})
+
+	t.Run("subscriber leaving does not stop the others", func(t *testing.T) {
+		s := service.NewService(10, 1, 1000, 1000, 100000, service.WithCoalescing(4))
+		errSend := errors.New("send failed")
+
+		a := subscribe(s, context.Background(), req, 0, nil)
+		<-a.blocked
+
+		ctx, cancel := context.WithCancel(context.Background())
+		b := subscribe(s, ctx, req, 0, nil)
+		<-b.blocked
+
+		c := subscribe(s, context.Background(), req, 0, errSend)
+		<-c.blocked
+
+		// The canceled subscriber fails before sending its next chunk, the failing one on sending it.
+		cancel()
+		close(b.release)
+		close(c.release)
+		<-b.done
+		<-c.done
+
+		close(a.release)
+		<-a.done
+
+		assert.NoError(t, a.err)
+		assert.Equal(t, want, a.values)
+		assert.ErrorIs(t, b.err, domain.ErrContextCanceled)
+		assert.ErrorIs(t, c.err, errSend)
+	})
+
+	t.Run("late subscriber starts its own generator", func(t *testing.T) {
+		s := service.NewService(10, 1, 1000, 1000, 100000, service.WithCoalescing(2))
+		before := coalesced("FibonacciStream")
+
+		// Once the first subscriber has sent three chunks, the first chunk has been dropped.
+		a := subscribe(s, context.Background(), req, 3, nil)
+		<-a.blocked
+
+		b := subscribe(s, context.Background(), req, -1, nil)
+		<-b.done
+
+		close(a.release)
+		<-a.done
+
+		assert.NoError(t, a.err)
+		assert.NoError(t, b.err)
+		assert.Equal(t, want, a.values)
+		assert.Equal(t, want, b.values)
+		assert.Equal(t, before, coalesced("FibonacciStream"))
+	})
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"fibonacci/internal/domain"
+)
+
+// WithByteBudgets limits the estimated size of the terms returned by a single GetFibonacci or GetFibonacciBatch call
+// to request bytes, of a whole stream to stream bytes and of a single chunk to chunk bytes.
+// A budget of zero is not enforced, which is also the default for all three.
+//
+// Sizes are estimated from the number of decimal digits of the terms, see rangeDigits, since that is
+// what the output grows with: the index limits allow ranges whose size grows quadratically with n.
+func WithByteBudgets(request, stream, chunk int64) Option {
+	return func(s *fibonacciService) {
+		s.requestBytesLimit = request
+		s.streamBytesLimit = stream
+		s.chunkBytesLimit = chunk
+	}
+}
+
+// checkBytes checks the estimated size of the terms a(start)..a(end-1) of the range against limit,
+// attributing a violation to field. A limit of zero is not enforced.
+func checkBytes(field string, r sequenceRange, start, end int, limit int64) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	if size := rangeDigits(r.seq, start, end, r.modulus); size > limit {
+		return domain.NewFieldError(field, fmt.Errorf("%w: estimated %d bytes, must not exceed %d", domain.ErrTooLargeResponse, size, limit))
+	}
+
+	return nil
+}
+
+// checkChunkBytes checks the estimated size of the largest chunk of chunkSize terms of the range against the chunk budget.
+// Terms grow away from zero in both directions, so the largest chunk is at one of the ends.
+func (s *fibonacciService) checkChunkBytes(r sequenceRange, chunkSize int) error {
+	if err := checkBytes("chunk_size", r, r.start, min(r.start+chunkSize, r.end), s.chunkBytesLimit); err != nil {
+		return err
+	}
+
+	return checkBytes("chunk_size", r, max(r.end-chunkSize, r.start), r.end, s.chunkBytesLimit)
+}
+
+// rangeDigits estimates the total number of decimal digits of a(start)..a(end-1) of seq, reduced modulo modulus
+// unless it is zero, without counting signs.
+//
+// Reduced terms have at most as many digits as modulus-1. Otherwise the digits of a(n) grow linearly
+// with |n|, see digitGrowth, so they add up to an arithmetic series on either side of zero.
+func rangeDigits(seq Sequence, start, end int, modulus uint64) int64 {
+	if end <= start {
+		return 0
+	}
+
+	if modulus != 0 {
+		return int64(end-start) * int64(len(strconv.FormatUint(modulus-1, 10)))
+	}
+
+	var total float64
+
+	if start < 0 {
+		// The terms at -1..-k mirror those at 1..k of the reversed recurrence.
+		base, rate := digitGrowth(seq, true)
+		lo, hi := max(-end+1, 1), -start
+
+		total += float64(hi-lo+1)*base + rate*sumRange(lo, hi)
+	}
+
+	if end > 0 {
+		base, rate := digitGrowth(seq, false)
+		lo, hi := max(start, 0), end-1
+
+		total += float64(hi-lo+1)*base + rate*sumRange(lo, hi)
+	}
+
+	// Every term has at least one digit, which the estimate may fall short of for the first few terms.
+	return max(int64(math.Ceil(total)), int64(end-start))
+}
+
+// termDigits estimates the number of decimal digits of a(n) of seq, reduced modulo modulus unless it is zero,
+// like rangeDigits for a single term.
+func termDigits(seq Sequence, n int, modulus uint64) int64 {
+	if modulus != 0 {
+		return int64(len(strconv.FormatUint(modulus-1, 10)))
+	}
+
+	return int64(sequenceDigits(seq, n))
+}
+
+// digitGrowth returns the estimated number of decimal digits of a(n) of seq as base + rate*|n|,
+// for negative n if backwards is set. See fibDigits and sequenceDigits for single terms.
+func digitGrowth(seq Sequence, backwards bool) (base, rate float64) {
+	if isFibonacci(seq) {
+		return 1 - log10Sqrt5, log10Phi
+	}
+
+	seedDigits := 1
+	for _, seed := range seq.Seeds() {
+		seedDigits = max(seedDigits, len(new(big.Int).Abs(seed).Text(10)))
+	}
+
+	coefficients := seq.Coefficients()
+	if backwards {
+		coefficients = reverseCoefficients(coefficients)
+	}
+
+	return float64(seedDigits), growthRate(coefficients)
+}
+
+// sumRange returns lo + (lo+1) + ... + hi in floating point, which cannot overflow.
+func sumRange(lo, hi int) float64 {
+	if hi < lo {
+		return 0
+	}
+
+	return (float64(lo) + float64(hi)) * float64(hi-lo+1) / 2
+}
@@ -0,0 +1,145 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteBudgets(t *testing.T) {
+	plain := service.NewService(100, 1, 10000, 10000, 100000)
+
+	// digits returns the actual number of decimal digits of the terms of req.
+	digits := func(t *testing.T, req domain.FibonacciRequest) int64 {
+		values, err := plain.GetFibonacci(context.Background(), req)
+		assert.NoError(t, err)
+
+		var total int64
+		for _, v := range values {
+			total += int64(len(strings.TrimPrefix(v, "-")))
+		}
+
+		return total
+	}
+
+	assertTooLarge := func(t *testing.T, err error, field string) {
+		assert.ErrorIs(t, err, domain.ErrTooLargeResponse)
+		assert.ErrorContains(t, err, "estimated")
+
+		var fieldErr *domain.FieldError
+		if assert.ErrorAs(t, err, &fieldErr) {
+			assert.Equal(t, field, fieldErr.Field)
+		}
+	}
+
+	t.Run("estimates are close to the actual size", func(t *testing.T) {
+		for _, req := range []domain.FibonacciRequest{
+			{Start: 1000, End: 3000},
+			{Start: -3000, End: -1000},
+			{Start: -2000, End: 2000},
+			{Start: 5000, End: 5100},
+		} {
+			actual := digits(t, req)
+
+			s := service.NewService(100, 1, 10000, 10000, 100000, service.WithByteBudgets(actual*101/100, 0, 0))
+			_, err := s.GetFibonacci(context.Background(), req)
+			assert.NoError(t, err, "%+v", req)
+
+			s = service.NewService(100, 1, 10000, 10000, 100000, service.WithByteBudgets(actual*99/100, 0, 0))
+			_, err = s.GetFibonacci(context.Background(), req)
+			assertTooLarge(t, err, "n")
+		}
+	})
+
+	t.Run("small ranges are never underestimated", func(t *testing.T) {
+		for _, req := range []domain.FibonacciRequest{{End: 10}, {Start: -10, End: 10}, {Start: 7, End: 8}} {
+			s := service.NewService(100, 1, 10000, 10000, 100000, service.WithByteBudgets(digits(t, req)-1, 0, 0))
+			_, err := s.GetFibonacci(context.Background(), req)
+			assertTooLarge(t, err, "n")
+		}
+	})
+
+	t.Run("reduced terms are bounded by the modulus", func(t *testing.T) {
+		req := domain.FibonacciRequest{Start: 1000, End: 3000, Modulus: 1_000_000_007}
+
+		s := service.NewService(100, 1, 10000, 10000, 100000, service.WithByteBudgets(20000, 0, 0))
+		_, err := s.GetFibonacci(context.Background(), req)
+		assert.NoError(t, err)
+
+		s = service.NewService(100, 1, 10000, 10000, 100000, service.WithByteBudgets(19999, 0, 0))
+		_, err = s.GetFibonacci(context.Background(), req)
+		assertTooLarge(t, err, "n")
+	})
+
+	s := service.NewService(100, 1, 10000, 10000, 100000, service.WithByteBudgets(200000, 1000000, 10000))
+
+	t.Run("requests", func(t *testing.T) {
+		_, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 5000, End: 5100})
+		assert.NoError(t, err)
+
+		_, err = s.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 5000, End: 5500})
+		assertTooLarge(t, err, "n")
+		assert.ErrorContains(t, err, "must not exceed 200000")
+	})
+
+	t.Run("streams", func(t *testing.T) {
+		send := func(domain.FibonacciChunk) error { return nil }
+
+		err := s.GetFibonacciStream(context.Background(), domain.FibonacciStreamRequest{Start: 5000, End: 5500, ChunkSize: 5, SendFunc: send})
+		assert.NoError(t, err)
+
+		err = s.GetFibonacciStream(context.Background(), domain.FibonacciStreamRequest{Start: 5000, End: 7000, ChunkSize: 10, SendFunc: send})
+		assertTooLarge(t, err, "n")
+
+		// Only the last chunks are too large.
+		err = s.GetFibonacciStream(context.Background(), domain.FibonacciStreamRequest{Start: 0, End: 2000, ChunkSize: 30, SendFunc: send})
+		assertTooLarge(t, err, "chunk_size")
+
+		err = s.GetFibonacciStream(context.Background(), domain.FibonacciStreamRequest{Start: 0, End: 2000, ChunkSize: 20, SendFunc: send})
+		assert.NoError(t, err)
+	})
+
+	t.Run("flow chunk size", func(t *testing.T) {
+		commands := make(chan domain.FlowCommand, 1)
+		commands <- domain.FlowCommand{Kind: domain.FlowChunkSize, Value: 30}
+
+		err := s.GetFibonacciFlow(context.Background(), domain.FibonacciFlowRequest{
+			Stream:   domain.FibonacciStreamRequest{Start: 0, End: 2000, ChunkSize: 20, SendFunc: func(domain.FibonacciChunk) error { return nil }},
+			Commands: commands,
+		})
+		assertTooLarge(t, err, "chunk_size")
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		s := service.NewService(100, 1, 10000, 10000, 100000, service.WithBatchLimits(10, 10000, 0), service.WithByteBudgets(200000, 0, 0))
+
+		results, err := s.GetFibonacciBatch(context.Background(), []domain.BatchItem{
+			{Range: &domain.FibonacciRequest{Start: 5000, End: 5100}},
+			{Nth: &domain.FibonacciNthRequest{N: 5000}},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+
+		// The items share the budget of the request: each of these fits, but not all of them.
+		items := make([]domain.BatchItem, 10)
+		for i := range items {
+			items[i] = domain.BatchItem{Range: &domain.FibonacciRequest{Start: 5000, End: 5100}}
+		}
+
+		_, err = s.GetFibonacciBatch(context.Background(), items)
+		assertTooLarge(t, err, "items")
+
+		// Single terms count as well.
+		for i := range items {
+			items[i] = domain.BatchItem{Nth: &domain.FibonacciNthRequest{N: 99999}}
+		}
+
+		_, err = s.GetFibonacciBatch(context.Background(), items)
+		assertTooLarge(t, err, "items")
+	})
+}
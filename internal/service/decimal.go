@@ -0,0 +1,318 @@
+package service
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+	"strconv"
+)
+
+const (
+	decimalBase   = 1_000_000_000_000_000_000 // 10^18, the largest power of ten below 2^64 / 2
+	decimalDigits = 18                        // Decimal digits per limb
+)
+
+// digitPairs holds the two-digit decimal representations of 0..99 back to back.
+const digitPairs = "00010203040506070809" +
+	"10111213141516171819" +
+	"20212223242526272829" +
+	"30313233343536373839" +
+	"40414243444546474849" +
+	"50515253545556575859" +
+	"60616263646566676869" +
+	"70717273747576777879" +
+	"80818283848586878889" +
+	"90919293949596979899"
+
+// decimal is an arbitrary-precision signed integer stored as a sign and
+// little-endian base 10^18 limbs. Keeping a power-of-ten base makes addition as
+// cheap as with binary limbs while turning decimal conversion into a linear
+// copy, which is what dominates when every term is emitted as a string.
+//
+// The zero value is 0 and ready to use. Operations write into the receiver and
+// reuse its limb storage whenever the capacity allows.
+type decimal struct {
+	neg   bool     // Sign, never set for zero
+	limbs []uint64 // Little-endian magnitude, without leading zero limbs
+}
+
+// setUint64 sets z to x and returns z.
+func (z *decimal) setUint64(x uint64) *decimal {
+	z.neg = false
+	z.limbs = z.limbs[:0]
+	for x > 0 {
+		z.limbs = append(z.limbs, x%decimalBase)
+		x /= decimalBase
+	}
+
+	return z
+}
+
+// setBig sets z to x and returns z.
+func (z *decimal) setBig(x *big.Int) *decimal {
+	// big.Int uses subquadratic base conversion, after which splitting the
+	// digits into limbs is linear.
+	z.setText(new(big.Int).Abs(x).Text(10))
+	z.neg = x.Sign() < 0
+
+	return z
+}
+
+// setText sets z to the non-negative integer written in decimal digits by text and returns z.
+// The text must consist of digits only.
+func (z *decimal) setText(text string) *decimal {
+	z.limbs = grow(z.limbs, (len(text)+decimalDigits-1)/decimalDigits)
+	for i := range z.limbs {
+		hi := len(text) - i*decimalDigits
+		lo := max(hi-decimalDigits, 0)
+
+		limb, _ := strconv.ParseUint(text[lo:hi], 10, 64)
+		z.limbs[i] = limb
+	}
+
+	z.limbs = trim(z.limbs)
+	z.neg = false
+
+	return z
+}
+
+// setZero sets z to 0 and returns z.
+func (z *decimal) setZero() *decimal {
+	z.neg = false
+	z.limbs = z.limbs[:0]
+
+	return z
+}
+
+// add sets z to x + y and returns z. z may alias x or y.
+func (z *decimal) add(x, y *decimal) *decimal {
+	return z.addSigned(x.limbs, x.neg, y.limbs, y.neg)
+}
+
+// sub sets z to x - y and returns z. z may alias x or y.
+func (z *decimal) sub(x, y *decimal) *decimal {
+	return z.addSigned(x.limbs, x.neg, y.limbs, !y.neg)
+}
+
+// mulInt64 sets z to x * c and returns z. z may alias x.
+func (z *decimal) mulInt64(x *decimal, c int64) *decimal {
+	neg := x.neg != (c < 0)
+
+	abs := uint64(c)
+	if c < 0 {
+		abs = -abs
+	}
+
+	z.limbs = mulAbs(z.limbs, x.limbs, abs)
+	z.neg = neg && len(z.limbs) > 0
+
+	return z
+}
+
+func (z *decimal) addSigned(x []uint64, xneg bool, y []uint64, yneg bool) *decimal {
+	switch {
+	case xneg == yneg:
+		z.limbs = addAbs(z.limbs, x, y)
+		z.neg = xneg
+	case cmpAbs(x, y) >= 0:
+		z.limbs = subAbs(z.limbs, x, y)
+		z.neg = xneg
+	default:
+		z.limbs = subAbs(z.limbs, y, x)
+		z.neg = yneg
+	}
+
+	if len(z.limbs) == 0 {
+		z.neg = false
+	}
+
+	return z
+}
+
+// append appends the decimal representation of x to buf and returns the extended buffer.
+func (x *decimal) append(buf []byte) []byte {
+	if len(x.limbs) == 0 {
+		return append(buf, '0')
+	}
+
+	if x.neg {
+		buf = append(buf, '-')
+	}
+
+	top := len(x.limbs) - 1
+	buf = strconv.AppendUint(buf, x.limbs[top], 10)
+
+	for i := top - 1; i >= 0; i-- {
+		var digits [decimalDigits]byte
+
+		limb := x.limbs[i]
+		for j := decimalDigits - 2; j >= 0; j -= 2 {
+			pair := limb % 100
+			limb /= 100
+			digits[j], digits[j+1] = digitPairs[2*pair], digitPairs[2*pair+1]
+		}
+
+		buf = append(buf, digits[:]...)
+	}
+
+	return buf
+}
+
+// appendBinary appends a compact binary encoding of x to buf, the sign followed by the limbs,
+// and returns the extended buffer.
+func (x *decimal) appendBinary(buf []byte) []byte {
+	sign := byte(0)
+	if x.neg {
+		sign = 1
+	}
+
+	buf = append(buf, sign)
+	buf = binary.AppendUvarint(buf, uint64(len(x.limbs)))
+
+	for _, limb := range x.limbs {
+		buf = binary.LittleEndian.AppendUint64(buf, limb)
+	}
+
+	return buf
+}
+
+// readBinary sets z to the value encoded by appendBinary at the start of r.
+func (z *decimal) readBinary(r *tokenReader) {
+	neg := r.uvarint() == 1
+	n := r.count()
+
+	z.limbs = grow(z.limbs, n)
+	for i := range z.limbs {
+		if z.limbs[i] = r.uint64(); z.limbs[i] >= decimalBase {
+			r.fail(errors.New("limb out of range"))
+		}
+	}
+
+	z.limbs = trim(z.limbs)
+	z.neg = neg && len(z.limbs) > 0
+}
+
+// addAbs returns the magnitude x + y, written into z's storage when it is large enough.
+func addAbs(z, x, y []uint64) []uint64 {
+	if len(x) < len(y) {
+		x, y = y, x
+	}
+
+	z = grow(z, len(x)+1)
+
+	var carry uint64
+	for i := 0; i < len(x); i++ {
+		sum := x[i] + carry
+		if i < len(y) {
+			sum += y[i]
+		}
+
+		if sum >= decimalBase {
+			sum -= decimalBase
+			carry = 1
+		} else {
+			carry = 0
+		}
+
+		z[i] = sum
+	}
+
+	z[len(x)] = carry
+
+	return trim(z)
+}
+
+// subAbs returns the magnitude x - y for x >= y, written into z's storage when it is large enough.
+func subAbs(z, x, y []uint64) []uint64 {
+	z = grow(z, len(x))
+
+	var borrow uint64
+	for i := 0; i < len(x); i++ {
+		sub := borrow
+		if i < len(y) {
+			sub += y[i]
+		}
+
+		if x[i] >= sub {
+			z[i] = x[i] - sub
+			borrow = 0
+		} else {
+			z[i] = x[i] + decimalBase - sub
+			borrow = 1
+		}
+	}
+
+	return trim(z)
+}
+
+// mulAbs returns the magnitude x * c, written into z's storage when it is large enough.
+func mulAbs(z, x []uint64, c uint64) []uint64 {
+	if c == 0 || len(x) == 0 {
+		return z[:0]
+	}
+
+	n := len(x)
+	z = grow(z, n+2) // c < 2^64 < 10^20, so the product gains at most two limbs
+
+	var carry uint64
+	for i := 0; i < n; i++ {
+		hi, lo := bits.Mul64(x[i], c)
+
+		var cc uint64
+		lo, cc = bits.Add64(lo, carry, 0)
+		hi += cc
+
+		// hi < 10^18 because x[i] < 10^18, which keeps the quotient within 64 bits.
+		carry, z[i] = bits.Div64(hi, lo, decimalBase)
+	}
+
+	z[n], z[n+1] = carry%decimalBase, carry/decimalBase
+
+	return trim(z)
+}
+
+// cmpAbs compares the magnitudes x and y.
+func cmpAbs(x, y []uint64) int {
+	if len(x) != len(y) {
+		if len(x) < len(y) {
+			return -1
+		}
+
+		return 1
+	}
+
+	for i := len(x) - 1; i >= 0; i-- {
+		if x[i] != y[i] {
+			if x[i] < y[i] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// grow returns a slice of length n, reusing the storage of s when it is large enough.
+// When s has to be reallocated its contents are not preserved, so callers must read
+// their operands through their own slice headers.
+func grow(s []uint64, n int) []uint64 {
+	if cap(s) >= n {
+		return s[:n]
+	}
+
+	// Leave headroom so a growing term does not reallocate on every carry.
+	return make([]uint64, n, n+n/4+1)
+}
+
+// trim removes leading zero limbs.
+func trim(s []uint64) []uint64 {
+	i := len(s)
+	for i > 0 && s[i-1] == 0 {
+		i--
+	}
+
+	return s[:i]
+}
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/logging"
+)
+
+// jobProgressInterval is the number of terms computed between progress updates and cancellation checks.
+const jobProgressInterval = 1000
+
+// jobSweepInterval is the longest time between two deletions of the expired jobs.
+const jobSweepInterval = time.Minute
+
+// JobConfig configures the background jobs of the service.
+type JobConfig struct {
+	Dir       string // Directory the status and result of every job are persisted to
+	Workers   int    // Number of jobs computed concurrently
+	QueueSize int    // Number of jobs that may wait for a worker; further submissions are rejected
+	NLimit    int    // Maximum number of terms of a job
+
+	// BytesLimit bounds the estimated size of the result file of a job, one byte per decimal digit; 0 disables it.
+	BytesLimit int64
+	// Retention is how long the status and result of a finished job are kept; 0 keeps them forever.
+	Retention time.Duration
+}
+
+// WithJobs enables background jobs. They run on a pool of cfg.Workers workers until ctx is done,
+// which cancels the jobs that have not finished. Jobs persisted in cfg.Dir by an earlier process
+// can still be queried and fetched, and those it left unfinished are reported as canceled.
+// Finished jobs are deleted once they are older than cfg.Retention.
+func WithJobs(ctx context.Context, cfg JobConfig) Option {
+	return func(s *fibonacciService) {
+		s.jobs = &jobRunner{
+			s:     s,
+			ctx:   ctx,
+			cfg:   cfg,
+			queue: make(chan *job, cfg.QueueSize),
+			jobs:  make(map[string]*job),
+		}
+	}
+}
+
+// jobRunner queues, computes and persists jobs.
+//
+// Every job has a <id>.json file with its status, rewritten on every state change, and once it has
+// succeeded a <id>.txt file with one term per line. The result is written to <id>.txt.tmp first,
+// so a result file is always complete.
+type jobRunner struct {
+	s     *fibonacciService
+	ctx   context.Context // Global context, cancels every job when done
+	cfg   JobConfig
+	queue chan *job
+
+	initOnce sync.Once
+	initErr  error
+
+	mu   sync.Mutex // Guards jobs and the status of every job
+	jobs map[string]*job
+}
+
+// job is a submitted range and its status.
+type job struct {
+	r        sequenceRange
+	ctx      context.Context
+	cancel   context.CancelFunc
+	record   jobRecord    // Status, guarded by jobRunner.mu
+	canceled bool         // Whether the client canceled the job, guarded by jobRunner.mu
+	computed atomic.Int64 // Terms computed so far, updated by the worker without locking
+}
+
+// jobRecord is the persisted status of a job.
+type jobRecord struct {
+	ID          string              `json:"id"`
+	State       domain.JobState     `json:"state"`
+	Sequence    domain.SequenceSpec `json:"sequence"`
+	Start       int                 `json:"start"`
+	End         int                 `json:"end"`
+	Modulus     uint64              `json:"modulus"`
+	Computed    int                 `json:"computed"`
+	Error       string              `json:"error,omitempty"`
+	SubmittedAt time.Time           `json:"submitted_at"`
+	StartedAt   time.Time           `json:"started_at"`
+	FinishedAt  time.Time           `json:"finished_at"`
+}
+
+func (s *fibonacciService) SubmitJob(ctx context.Context, req domain.JobRequest) (string, error) {
+	r, err := s.jobRunner()
+	if err != nil {
+		return "", err
+	}
+
+	id, err := r.submit(req)
+	if err == nil {
+		logging.FromContext(ctx).WithField("job_id", id).Info("Job queued")
+	}
+
+	return id, err
+}
+
+func (s *fibonacciService) GetJobStatus(ctx context.Context, id string) (domain.JobStatus, error) {
+	r, err := s.jobRunner()
+	if err != nil {
+		return domain.JobStatus{}, err
+	}
+
+	return r.status(id)
+}
+
+func (s *fibonacciService) CancelJob(ctx context.Context, id string) (domain.JobStatus, error) {
+	r, err := s.jobRunner()
+	if err != nil {
+		return domain.JobStatus{}, err
+	}
+
+	return r.cancelJob(id)
+}
+
+func (s *fibonacciService) FetchJobResult(ctx context.Context, req domain.FetchJobResultRequest) error {
+	r, err := s.jobRunner()
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkChunkSize(req.ChunkSize); err != nil {
+		return err
+	}
+
+	return r.fetch(ctx, req)
+}
+
+// jobRunner returns the job runner, restoring the persisted jobs and starting the workers on first use.
+func (s *fibonacciService) jobRunner() (*jobRunner, error) {
+	if s.jobs == nil {
+		return nil, domain.ErrJobsDisabled
+	}
+
+	s.jobs.initOnce.Do(func() {
+		s.jobs.initErr = s.jobs.init()
+	})
+
+	return s.jobs, s.jobs.initErr
+}
+
+func (r *jobRunner) init() error {
+	if err := os.MkdirAll(r.cfg.Dir, 0o755); err != nil {
+		return fmt.Errorf("creating job directory: %w", err)
+	}
+
+	if err := r.restore(); err != nil {
+		return fmt.Errorf("restoring jobs: %w", err)
+	}
+
+	for range r.cfg.Workers {
+		go r.work()
+	}
+
+	if r.cfg.Retention > 0 {
+		go r.sweepExpired()
+	}
+
+	return nil
+}
+
+// restore loads the jobs persisted by earlier processes. Jobs that were queued or running
+// when the process stopped are marked canceled, as their computation is lost.
+func (r *jobRunner) restore() error {
+	paths, err := filepath.Glob(filepath.Join(r.cfg.Dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		j := &job{}
+		if err := json.Unmarshal(data, &j.record); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if r.expired(j.record, time.Now()) {
+			r.remove(j.record.ID)
+			continue
+		}
+
+		if !j.record.State.Finished() {
+			j.record.State = domain.JobCanceled
+			j.record.Error = "interrupted by a server restart"
+			j.record.FinishedAt = time.Now()
+
+			_ = os.Remove(r.path(j.record.ID, ".txt.tmp"))
+
+			if err := r.persist(j.record); err != nil {
+				return err
+			}
+		}
+
+		j.computed.Store(int64(j.record.Computed))
+		r.jobs[j.record.ID] = j
+	}
+
+	return nil
+}
+
+func (r *jobRunner) submit(req domain.JobRequest) (string, error) {
+	sr, err := r.s.newSequenceRange(req.Sequence, req.Start, req.End, req.Modulus, r.cfg.NLimit)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkBytes("n", sr, sr.start, sr.end, r.cfg.BytesLimit); err != nil {
+		return "", err
+	}
+
+	id, err := newJobID()
+	if err != nil {
+		return "", err
+	}
+
+	j := &job{
+		r: sr,
+		record: jobRecord{
+			ID:          id,
+			State:       domain.JobQueued,
+			Sequence:    req.Sequence,
+			Start:       sr.start,
+			End:         sr.end,
+			Modulus:     sr.modulus,
+			SubmittedAt: time.Now(),
+		},
+	}
+	j.ctx, j.cancel = context.WithCancel(r.ctx)
+
+	if err := r.enqueue(j); err != nil {
+		return "", err
+	}
+
+	if req.ChargeFunc != nil {
+		req.ChargeFunc(rangeDigits(sr.seq, sr.start, sr.end, sr.modulus))
+	}
+
+	return id, nil
+}
+
+// enqueue persists a new job and queues it, unless the queue is full.
+func (r *jobRunner) enqueue(j *job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := j.record.ID
+	if err := r.persist(j.record); err != nil {
+		j.cancel()
+		return err
+	}
+
+	select {
+	case r.queue <- j:
+	default:
+		j.cancel()
+		_ = os.Remove(r.path(id, ".json"))
+
+		return fmt.Errorf("%w: %d jobs are waiting", domain.ErrJobQueueFull, r.cfg.QueueSize)
+	}
+
+	r.jobs[id] = j
+
+	// Jobs still queued when the server shuts down are never picked up by a worker.
+	context.AfterFunc(j.ctx, func() { r.cancelQueued(j) })
+
+	return nil
+}
+
+func (r *jobRunner) work() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case j := <-r.queue:
+			r.run(j)
+		}
+	}
+}
+
+// run computes a queued job, unless it has been canceled while it was waiting.
+func (r *jobRunner) run(j *job) {
+	defer j.cancel()
+
+	started := r.update(j, func(rec *jobRecord) bool {
+		if rec.State != domain.JobQueued {
+			return false
+		}
+
+		rec.State = domain.JobRunning
+		rec.StartedAt = time.Now()
+
+		return true
+	})
+	if !started {
+		return
+	}
+
+	err := r.compute(j)
+
+	r.update(j, func(rec *jobRecord) bool {
+		rec.Computed = int(j.computed.Load())
+		rec.FinishedAt = time.Now()
+
+		switch {
+		case err == nil:
+			rec.State = domain.JobSucceeded
+		case errors.Is(err, domain.ErrContextCanceled):
+			rec.State = domain.JobCanceled
+			rec.Error = j.cancelReason()
+		default:
+			rec.State = domain.JobFailed
+			rec.Error = err.Error()
+		}
+
+		return true
+	})
+}
+
+// compute writes the terms of the job to its result file.
+func (r *jobRunner) compute(j *job) error {
+	state, err := r.s.newState(j.ctx, j.r)
+	if err != nil {
+		return err
+	}
+
+	tmp := r.path(j.record.ID, ".txt.tmp")
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	err = j.write(f, state)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, r.path(j.record.ID, ".txt"))
+}
+
+// write writes one term per line to w, updating the progress as it goes.
+func (j *job) write(w io.Writer, state sequenceState) error {
+	bw := bufio.NewWriter(w)
+
+	for i := j.r.start; i < j.r.end; i++ {
+		if done := i - j.r.start; done%jobProgressInterval == 0 {
+			if err := contextError(j.ctx); err != nil {
+				return err
+			}
+
+			j.computed.Store(int64(done))
+		}
+
+		bw.WriteString(state.text())
+		bw.WriteByte('\n')
+		state.advance()
+	}
+
+	j.computed.Store(int64(j.r.end - j.r.start))
+
+	return bw.Flush()
+}
+
+// cancelReason describes why the job was canceled. The caller must hold jobRunner.mu.
+func (j *job) cancelReason() string {
+	if j.canceled {
+		return "canceled by the client"
+	}
+
+	return "canceled by the server shutting down"
+}
+
+// cancelQueued marks a canceled job that no worker has picked up as canceled.
+func (r *jobRunner) cancelQueued(j *job) {
+	r.update(j, func(rec *jobRecord) bool {
+		if rec.State != domain.JobQueued {
+			return false
+		}
+
+		rec.State = domain.JobCanceled
+		rec.Error = j.cancelReason()
+		rec.FinishedAt = time.Now()
+
+		return true
+	})
+}
+
+// update applies change to the status of j and persists it, unless change reports that nothing changed.
+// It reports whether the status changed. A status that cannot be persisted is still served from memory.
+func (r *jobRunner) update(j *job, change func(*jobRecord) bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !change(&j.record) {
+		return false
+	}
+
+	_ = r.persist(j.record)
+
+	return true
+}
+
+func (r *jobRunner) status(id string) (domain.JobStatus, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[id]
+	if !ok {
+		return domain.JobStatus{}, fmt.Errorf("%w: %q", domain.ErrJobNotFound, id)
+	}
+
+	return j.status(), nil
+}
+
+// status returns the status of the job. The caller must hold jobRunner.mu.
+func (j *job) status() domain.JobStatus {
+	rec := j.record
+	st := domain.JobStatus{
+		ID:          rec.ID,
+		State:       rec.State,
+		Start:       rec.Start,
+		End:         rec.End,
+		Computed:    int(j.computed.Load()),
+		Error:       rec.Error,
+		SubmittedAt: rec.SubmittedAt,
+		StartedAt:   rec.StartedAt,
+		FinishedAt:  rec.FinishedAt,
+	}
+
+	// The estimate assumes a constant rate, so it is optimistic for sequences whose terms keep growing.
+	if rec.State == domain.JobRunning && st.Computed > 0 {
+		elapsed := time.Since(rec.StartedAt)
+		st.ETA = time.Duration(float64(elapsed) * float64(rec.End-rec.Start-st.Computed) / float64(st.Computed))
+	}
+
+	return st
+}
+
+// cancelJob cancels a job that has not finished. A queued job is canceled at once, a running one
+// at its next progress check. Canceling a finished job does nothing.
+func (r *jobRunner) cancelJob(id string) (domain.JobStatus, error) {
+	r.mu.Lock()
+	j, ok := r.jobs[id]
+	if ok && !j.record.State.Finished() {
+		j.canceled = true
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return domain.JobStatus{}, fmt.Errorf("%w: %q", domain.ErrJobNotFound, id)
+	}
+
+	if j.cancel != nil {
+		j.cancel()
+		r.cancelQueued(j)
+	}
+
+	return r.status(id)
+}
+
+// fetch streams the result file of a succeeded job in chunks, with the absolute index of their first value.
+func (r *jobRunner) fetch(ctx context.Context, req domain.FetchJobResultRequest) error {
+	st, err := r.status(req.ID)
+	if err != nil {
+		return err
+	}
+
+	if st.State != domain.JobSucceeded {
+		return fmt.Errorf("%w: job is %s", domain.ErrJobNotReady, st.State)
+	}
+
+	f, err := os.Open(r.path(req.ID, ".txt"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+	values := make([]string, 0, req.ChunkSize) // Reused chunk array
+	index := st.Start
+
+	send := func() error {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		if err := req.SendFunc(domain.FibonacciChunk{Index: index, Values: values}); err != nil {
+			return err
+		}
+
+		index += len(values)
+		values = values[:0]
+
+		return nil
+	}
+
+	for {
+		line, err := br.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		values = append(values, strings.TrimSuffix(line, "\n"))
+		if len(values) == req.ChunkSize {
+			if err := send(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(values) > 0 {
+		return send()
+	}
+
+	return nil
+}
+
+// persist writes the status of a job, replacing the previous one atomically.
+func (r *jobRunner) persist(rec jobRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	tmp := r.path(rec.ID, ".json.tmp")
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, r.path(rec.ID, ".json"))
+}
+
+// sweepExpired deletes the expired jobs until the global context is done.
+func (r *jobRunner) sweepExpired() {
+	ticker := time.NewTicker(min(r.cfg.Retention, jobSweepInterval))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case now := <-ticker.C:
+			r.mu.Lock()
+			for id, j := range r.jobs {
+				if r.expired(j.record, now) {
+					delete(r.jobs, id)
+					r.remove(id)
+				}
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// expired reports whether the job with status rec finished longer than the retention ago.
+func (r *jobRunner) expired(rec jobRecord, now time.Time) bool {
+	return r.cfg.Retention > 0 && rec.State.Finished() && now.Sub(rec.FinishedAt) > r.cfg.Retention
+}
+
+// remove deletes the status and result files of a job. Fetches of the result that already opened it still complete.
+func (r *jobRunner) remove(id string) {
+	_ = os.Remove(r.path(id, ".txt"))
+	_ = os.Remove(r.path(id, ".json"))
+}
+
+func (r *jobRunner) path(id, ext string) string {
+	return filepath.Join(r.cfg.Dir, id+ext)
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,265 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobs(t *testing.T) {
+	newService := func(ctx context.Context, dir string, workers int) service.Service {
+		return service.NewService(10, 2, 50, 100, 1000, service.WithJobs(ctx, service.JobConfig{
+			Dir:       dir,
+			Workers:   workers,
+			QueueSize: 2,
+			NLimit:    100_000_000,
+		}))
+	}
+
+	// wait polls the status of a job until it is in state.
+	wait := func(t *testing.T, s service.Service, id string, state domain.JobState) domain.JobStatus {
+		var st domain.JobStatus
+		assert.Eventually(t, func() bool {
+			var err error
+			st, err = s.GetJobStatus(context.Background(), id)
+			return err == nil && st.State == state
+		}, 10*time.Second, time.Millisecond, "job %s is not %s", id, state)
+
+		return st
+	}
+
+	// fetch collects the indexes and values of the result chunks.
+	fetch := func(s service.Service, id string, chunkSize int) ([]int, []string, error) {
+		indexes, values := []int{}, []string{}
+		err := s.FetchJobResult(context.Background(), domain.FetchJobResultRequest{
+			ID:        id,
+			ChunkSize: chunkSize,
+			SendFunc: func(chunk domain.FibonacciChunk) error {
+				indexes = append(indexes, chunk.Index)
+				values = append(values, slices.Clone(chunk.Values)...)
+
+				return nil
+			},
+		})
+
+		return indexes, values, err
+	}
+
+	t.Run("computes and persists the result", func(t *testing.T) {
+		ctx := context.Background()
+		dir := t.TempDir()
+		s := newService(ctx, dir, 2)
+
+		id, err := s.SubmitJob(ctx, domain.JobRequest{Start: -3, End: 2500})
+		assert.NoError(t, err)
+
+		st := wait(t, s, id, domain.JobSucceeded)
+		assert.Equal(t, 2503, st.Computed)
+		assert.Equal(t, -3, st.Start)
+		assert.Equal(t, 2500, st.End)
+		assert.False(t, st.FinishedAt.Before(st.StartedAt))
+
+		indexes, values, err := fetch(s, id, 10)
+		assert.NoError(t, err)
+		assert.Len(t, indexes, 251)
+		assert.Equal(t, []int{-3, 7, 17}, indexes[:3])
+		assert.Equal(t, []string{"2", "-1", "1", "0", "1", "1", "2"}, values[:7])
+
+		// Another process serves the jobs persisted by the first one.
+		restarted := newService(ctx, dir, 1)
+
+		restored, err := restarted.GetJobStatus(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobSucceeded, restored.State)
+
+		_, restoredValues, err := fetch(restarted, id, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, values, restoredValues)
+	})
+
+	t.Run("modulus and sequence", func(t *testing.T) {
+		ctx := context.Background()
+		s := newService(ctx, t.TempDir(), 1)
+
+		spec := domain.SequenceSpec{Name: service.SequenceLucas}
+		id, err := s.SubmitJob(ctx, domain.JobRequest{Sequence: spec, Start: 10, End: 16, Modulus: 10})
+		assert.NoError(t, err)
+
+		wait(t, s, id, domain.JobSucceeded)
+
+		_, values, err := fetch(s, id, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"3", "9", "2", "1", "3", "4"}, values) // 123, 199, 322, 521, 843, 1364
+	})
+
+	t.Run("cancel queued job", func(t *testing.T) {
+		ctx := context.Background()
+		s := newService(ctx, t.TempDir(), 0)
+
+		id, err := s.SubmitJob(ctx, domain.JobRequest{End: 10})
+		assert.NoError(t, err)
+
+		_, _, err = fetch(s, id, 5)
+		assert.ErrorIs(t, err, domain.ErrJobNotReady)
+
+		st, err := s.CancelJob(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobCanceled, st.State)
+		assert.Equal(t, "canceled by the client", st.Error)
+
+		// Canceling again does nothing.
+		st, err = s.CancelJob(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobCanceled, st.State)
+	})
+
+	t.Run("cancel running job", func(t *testing.T) {
+		ctx := context.Background()
+		s := newService(ctx, t.TempDir(), 1)
+
+		id, err := s.SubmitJob(ctx, domain.JobRequest{End: 50_000_000, Modulus: 1_000_000_007})
+		assert.NoError(t, err)
+
+		st := wait(t, s, id, domain.JobRunning)
+		assert.Less(t, st.Computed, 50_000_000)
+
+		_, err = s.CancelJob(ctx, id)
+		assert.NoError(t, err)
+
+		st = wait(t, s, id, domain.JobCanceled)
+		assert.Equal(t, "canceled by the client", st.Error)
+	})
+
+	t.Run("shutdown cancels jobs", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		dir := t.TempDir()
+		s := newService(ctx, dir, 0)
+
+		id, err := s.SubmitJob(ctx, domain.JobRequest{End: 10})
+		assert.NoError(t, err)
+
+		cancel()
+
+		st := wait(t, s, id, domain.JobCanceled)
+		assert.Equal(t, "canceled by the server shutting down", st.Error)
+	})
+
+	t.Run("restart cancels unfinished jobs", func(t *testing.T) {
+		ctx := context.Background()
+		dir := t.TempDir()
+
+		id, err := newService(ctx, dir, 0).SubmitJob(ctx, domain.JobRequest{End: 10})
+		assert.NoError(t, err)
+
+		st, err := newService(ctx, dir, 0).GetJobStatus(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobCanceled, st.State)
+		assert.Equal(t, "interrupted by a server restart", st.Error)
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		ctx := context.Background()
+		s := newService(ctx, t.TempDir(), 0)
+
+		for range 2 {
+			_, err := s.SubmitJob(ctx, domain.JobRequest{End: 10})
+			assert.NoError(t, err)
+		}
+
+		_, err := s.SubmitJob(ctx, domain.JobRequest{End: 10})
+		assert.ErrorIs(t, err, domain.ErrJobQueueFull)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		ctx := context.Background()
+		s := newService(ctx, t.TempDir(), 0)
+
+		_, err := s.SubmitJob(ctx, domain.JobRequest{Start: 10, End: 5})
+		assert.ErrorIs(t, err, domain.ErrInvalidRange)
+
+		_, err = s.SubmitJob(ctx, domain.JobRequest{End: 100_000_001, Modulus: 7})
+		assert.ErrorIs(t, err, domain.ErrTooLargeN)
+
+		_, err = s.GetJobStatus(ctx, "unknown")
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+		_, err = s.CancelJob(ctx, "unknown")
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+		_, _, err = fetch(s, "unknown", 5)
+		assert.ErrorIs(t, err, domain.ErrJobNotFound)
+
+		_, _, err = fetch(s, "unknown", 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidChunkSize)
+	})
+
+	t.Run("byte budget and charge", func(t *testing.T) {
+		ctx := context.Background()
+		s := service.NewService(10, 2, 50, 100, 1000, service.WithJobs(ctx, service.JobConfig{
+			Dir:        t.TempDir(),
+			QueueSize:  2,
+			NLimit:     100_000_000,
+			BytesLimit: 1000,
+		}))
+
+		_, err := s.SubmitJob(ctx, domain.JobRequest{End: 1000})
+		assert.ErrorIs(t, err, domain.ErrTooLargeResponse)
+
+		var charged int64
+		_, err = s.SubmitJob(ctx, domain.JobRequest{End: 100, Modulus: 7, ChargeFunc: func(digits int64) { charged += digits }})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), charged)
+	})
+
+	t.Run("expired jobs are deleted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		dir := t.TempDir()
+
+		first := newService(ctx, dir, 1)
+
+		id, err := first.SubmitJob(ctx, domain.JobRequest{End: 10})
+		assert.NoError(t, err)
+
+		wait(t, first, id, domain.JobSucceeded)
+
+		s := service.NewService(10, 2, 50, 100, 1000, service.WithJobs(ctx, service.JobConfig{
+			Dir:       dir,
+			Workers:   1,
+			QueueSize: 2,
+			NLimit:    100_000_000,
+			Retention: 10 * time.Millisecond,
+		}))
+
+		// Restored jobs are deleted once they have expired, and so are the jobs of this process.
+		assert.Eventually(t, func() bool {
+			_, err := s.GetJobStatus(ctx, id)
+			return errors.Is(err, domain.ErrJobNotFound)
+		}, 10*time.Second, time.Millisecond)
+
+		id, err = s.SubmitJob(ctx, domain.JobRequest{End: 10})
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			_, err := s.GetJobStatus(ctx, id)
+			files, _ := os.ReadDir(dir)
+			return errors.Is(err, domain.ErrJobNotFound) && len(files) == 0
+		}, 10*time.Second, time.Millisecond)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := service.NewService(10, 2, 50, 100, 100)
+
+		_, err := s.SubmitJob(context.Background(), domain.JobRequest{End: 10})
+		assert.ErrorIs(t, err, domain.ErrJobsDisabled)
+	})
+}
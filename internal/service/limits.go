@@ -0,0 +1,34 @@
+package service
+
+import "context"
+
+// ClientLimits override the limits of the service for the calls of a single client. Zero values keep the limits of the service.
+type ClientLimits struct {
+	NLimit       int // Replaces the limit of the number of terms of GetFibonacci calls and batch items
+	StreamNLimit int // Replaces the limit of the number of terms of streams
+}
+
+type clientLimitsKey struct{}
+
+// WithClientLimits returns a context applying limits to the calls made with it.
+func WithClientLimits(ctx context.Context, limits ClientLimits) context.Context {
+	return context.WithValue(ctx, clientLimitsKey{}, limits)
+}
+
+// nLimit returns the limit of the number of terms of a GetFibonacci call or batch item made with ctx.
+func (s *fibonacciService) nLimit(ctx context.Context) int {
+	if limits, ok := ctx.Value(clientLimitsKey{}).(ClientLimits); ok && limits.NLimit > 0 {
+		return limits.NLimit
+	}
+
+	return s.NLimit
+}
+
+// streamNLimit returns the limit of the number of terms of a stream made with ctx.
+func (s *fibonacciService) streamNLimit(ctx context.Context) int {
+	if limits, ok := ctx.Value(clientLimitsKey{}).(ClientLimits); ok && limits.StreamNLimit > 0 {
+		return limits.StreamNLimit
+	}
+
+	return s.StreamNLimit
+}
@@ -0,0 +1,22 @@
+//go:build !unix
+
+package service
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the first size bytes of f, as memory mapping is only supported on Unix.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func unmapFile(data []byte) error {
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package service
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile maps the first size bytes of f into memory read-only.
+// The mapping stays valid after f is closed, until it is released with unmapFile.
+func mapFile(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func unmapFile(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strconv"
+)
+
+// errResidueRange reports a saved modular term that is not reduced modulo the modulus.
+var errResidueRange = errors.New("residue out of range")
+
+// addMod returns (a + b) mod m for a, b < m.
+func addMod(a, b, m uint64) uint64 {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 || sum >= m {
+		sum -= m
+	}
+
+	return sum
+}
+
+// subMod returns (a - b) mod m for a, b < m.
+func subMod(a, b, m uint64) uint64 {
+	if a >= b {
+		return a - b
+	}
+
+	return m - (b - a)
+}
+
+// mulMod returns (a * b) mod m for a, b < m using a 128-bit intermediate product.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi, lo, m) // hi < m because a, b < m
+
+	return rem
+}
+
+// fibPairModAt returns F(n) mod m and F(n+1) mod m for any integer n.
+func fibPairModAt(ctx context.Context, n int, m uint64) (uint64, uint64, error) {
+	if n >= 0 {
+		return fibPairMod(ctx, uint64(n), m)
+	}
+
+	k := -uint64(n)
+
+	prev, curr, err := fibPairMod(ctx, k-1, m) // F(k-1), F(k)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if k%2 == 0 {
+		curr = subMod(0, curr, m)
+	} else {
+		prev = subMod(0, prev, m)
+	}
+
+	return curr, prev, nil // F(-k), F(-k+1)
+}
+
+// fibPairMod returns F(n) mod m and F(n+1) mod m using fast doubling.
+func fibPairMod(ctx context.Context, n, m uint64) (uint64, uint64, error) {
+	a, b := uint64(0), 1%m // F(k), F(k+1)
+
+	for bit := bits.Len64(n) - 1; bit >= 0; bit-- {
+		if err := contextError(ctx); err != nil {
+			return 0, 0, err
+		}
+
+		c := mulMod(a, subMod(addMod(b, b, m), a, m), m) // F(2k)
+		d := addMod(mulMod(a, a, m), mulMod(b, b, m), m) // F(2k+1)
+
+		if n>>bit&1 == 1 {
+			a, b = d, addMod(c, d, m)
+		} else {
+			a, b = c, d
+		}
+	}
+
+	return a, b, nil
+}
+
+// modFibState holds two consecutive Fibonacci terms reduced modulo m.
+type modFibState struct {
+	curr, next uint64
+	m          uint64
+	buf        []byte // Reused decimal conversion buffer
+}
+
+func newModFibStateAt(ctx context.Context, start int, m uint64) (*modFibState, error) {
+	curr, next, err := fibPairModAt(ctx, start, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modFibState{curr: curr, next: next, m: m}, nil
+}
+
+func restoreModFibState(r *tokenReader, m uint64) *modFibState {
+	s := &modFibState{curr: r.uvarint(), next: r.uvarint(), m: m}
+	if s.curr >= m || s.next >= m {
+		r.fail(errResidueRange)
+	}
+
+	return s
+}
+
+func (s *modFibState) advance() {
+	s.curr, s.next = s.next, addMod(s.curr, s.next, s.m)
+}
+
+func (s *modFibState) text() string {
+	s.buf = strconv.AppendUint(s.buf[:0], s.curr, 10)
+
+	return string(s.buf)
+}
+
+func (s *modFibState) save(buf []byte) []byte {
+	buf = binary.AppendUvarint(buf, s.curr)
+
+	return binary.AppendUvarint(buf, s.next)
+}
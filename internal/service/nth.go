@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+var (
+	log10Phi   = math.Log10(math.Phi)
+	log10Sqrt5 = math.Log10(math.Sqrt(5))
+)
+
+// fibDigits estimates the number of decimal digits of F(n) from Binet's formula,
+// |F(n)| ≈ phi^|n| / sqrt(5). The estimate is exact except within rounding error of a power of ten.
+func fibDigits(n int) int {
+	k := magnitude(n)
+	if k < 2 {
+		return 1
+	}
+
+	return int(float64(k)*log10Phi-log10Sqrt5) + 1
+}
+
+// magnitude returns |n|, which does not overflow for math.MinInt64 unlike -n.
+func magnitude(n int) uint64 {
+	if n < 0 {
+		return -uint64(n)
+	}
+
+	return uint64(n)
+}
+
+// fibPair returns F(n) and F(n+1) for any integer n.
+// Negative indices follow from F(-k) = (-1)^(k+1) F(k).
+func fibPair(ctx context.Context, n int) (*big.Int, *big.Int, error) {
+	if n >= 0 {
+		return fastDoubling(ctx, uint64(n))
+	}
+
+	k := -uint64(n)
+
+	prev, curr, err := fastDoubling(ctx, k-1) // F(k-1), F(k)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if k%2 == 0 {
+		curr.Neg(curr)
+	} else {
+		prev.Neg(prev)
+	}
+
+	return curr, prev, nil // F(-k), F(-k+1)
+}
+
+// fastDoubling returns F(n) and F(n+1) using
+//
+//	F(2k)   = F(k) * (2*F(k+1) - F(k))
+//	F(2k+1) = F(k)^2 + F(k+1)^2
+//
+// It needs O(log n) big-integer multiplications.
+func fastDoubling(ctx context.Context, n uint64) (*big.Int, *big.Int, error) {
+	var (
+		a, b   = new(big.Int), big.NewInt(1) // F(k), F(k+1)
+		t1, t2 = new(big.Int), new(big.Int)
+	)
+
+	for bit := bits.Len64(n) - 1; bit >= 0; bit-- {
+		if err := contextError(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		t1.Lsh(b, 1).Sub(t1, a).Mul(t1, a) // F(2k)
+		t2.Mul(a, a)
+		a.Mul(b, b)
+		t2.Add(t2, a) // F(2k+1)
+
+		a, t1 = t1, a
+		b, t2 = t2, b
+
+		if n>>bit&1 == 1 {
+			a.Add(a, b)
+			a, b = b, a
+		}
+	}
+
+	return a, b, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// maxPisanoModulus keeps every period and intermediate order within uint64, since π(m) <= 6m.
+const maxPisanoModulus = math.MaxUint64 / 6
+
+// pisanoPeriod returns the Pisano period π(m), the period of the Fibonacci sequence modulo m.
+//
+// m is factored into prime powers and π(m) is the least common multiple of their periods.
+// For a prime p, π(p) divides p-1 when p ≡ ±1 (mod 5) and 2(p+1) when p ≡ ±2 (mod 5),
+// so it is found by dividing prime factors out of that bound while it remains a period.
+// π(p^k) is π(p) multiplied by a power of p, which is found by trying each in turn.
+func pisanoPeriod(ctx context.Context, m uint64) (uint64, error) {
+	factors, err := factorize(ctx, m)
+	if err != nil {
+		return 0, err
+	}
+
+	period := uint64(1)
+
+	for _, f := range factors {
+		primePeriod, err := primePisanoPeriod(ctx, f.prime)
+		if err != nil {
+			return 0, err
+		}
+
+		power := uint64(1)
+		for i := 0; i < f.exp; i++ {
+			power *= f.prime
+		}
+
+		for {
+			ok, err := isPisanoPeriod(ctx, primePeriod, power)
+			if err != nil {
+				return 0, err
+			}
+
+			if ok {
+				break
+			}
+
+			primePeriod *= f.prime
+		}
+
+		period = lcm(period, primePeriod)
+	}
+
+	return period, nil
+}
+
+// primePisanoPeriod returns π(p) for a prime p.
+func primePisanoPeriod(ctx context.Context, p uint64) (uint64, error) {
+	var bound uint64
+
+	switch {
+	case p == 2:
+		return 3, nil
+	case p == 5:
+		return 20, nil
+	case p%5 == 1 || p%5 == 4:
+		bound = p - 1
+	default:
+		bound = 2 * (p + 1)
+	}
+
+	factors, err := factorize(ctx, bound)
+	if err != nil {
+		return 0, err
+	}
+
+	period := bound
+
+	for _, f := range factors {
+		for period%f.prime == 0 {
+			ok, err := isPisanoPeriod(ctx, period/f.prime, p)
+			if err != nil {
+				return 0, err
+			}
+
+			if !ok {
+				break
+			}
+
+			period /= f.prime
+		}
+	}
+
+	return period, nil
+}
+
+// isPisanoPeriod reports whether the Fibonacci sequence modulo m repeats after d terms,
+// that is whether F(d) ≡ 0 and F(d+1) ≡ 1 (mod m).
+func isPisanoPeriod(ctx context.Context, d, m uint64) (bool, error) {
+	a, b, err := fibPairMod(ctx, d, m)
+	if err != nil {
+		return false, err
+	}
+
+	return a == 0 && b == 1%m, nil
+}
+
+func gcd(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+
+	return a
+}
+
+func lcm(a, b uint64) uint64 {
+	return a / gcd(a, b) * b
+}
+
+type primeFactor struct {
+	prime uint64
+	exp   int
+}
+
+// factorize returns the prime factorization of n in ascending order of primes.
+// Small factors are removed by trial division and the rest is split with Pollard's rho.
+func factorize(ctx context.Context, n uint64) ([]primeFactor, error) {
+	counts := map[uint64]int{}
+
+	for p := uint64(2); p < 1000 && p*p <= n; p++ {
+		for n%p == 0 {
+			counts[p]++
+			n /= p
+		}
+	}
+
+	stack := []uint64{n}
+	for len(stack) > 0 {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+
+		x := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		switch {
+		case x == 1:
+		case isPrime(x):
+			counts[x]++
+		default:
+			d := pollardRho(x)
+			stack = append(stack, d, x/d)
+		}
+	}
+
+	factors := make([]primeFactor, 0, len(counts))
+	for p, exp := range counts {
+		factors = append(factors, primeFactor{prime: p, exp: exp})
+	}
+
+	slices.SortFunc(factors, func(a, b primeFactor) int {
+		return cmp.Compare(a.prime, b.prime)
+	})
+
+	return factors, nil
+}
+
+// isPrime is a Miller-Rabin test that is deterministic for all 64-bit integers.
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+
+	witnesses := []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+	for _, p := range witnesses {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	d := n - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+
+	for _, a := range witnesses {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+
+		composite := true
+		for i := 1; i < s; i++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+
+		if composite {
+			return false
+		}
+	}
+
+	return true
+}
+
+func powMod(base, exp, m uint64) uint64 {
+	result := 1 % m
+	base %= m
+
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = mulMod(result, base, m)
+		}
+
+		base = mulMod(base, base, m)
+		exp >>= 1
+	}
+
+	return result
+}
+
+// pollardRho returns a non-trivial divisor of the odd composite n using Pollard's rho.
+func pollardRho(n uint64) uint64 {
+	for c := uint64(1); ; c++ {
+		f := func(x uint64) uint64 { return addMod(mulMod(x, x, n), c, n) }
+
+		x, y, d := uint64(2), uint64(2), uint64(1)
+		for d == 1 {
+			x, y = f(x), f(f(y))
+			d = gcd(absDiff(x, y), n)
+		}
+
+		if d != n {
+			return d
+		}
+	}
+}
+
+func absDiff(a, b uint64) uint64 {
+	if a > b {
+		return a - b
+	}
+
+	return b - a
+}
@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"math/big"
+	"strconv"
+)
+
+// recurrenceState holds the k most recent terms of a linear recurrence.
+// Like fibState it advances in place, recycling the storage of the term that
+// drops out of the window for the new one.
+type recurrenceState struct {
+	coefficients []int64
+	window       []decimal // window[j] = a(i+j)
+	spare        decimal   // Storage for the next term
+	product      decimal   // Scratch space for coefficient products
+	buf          []byte    // Reused decimal conversion buffer
+}
+
+// newRecurrenceStateAt returns a state positioned at a(start) of seq.
+func newRecurrenceStateAt(ctx context.Context, seq Sequence, start int) (*recurrenceState, error) {
+	terms, err := recurrenceWindow(ctx, seq, start)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &recurrenceState{
+		coefficients: seq.Coefficients(),
+		window:       make([]decimal, len(terms)),
+	}
+
+	for i, term := range terms {
+		s.window[i].setBig(term)
+	}
+
+	return s, nil
+}
+
+func restoreRecurrenceState(r *tokenReader, seq Sequence) *recurrenceState {
+	s := &recurrenceState{
+		coefficients: seq.Coefficients(),
+		window:       make([]decimal, len(seq.Coefficients())),
+	}
+
+	for i := range s.window {
+		s.window[i].readBinary(r)
+	}
+
+	return s
+}
+
+func (s *recurrenceState) advance() {
+	k := len(s.window)
+	next := &s.spare
+	next.setZero()
+
+	for j, c := range s.coefficients {
+		term := &s.window[k-1-j] // a(i+k-1-j) is multiplied by c(j+1)
+
+		switch c {
+		case 0:
+		case 1:
+			next.add(next, term)
+		case -1:
+			next.sub(next, term)
+		default:
+			s.product.mulInt64(term, c)
+			next.add(next, &s.product)
+		}
+	}
+
+	oldest := s.window[0]
+	copy(s.window, s.window[1:])
+	s.window[k-1] = s.spare
+	s.spare = oldest
+}
+
+func (s *recurrenceState) text() string {
+	s.buf = s.window[0].append(s.buf[:0])
+
+	return string(s.buf)
+}
+
+func (s *recurrenceState) save(buf []byte) []byte {
+	for i := range s.window {
+		buf = s.window[i].appendBinary(buf)
+	}
+
+	return buf
+}
+
+// modRecurrenceState holds the k most recent terms of a linear recurrence reduced modulo m.
+type modRecurrenceState struct {
+	coefficients []uint64 // Reduced modulo m
+	window       []uint64
+	m            uint64
+	buf          []byte // Reused decimal conversion buffer
+}
+
+func newModRecurrenceStateAt(ctx context.Context, seq Sequence, start int, m uint64) (*modRecurrenceState, error) {
+	window, err := recurrenceWindowMod(ctx, seq, start, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modRecurrenceState{
+		coefficients: reduceCoefficients(seq.Coefficients(), m),
+		window:       window,
+		m:            m,
+	}, nil
+}
+
+func restoreModRecurrenceState(r *tokenReader, seq Sequence, m uint64) *modRecurrenceState {
+	s := &modRecurrenceState{
+		coefficients: reduceCoefficients(seq.Coefficients(), m),
+		window:       make([]uint64, len(seq.Coefficients())),
+		m:            m,
+	}
+
+	for i := range s.window {
+		if s.window[i] = r.uvarint(); s.window[i] >= m {
+			r.fail(errResidueRange)
+		}
+	}
+
+	return s
+}
+
+func (s *modRecurrenceState) advance() {
+	k := len(s.window)
+
+	var next uint64
+	for j, c := range s.coefficients {
+		next = addMod(next, mulMod(c, s.window[k-1-j], s.m), s.m)
+	}
+
+	copy(s.window, s.window[1:])
+	s.window[k-1] = next
+}
+
+func (s *modRecurrenceState) text() string {
+	s.buf = strconv.AppendUint(s.buf[:0], s.window[0], 10)
+
+	return string(s.buf)
+}
+
+func (s *modRecurrenceState) save(buf []byte) []byte {
+	for _, v := range s.window {
+		buf = binary.AppendUvarint(buf, v)
+	}
+
+	return buf
+}
+
+// recurrenceWindow returns a(n)..a(n+k-1) of seq.
+//
+// With v(i) = (a(i), ..., a(i+k-1)) and the companion matrix M of the recurrence,
+// v(n) = M^n v(0), which binary exponentiation computes in O(k^3 log n) multiplications.
+// Negative n use the inverse of M, which is integral only for invertible sequences.
+func recurrenceWindow(ctx context.Context, seq Sequence, n int) ([]*big.Int, error) {
+	v := seq.Seeds()
+	p := companionMatrix(seq.Coefficients())
+
+	e := uint64(n)
+	if n < 0 {
+		e = -uint64(n)
+		p = inverseCompanionMatrix(seq.Coefficients())
+	}
+
+	for ; e > 0; e >>= 1 {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+
+		if e&1 == 1 {
+			v = mulMatrixVector(p, v)
+		}
+
+		if e > 1 {
+			p = mulMatrix(p, p)
+		}
+	}
+
+	return v, nil
+}
+
+// companionMatrix returns M such that M (a(i), ..., a(i+k-1)) = (a(i+1), ..., a(i+k)).
+func companionMatrix(coefficients []int64) [][]*big.Int {
+	k := len(coefficients)
+	m := make([][]*big.Int, k)
+
+	for r := range m {
+		m[r] = make([]*big.Int, k)
+		for c := range m[r] {
+			m[r][c] = new(big.Int)
+		}
+
+		if r < k-1 {
+			m[r][r+1].SetInt64(1)
+		}
+	}
+
+	for j, c := range coefficients {
+		m[k-1][k-1-j].SetInt64(c)
+	}
+
+	return m
+}
+
+// inverseCompanionMatrix returns M^-1 such that M^-1 (a(i), ..., a(i+k-1)) = (a(i-1), ..., a(i+k-2)).
+// It follows from a(i-1) = (a(i+k-1) - c(1)*a(i+k-2) - ... - c(k-1)*a(i)) / c(k) with c(k) = ±1.
+func inverseCompanionMatrix(coefficients []int64) [][]*big.Int {
+	k := len(coefficients)
+	last := coefficients[k-1] // ±1, so dividing by it is multiplying by it
+	m := make([][]*big.Int, k)
+
+	for r := range m {
+		m[r] = make([]*big.Int, k)
+		for c := range m[r] {
+			m[r][c] = new(big.Int)
+		}
+
+		if r > 0 {
+			m[r][r-1].SetInt64(1)
+		}
+	}
+
+	m[0][k-1].SetInt64(last)
+	for j, c := range coefficients[:k-1] {
+		m[0][k-2-j].SetInt64(-c * last)
+	}
+
+	return m
+}
+
+// invertible reports whether seq extends to negative indices with integer terms, which holds when c(k) = ±1.
+func invertible(seq Sequence) bool {
+	coefficients := seq.Coefficients()
+	last := coefficients[len(coefficients)-1]
+
+	return last == 1 || last == -1
+}
+
+// reverseCoefficients returns the coefficients d(1)..d(k) of the recurrence b(t) = a(-t)
+// that runs seq backwards, d(k) = c(k) and d(l) = -c(k)*c(k-l), for an invertible seq.
+func reverseCoefficients(coefficients []int64) []int64 {
+	k := len(coefficients)
+	last := coefficients[k-1]
+	res := make([]int64, k)
+
+	res[k-1] = last
+	for l := 1; l < k; l++ {
+		res[l-1] = -last * coefficients[k-1-l]
+	}
+
+	return res
+}
+
+func mulMatrix(a, b [][]*big.Int) [][]*big.Int {
+	k := len(a)
+	res := make([][]*big.Int, k)
+	tmp := new(big.Int)
+
+	for r := range res {
+		res[r] = make([]*big.Int, k)
+		for c := range res[r] {
+			sum := new(big.Int)
+			for i := 0; i < k; i++ {
+				sum.Add(sum, tmp.Mul(a[r][i], b[i][c]))
+			}
+
+			res[r][c] = sum
+		}
+	}
+
+	return res
+}
+
+func mulMatrixVector(a [][]*big.Int, v []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(v))
+	tmp := new(big.Int)
+
+	for r := range res {
+		sum := new(big.Int)
+		for i := range v {
+			sum.Add(sum, tmp.Mul(a[r][i], v[i]))
+		}
+
+		res[r] = sum
+	}
+
+	return res
+}
+
+// recurrenceWindowMod returns a(n)..a(n+k-1) of seq reduced modulo m.
+func recurrenceWindowMod(ctx context.Context, seq Sequence, n int, m uint64) ([]uint64, error) {
+	v := reduceSeeds(seq.Seeds(), m)
+	p := companionMatrixMod(reduceCoefficients(seq.Coefficients(), m), m)
+
+	e := uint64(n)
+	if n < 0 {
+		e = -uint64(n)
+		p = reduceMatrix(inverseCompanionMatrix(seq.Coefficients()), m)
+	}
+
+	for ; e > 0; e >>= 1 {
+		if err := contextError(ctx); err != nil {
+			return nil, err
+		}
+
+		if e&1 == 1 {
+			v = mulMatrixVectorMod(p, v, m)
+		}
+
+		if e > 1 {
+			p = mulMatrixMod(p, p, m)
+		}
+	}
+
+	return v, nil
+}
+
+func companionMatrixMod(coefficients []uint64, m uint64) [][]uint64 {
+	k := len(coefficients)
+	res := make([][]uint64, k)
+
+	for r := range res {
+		res[r] = make([]uint64, k)
+		if r < k-1 {
+			res[r][r+1] = 1 % m
+		}
+	}
+
+	for j, c := range coefficients {
+		res[k-1][k-1-j] = c
+	}
+
+	return res
+}
+
+func mulMatrixMod(a, b [][]uint64, m uint64) [][]uint64 {
+	k := len(a)
+	res := make([][]uint64, k)
+
+	for r := range res {
+		res[r] = make([]uint64, k)
+		for c := range res[r] {
+			for i := 0; i < k; i++ {
+				res[r][c] = addMod(res[r][c], mulMod(a[r][i], b[i][c], m), m)
+			}
+		}
+	}
+
+	return res
+}
+
+func mulMatrixVectorMod(a [][]uint64, v []uint64, m uint64) []uint64 {
+	res := make([]uint64, len(v))
+
+	for r := range res {
+		for i := range v {
+			res[r] = addMod(res[r], mulMod(a[r][i], v[i], m), m)
+		}
+	}
+
+	return res
+}
+
+// reduceMatrix returns the entries of a reduced into [0, m).
+func reduceMatrix(a [][]*big.Int, m uint64) [][]uint64 {
+	res := make([][]uint64, len(a))
+	for r := range a {
+		res[r] = reduceSeeds(a[r], m)
+	}
+
+	return res
+}
+
+// reduceSeeds returns the seeds reduced into [0, m).
+func reduceSeeds(seeds []*big.Int, m uint64) []uint64 {
+	mod := new(big.Int).SetUint64(m)
+	res := make([]uint64, len(seeds))
+
+	for i, seed := range seeds {
+		res[i] = new(big.Int).Mod(seed, mod).Uint64()
+	}
+
+	return res
+}
+
+// reduceCoefficients returns the coefficients reduced into [0, m).
+func reduceCoefficients(coefficients []int64, m uint64) []uint64 {
+	res := make([]uint64, len(coefficients))
+
+	for i, c := range coefficients {
+		if c >= 0 {
+			res[i] = uint64(c) % m
+		} else {
+			res[i] = (m - (-uint64(c))%m) % m
+		}
+	}
+
+	return res
+}
+
+// sequenceDigits estimates the number of decimal digits of a(n) of seq.
+// Negative n are estimated from the growth of the reversed recurrence.
+func sequenceDigits(seq Sequence, n int) int {
+	if isFibonacci(seq) {
+		return fibDigits(n)
+	}
+
+	seedDigits := 1
+	for _, seed := range seq.Seeds() {
+		seedDigits = max(seedDigits, len(new(big.Int).Abs(seed).Text(10)))
+	}
+
+	coefficients := seq.Coefficients()
+	if n < 0 {
+		coefficients = reverseCoefficients(coefficients)
+	}
+
+	// Clamped, as converting a float beyond the range of int is undefined.
+	growth := min(math.Ceil(float64(magnitude(n))*growthRate(coefficients)), math.MaxInt32)
+
+	return seedDigits + int(growth)
+}
+
+// growthRate estimates how many decimal digits each term of a recurrence adds asymptotically,
+// the base-10 logarithm of the largest root of its characteristic polynomial.
+// It runs the recurrence in floating point from all-ones seeds, normalizing every step,
+// and averages the per-step growth once the dominant root has taken over.
+func growthRate(coefficients []int64) float64 {
+	const warmup, steps = 256, 256
+
+	k := len(coefficients)
+	window := make([]float64, k)
+	for i := range window {
+		window[i] = 1
+	}
+
+	var total float64
+	for step := 0; step < warmup+steps; step++ {
+		var next float64
+		for j, c := range coefficients {
+			next += float64(c) * window[k-1-j]
+		}
+
+		copy(window, window[1:])
+		window[k-1] = next
+
+		norm := 0.0
+		for _, v := range window {
+			norm = max(norm, math.Abs(v))
+		}
+
+		if norm == 0 {
+			return 0
+		}
+
+		for i := range window {
+			window[i] /= norm
+		}
+
+		if step >= warmup {
+			total += math.Log10(norm)
+		}
+	}
+
+	return max(total/steps, 0)
+}
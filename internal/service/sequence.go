@@ -0,0 +1,217 @@
+package service
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"fibonacci/internal/domain"
+)
+
+// Names of the built-in sequences, which are also accepted in domain.SequenceSpec.
+const (
+	SequenceFibonacci  = "fibonacci"
+	SequenceLucas      = "lucas"
+	SequencePell       = "pell"
+	SequenceTribonacci = "tribonacci"
+	SequenceKBonacci   = "kbonacci" // Parameterized by domain.SequenceSpec.K
+	SequenceCustom     = "custom"   // Defined by domain.SequenceSpec.Seeds and Coefficients
+)
+
+// maxSequenceOrder bounds the number of preceding terms a recurrence may depend on.
+const maxSequenceOrder = 16
+
+// Sequence is a linear recurrence
+//
+//	a(n) = c(1)*a(n-1) + c(2)*a(n-2) + ... + c(k)*a(n-k)
+//
+// defined by its first k terms. Implementations must return the same values on every call.
+type Sequence interface {
+	// Seeds returns the initial terms a(0)..a(k-1).
+	Seeds() []*big.Int
+
+	// Coefficients returns c(1)..c(k).
+	Coefficients() []int64
+}
+
+// Built-in sequences.
+var (
+	Fibonacci  Sequence = fibonacci{}
+	Lucas               = mustRecurrence([]int64{2, 1}, []int64{1, 1})
+	Pell                = mustRecurrence([]int64{0, 1}, []int64{2, 1})
+	Tribonacci          = mustRecurrence([]int64{0, 0, 1}, []int64{1, 1, 1})
+)
+
+// fibonacci is the default sequence. The service recognizes it to use the
+// dedicated fast doubling and two-term paths instead of the generic recurrence.
+type fibonacci struct{}
+
+func (fibonacci) Seeds() []*big.Int {
+	return []*big.Int{big.NewInt(0), big.NewInt(1)}
+}
+
+func (fibonacci) Coefficients() []int64 {
+	return []int64{1, 1}
+}
+
+func isFibonacci(seq Sequence) bool {
+	_, ok := seq.(fibonacci)
+
+	return ok
+}
+
+// recurrence is a Sequence with fixed seeds and coefficients.
+type recurrence struct {
+	seeds        []*big.Int
+	coefficients []int64
+}
+
+// NewRecurrence returns the sequence with the given initial terms and coefficients.
+// Both must have the same length k, between 1 and 16.
+func NewRecurrence(seeds []*big.Int, coefficients []int64) (Sequence, error) {
+	if len(seeds) != len(coefficients) {
+		return nil, fmt.Errorf("%w: got %d seeds for %d coefficients", domain.ErrInvalidSequence, len(seeds), len(coefficients))
+	}
+
+	if len(seeds) == 0 || len(seeds) > maxSequenceOrder {
+		return nil, fmt.Errorf("%w: order must be between 1 and %d", domain.ErrInvalidSequence, maxSequenceOrder)
+	}
+
+	r := &recurrence{
+		seeds:        make([]*big.Int, len(seeds)),
+		coefficients: append([]int64(nil), coefficients...),
+	}
+
+	for i, seed := range seeds {
+		if seed == nil {
+			return nil, fmt.Errorf("%w: seed %d is missing", domain.ErrInvalidSequence, i)
+		}
+
+		r.seeds[i] = new(big.Int).Set(seed)
+	}
+
+	return r, nil
+}
+
+// KBonacci returns the k-bonacci sequence, in which every term is the sum of the k preceding ones,
+// starting from k-1 zeros followed by a one.
+func KBonacci(k int) (Sequence, error) {
+	if k < 2 || k > maxSequenceOrder {
+		return nil, fmt.Errorf("%w: k must be between 2 and %d", domain.ErrInvalidSequence, maxSequenceOrder)
+	}
+
+	seeds := make([]*big.Int, k)
+	coefficients := make([]int64, k)
+
+	for i := range seeds {
+		seeds[i] = new(big.Int)
+		coefficients[i] = 1
+	}
+
+	seeds[k-1].SetInt64(1)
+
+	return NewRecurrence(seeds, coefficients)
+}
+
+func mustRecurrence(seeds []int64, coefficients []int64) Sequence {
+	values := make([]*big.Int, len(seeds))
+	for i, seed := range seeds {
+		values[i] = big.NewInt(seed)
+	}
+
+	seq, err := NewRecurrence(values, coefficients)
+	if err != nil {
+		panic(err)
+	}
+
+	return seq
+}
+
+func (r *recurrence) Seeds() []*big.Int {
+	seeds := make([]*big.Int, len(r.seeds))
+	for i, seed := range r.seeds {
+		seeds[i] = new(big.Int).Set(seed)
+	}
+
+	return seeds
+}
+
+func (r *recurrence) Coefficients() []int64 {
+	return append([]int64(nil), r.coefficients...)
+}
+
+var registry = struct {
+	sync.RWMutex
+	sequences map[string]Sequence
+}{
+	sequences: map[string]Sequence{
+		SequenceFibonacci:  Fibonacci,
+		SequenceLucas:      Lucas,
+		SequencePell:       Pell,
+		SequenceTribonacci: Tribonacci,
+	},
+}
+
+// RegisterSequence makes seq available under name to every request that selects it.
+// It fails if the name is empty, reserved or already registered, or if seq is not a valid recurrence.
+func RegisterSequence(name string, seq Sequence) error {
+	if name == "" || name == SequenceKBonacci || name == SequenceCustom {
+		return fmt.Errorf("%w: name %q is reserved", domain.ErrInvalidSequence, name)
+	}
+
+	if _, err := NewRecurrence(seq.Seeds(), seq.Coefficients()); err != nil {
+		return err
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.sequences[name]; ok {
+		return fmt.Errorf("%w: sequence %q is already registered", domain.ErrInvalidSequence, name)
+	}
+
+	registry.sequences[name] = seq
+
+	return nil
+}
+
+// LookupSequence returns the sequence registered under name.
+func LookupSequence(name string) (Sequence, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	seq, ok := registry.sequences[name]
+
+	return seq, ok
+}
+
+// resolveSequence returns the sequence selected by spec. The zero spec selects Fibonacci.
+func resolveSequence(spec domain.SequenceSpec) (Sequence, error) {
+	switch spec.Name {
+	case "":
+		return Fibonacci, nil
+
+	case SequenceKBonacci:
+		return KBonacci(spec.K)
+
+	case SequenceCustom:
+		seeds := make([]*big.Int, len(spec.Seeds))
+		for i, seed := range spec.Seeds {
+			value, ok := new(big.Int).SetString(seed, 10)
+			if !ok {
+				return nil, fmt.Errorf("%w: seed %q is not an integer", domain.ErrInvalidSequence, seed)
+			}
+
+			seeds[i] = value
+		}
+
+		return NewRecurrence(seeds, spec.Coefficients)
+	}
+
+	seq, ok := LookupSequence(spec.Name)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown sequence %q", domain.ErrInvalidSequence, spec.Name)
+	}
+
+	return seq, nil
+}
@@ -9,17 +9,45 @@ import (
 
 	"fibonacci/internal/domain"
 	"fibonacci/internal/metrics"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:generate mockery --name=Service --with-expecter --output=../mock --outpkg=mock --case=underscore
 
 // Service defines the interface for Fibonacci calculations.
 type Service interface {
-	// GetFibonacci calculates the first n Fibonacci numbers.
-	GetFibonacci(ctx context.Context, n int) ([]string, error)
+	// GetFibonacci calculates the Fibonacci numbers, or the terms of the selected sequence, in the requested range.
+	GetFibonacci(ctx context.Context, req domain.FibonacciRequest) ([]string, error)
 
-	// GetFibonacciStream streams chunks of Fibonacci numbers based on the request.
+	// GetFibonacciStream streams chunks of Fibonacci numbers, or of the selected sequence, based on the request.
 	GetFibonacciStream(ctx context.Context, req domain.FibonacciStreamRequest) error
+
+	// GetFibonacciFlow streams chunks like GetFibonacciStream, but only as many as the commands request,
+	// letting the consumer change their size, pause or seek within the range.
+	GetFibonacciFlow(ctx context.Context, req domain.FibonacciFlowRequest) error
+
+	// GetNth calculates the single Fibonacci number F(n), or the n-th term of the selected sequence.
+	GetNth(ctx context.Context, req domain.FibonacciNthRequest) (string, error)
+
+	// GetPisanoPeriod calculates the period of the Fibonacci sequence modulo m.
+	GetPisanoPeriod(ctx context.Context, modulus uint64) (uint64, error)
+
+	// GetFibonacciBatch answers many range and nth-term requests at once, computing the terms they share only once.
+	// Items fail individually; the error is returned when the batch as a whole cannot be answered.
+	GetFibonacciBatch(ctx context.Context, items []domain.BatchItem) ([]domain.BatchResult, error)
+
+	// SubmitJob queues the computation of a range too large for a single call and returns the job ID.
+	SubmitJob(ctx context.Context, req domain.JobRequest) (string, error)
+
+	// GetJobStatus reports the progress of a job.
+	GetJobStatus(ctx context.Context, id string) (domain.JobStatus, error)
+
+	// CancelJob cancels a job that has not finished yet and returns its status.
+	CancelJob(ctx context.Context, id string) (domain.JobStatus, error)
+
+	// FetchJobResult streams the result of a succeeded job in chunks.
+	FetchJobResult(ctx context.Context, req domain.FetchJobResultRequest) error
 }
 
 // FibonacciService implements the Service interface with additional constraints.
@@ -28,171 +56,650 @@ type fibonacciService struct {
 	MinChunkSize int // Minimum allowed chunk size for streaming
 	NLimit       int // Maximum limit for the Fibonacci sequence length
 	StreamNLimit int // Maximum limit for the Fibonacci streaming sequence length
+
+	NthDigitsLimit int // Maximum number of decimal digits of a single term returned by GetNth or in a range
+
+	requestBytesLimit int64 // Maximum estimated size of a GetFibonacci result, see WithByteBudgets
+	streamBytesLimit  int64 // Maximum estimated size of a whole stream
+	chunkBytesLimit   int64 // Maximum estimated size of a single chunk
+
+	tokenSecret []byte      // Key for continuation tokens, see WithTokenSecret
+	tokens      tokenSigner // Issues and verifies continuation tokens
+
+	batchItemsLimit  int   // Maximum number of items of a batch, see WithBatchLimits
+	batchTermsLimit  int   // Maximum number of distinct terms a batch may return
+	batchDigitsLimit int64 // Maximum estimated number of digits a batch may return, counting repeated terms
+
+	jobs *jobRunner // Runs background jobs, nil unless enabled with WithJobs
+
+	cache *checkpointCache // Checkpoints and prefixes of computed sequences, nil unless enabled with WithCache
+
+	table *Table // Precomputed Fibonacci terms, nil unless enabled with WithTable
+
+	calls   *flightGroup    // Concurrent GetFibonacci calls, nil unless enabled with WithCoalescing
+	streams *broadcastGroup // Concurrent GetFibonacciStream calls, nil unless enabled with WithCoalescing
+}
+
+// Option configures optional behavior of the service.
+type Option func(*fibonacciService)
+
+// WithTokenSecret sets the key that stream continuation tokens are signed with.
+// Instances sharing the key accept each other's tokens. Without it a random key is used,
+// so tokens are only valid until the process exits.
+func WithTokenSecret(secret []byte) Option {
+	return func(s *fibonacciService) {
+		s.tokenSecret = secret
+	}
 }
 
-func NewService(maxChunkSize int, minChunkSize int, nLimit, streamNLimit int) Service {
-	return &fibonacciService{
-		MaxChunkSize: maxChunkSize,
-		MinChunkSize: minChunkSize,
-		NLimit:       nLimit,
-		StreamNLimit: streamNLimit,
+// WithBatchLimits limits batches to maxItems items returning at most maxTerms distinct terms altogether,
+// and at most maxDigits digits in their response. As items may repeat the same terms, the response can be far
+// larger than the terms computed; a maxDigits of zero is not enforced.
+// Without it a batch may have 1000 items returning as many terms as a single range.
+func WithBatchLimits(maxItems, maxTerms int, maxDigits int64) Option {
+	return func(s *fibonacciService) {
+		s.batchItemsLimit = maxItems
+		s.batchTermsLimit = maxTerms
+		s.batchDigitsLimit = maxDigits
 	}
 }
 
-func (s *fibonacciService) GetFibonacci(ctx context.Context, n int) ([]string, error) {
-	if n < 0 {
-		return nil, domain.ErrNegativeN
+func NewService(maxChunkSize int, minChunkSize int, nLimit, streamNLimit, nthDigitsLimit int, opts ...Option) Service {
+	s := &fibonacciService{
+		MaxChunkSize:    maxChunkSize,
+		MinChunkSize:    minChunkSize,
+		NLimit:          nLimit,
+		StreamNLimit:    streamNLimit,
+		NthDigitsLimit:  nthDigitsLimit,
+		batchItemsLimit: 1000,
+		batchTermsLimit: nLimit,
 	}
 
-	if n > s.NLimit {
-		return nil, fmt.Errorf("%w: must not exceed %d", domain.ErrTooLargeN, s.NLimit)
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.tokens = newTokenSigner(s.tokenSecret)
+
+	return s
+}
+
+func (s *fibonacciService) GetFibonacci(ctx context.Context, req domain.FibonacciRequest) (_ []string, err error) {
+	ctx, span := tracer.Start(ctx, "GetFibonacci", trace.WithAttributes(attrStart.Int(req.Start), attrEnd.Int(req.End)))
+	defer func() { endSpan(span, err) }()
+
+	r, err := s.newSequenceRange(req.Sequence, req.Start, req.End, req.Modulus, s.nLimit(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	span.SetAttributes(rangeAttributes(r)...)
+
+	if err := checkBytes("n", r, r.start, r.end, s.requestBytesLimit); err != nil {
+		return nil, err
 	}
 
 	start := time.Now()
 
-	res, err := getFibonacci(ctx, n)
+	var res []string
+	if s.calls != nil {
+		res, err = s.calls.do(ctx, rangeKey(r), func(ctx context.Context) ([]string, error) {
+			return s.getFibonacci(ctx, r)
+		})
+	} else {
+		res, err = s.getFibonacci(ctx, r)
+	}
+
 	if err != nil {
 		return nil, err
 	}
 
-	metrics.FibonacciCalculationDuration.WithLabelValues().Observe(float64(time.Since(start).Nanoseconds()))
-	metrics.FibonacciCalculationsTotal.WithLabelValues(strconv.Itoa(n)).Inc()
+	span.SetAttributes(attrDigits.Int64(valuesDigits(res)))
+
+	observe("Fibonacci", uint64(r.end-r.start), start)
 
 	return res, nil
 }
 
-// getFibonacci generates the Fibonacci sequence up to n using dp.
-func getFibonacci(ctx context.Context, n int) ([]string, error) {
-	seq := make([]string, n)
+// getFibonacci returns the terms of the range from the cached prefix of the sequence or generates them,
+// caching them in turn if they are a prefix.
+func (s *fibonacciService) getFibonacci(ctx context.Context, r sequenceRange) ([]string, error) {
+	if r.start == r.end {
+		return []string{}, nil
+	}
 
-	if n > 0 {
-		seq[0] = "0"
+	key := sequenceKey(r.spec, r.modulus)
+	if seq, ok := s.cache.prefix(key, r.start, r.end); ok {
+		return seq, nil
 	}
 
-	if n > 1 {
-		seq[1] = "1"
+	seq := make([]string, r.end-r.start)
+
+	state, err := s.newState(ctx, r)
+	if err != nil {
+		return nil, err
 	}
 
-	for i := 2; i < n; i++ {
-		select {
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				return nil, domain.ErrContextCanceled
-			}
-			return nil, ctx.Err()
-		default:
-			seq[i] = addStrings(seq[i-1], seq[i-2])
+	for i := range seq {
+		if err := contextError(ctx); err != nil {
+			return nil, err
 		}
+
+		seq[i] = state.text()
+		state.advance()
+	}
+
+	if r.start == 0 {
+		s.cache.addPrefix(key, seq)
 	}
 
 	return seq, nil
 }
 
-func (s *fibonacciService) GetFibonacciStream(ctx context.Context, req domain.FibonacciStreamRequest) error {
-	if req.N < 0 {
-		return domain.ErrNegativeN
-	}
-
-	if req.N > s.StreamNLimit {
-		return fmt.Errorf("%w: must not exceed %d", domain.ErrTooLargeN, s.NLimit)
-	}
+func (s *fibonacciService) GetFibonacciStream(ctx context.Context, req domain.FibonacciStreamRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "GetFibonacciStream", trace.WithAttributes(attrChunkSize.Int(req.ChunkSize)))
+	defer func() { endSpan(span, err) }()
 
-	if req.ChunkSize > s.MaxChunkSize {
-		return fmt.Errorf("%w: must not exceed %d", domain.ErrInvalidChunkSize, s.MaxChunkSize)
+	r, err := s.streamRange(ctx, req)
+	if err != nil {
+		return err
 	}
 
-	if req.ChunkSize < s.MinChunkSize {
-		return fmt.Errorf("%w: must be at least %d", domain.ErrInvalidChunkSize, s.MinChunkSize)
-	}
+	span.SetAttributes(rangeAttributes(r)...)
 
 	start := time.Now()
 
-	err := processChunks(ctx, req.N, req.ChunkSize, req.SendFunc)
+	if s.streams != nil {
+		key := fmt.Sprintf("%s %d", rangeKey(r), req.ChunkSize)
+		err = s.streams.stream(ctx, key, req.ChunkSize, req.SendFunc, func(ctx context.Context) (*chunkCursor, error) {
+			return s.newChunkCursor(ctx, r)
+		})
+	} else {
+		err = s.processChunks(ctx, r, req.ChunkSize, req.SendFunc)
+	}
+
 	if err != nil {
 		return err
 	}
 
-	metrics.FibonacciStreamCalculationDuration.WithLabelValues().Observe(float64(time.Since(start).Nanoseconds()))
-	metrics.FibonacciStreamCalculationsTotal.WithLabelValues(strconv.Itoa(req.N), strconv.Itoa(req.ChunkSize)).Inc()
+	observe("FibonacciStream", uint64(r.end-r.start), start)
 
 	return nil
 }
 
-// processChunks divides the Fibonacci sequence into chunks and streams each chunk.
-// it also utilizes only one array we
-func processChunks(ctx context.Context, n, chunkSize int, send func([]string, int) error) error {
+func (s *fibonacciService) GetFibonacciFlow(ctx context.Context, req domain.FibonacciFlowRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "GetFibonacciFlow", trace.WithAttributes(attrChunkSize.Int(req.Stream.ChunkSize)))
+	defer func() { endSpan(span, err) }()
+
+	r, err := s.streamRange(ctx, req.Stream)
+	if err != nil {
+		return err
+	}
+
+	span.SetAttributes(rangeAttributes(r)...)
+
+	start := time.Now()
+
+	c, err := s.newChunkCursor(ctx, r)
+	if err != nil {
+		return err
+	}
+
 	var (
-		prev1, prev2 = "0", "1"
-		iteration    = 0
-		chunk        = make([]string, chunkSize) // Reused chunk array
+		chunkSize = req.Stream.ChunkSize
+		credits   = 0 // Chunks requested but not sent yet
+		commands  = req.Commands
+		sent      = 0 // Chunks sent so far
 	)
 
-	for i := 0; i < n; i += chunkSize {
-		select {
-		case <-ctx.Done():
-			if errors.Is(ctx.Err(), context.Canceled) {
-				return domain.ErrContextCanceled
+	for !c.done() {
+		if credits == 0 && commands == nil {
+			return nil
+		}
+
+		var (
+			cmd domain.FlowCommand
+			ok  bool
+		)
+
+		if credits > 0 {
+			// Pending commands are applied before the next chunk, so a pause or seek
+			// is not delayed by the chunks requested before it.
+			select {
+			case cmd, ok = <-commands:
+			default:
+				if err := contextError(ctx); err != nil {
+					return err
+				}
+
+				if err := sendChunk(ctx, c, sent, chunkSize, req.Stream.SendFunc); err != nil {
+					return err
+				}
+
+				credits--
+				sent++
+
+				continue
 			}
+		} else {
+			select {
+			case <-ctx.Done():
+				return contextError(ctx)
+			case cmd, ok = <-commands:
+			}
+		}
 
-			return ctx.Err()
-		default:
-			iteration++
-			end := i + chunkSize
-			if end > n {
-				end = n
+		if !ok {
+			// Receiving from a nil channel blocks, so the remaining chunks are sent without waiting for commands.
+			commands = nil
+
+			continue
+		}
+
+		switch cmd.Kind {
+		case domain.FlowNext:
+			if cmd.Value <= 0 {
+				return domain.NewFieldError("next", fmt.Errorf("%w: must request at least one chunk", domain.ErrInvalidCommand))
 			}
 
-			currentChunkSize := end - i
+			credits += cmd.Value
+		case domain.FlowChunkSize:
+			if err := s.checkChunkSize(cmd.Value); err != nil {
+				return err
+			}
 
-			for j := 0; j < currentChunkSize; j++ {
-				chunk[j] = prev1
-				prev1, prev2 = prev2, addStrings(prev1, prev2)
+			if err := s.checkChunkBytes(r, cmd.Value); err != nil {
+				return err
 			}
 
-			err := send(chunk[:currentChunkSize], i)
-			if err != nil {
+			chunkSize = cmd.Value
+		case domain.FlowPause:
+			credits = 0
+		case domain.FlowSeek:
+			if err := c.seek(ctx, cmd.Value); err != nil {
 				return err
 			}
+		default:
+			return fmt.Errorf("%w: expected next, chunk_size, pause set to true or seek", domain.ErrInvalidCommand)
 		}
 	}
 
+	observe("FibonacciFlow", uint64(r.end-r.start), start)
+
 	return nil
 }
 
-func addStrings(num1, num2 string) string {
-	var result []byte
-	carry := false
-	i, j := len(num1)-1, len(num2)-1
+// streamRange validates a stream request and returns its range, either requested or resumed from its token.
+func (s *fibonacciService) streamRange(ctx context.Context, req domain.FibonacciStreamRequest) (sequenceRange, error) {
+	var (
+		r   sequenceRange
+		err error
+	)
+
+	if req.ResumeToken != "" {
+		r, err = s.resumeSequenceRange(ctx, req.ResumeToken)
+	} else {
+		r, err = s.newSequenceRange(req.Sequence, req.Start, req.End, req.Modulus, s.streamNLimit(ctx))
+	}
+
+	if err != nil {
+		return sequenceRange{}, err
+	}
+
+	if err := checkBytes("n", r, r.start, r.end, s.streamBytesLimit); err != nil {
+		return sequenceRange{}, err
+	}
+
+	if err := s.checkChunkSize(req.ChunkSize); err != nil {
+		return sequenceRange{}, err
+	}
+
+	if err := s.checkChunkBytes(r, req.ChunkSize); err != nil {
+		return sequenceRange{}, err
+	}
+
+	return r, nil
+}
+
+// checkChunkSize checks a chunk size against the configured bounds.
+func (s *fibonacciService) checkChunkSize(chunkSize int) error {
+	if chunkSize > s.MaxChunkSize {
+		return domain.NewFieldError("chunk_size", fmt.Errorf("%w: must not exceed %d", domain.ErrInvalidChunkSize, s.MaxChunkSize))
+	}
 
-	for i >= 0 || j >= 0 || carry {
-		sum := 0
+	if chunkSize < s.MinChunkSize {
+		return domain.NewFieldError("chunk_size", fmt.Errorf("%w: must be at least %d", domain.ErrInvalidChunkSize, s.MinChunkSize))
+	}
+
+	return nil
+}
 
-		if carry {
-			sum++
+// sequenceRange is a validated request for the terms a(start)..a(end-1) of a sequence.
+type sequenceRange struct {
+	spec    domain.SequenceSpec // The request the sequence was resolved from, kept for continuation tokens
+	seq     Sequence
+	start   int
+	end     int
+	modulus uint64 // Reduces every term modulo it unless it is zero
+	saved   []byte // State at start saved by a previous stream, if it is resumed
+}
+
+// newState returns a state positioned at the first term of the range.
+func (s *fibonacciService) newState(ctx context.Context, r sequenceRange) (sequenceState, error) {
+	if r.saved != nil {
+		state, err := restoreState(r.seq, r.modulus, r.saved)
+		if err != nil {
+			return nil, domain.NewFieldError("resume_token", fmt.Errorf("%w: %w", domain.ErrInvalidToken, err))
 		}
 
-		if i >= 0 {
-			sum += int(num1[i] - '0')
-			i--
+		return s.cache.track(sequenceKey(r.spec, r.modulus), state, r.start, r.modulus), nil
+	}
+
+	return s.stateAt(ctx, r, r.start)
+}
+
+// stateAt returns a state positioned at a(start) of the sequence of the range.
+func (s *fibonacciService) stateAt(ctx context.Context, r sequenceRange, start int) (sequenceState, error) {
+	return s.seed(ctx, sequenceKey(r.spec, r.modulus), r.seq, start, r.modulus)
+}
+
+// seed returns a state positioned at a(start) of seq reduced modulo modulus, reading the terms from the table
+// while it holds them, or else advanced from a cached checkpoint if possible. key identifies the sequence, see sequenceKey.
+func (s *fibonacciService) seed(ctx context.Context, key string, seq Sequence, start int, modulus uint64) (sequenceState, error) {
+	if s.table.holds(seq, modulus, start) {
+		return &tableState{t: s.table, index: start}, nil
+	}
+
+	return s.cache.stateAt(ctx, key, seq, start, modulus)
+}
+
+// resumeSequenceRange returns the remainder of the range a continuation token was issued for.
+// The remainder is checked against the current limits of the caller, which may differ from those of the client
+// that started the stream, or may have been lowered since.
+func (s *fibonacciService) resumeSequenceRange(ctx context.Context, token string) (sequenceRange, error) {
+	p, err := s.tokens.decode(token)
+	if err != nil {
+		return sequenceRange{}, domain.NewFieldError("resume_token", err)
+	}
+
+	if _, err := resolveSequence(p.spec); err != nil || p.position > p.end {
+		return sequenceRange{}, domain.NewFieldError("resume_token", fmt.Errorf("%w: inconsistent range", domain.ErrInvalidToken))
+	}
+
+	r, err := s.newSequenceRange(p.spec, p.position, p.end, p.modulus, s.streamNLimit(ctx))
+	if err != nil {
+		return sequenceRange{}, err
+	}
+
+	r.saved = p.state
+
+	return r, nil
+}
+
+// newSequenceRange resolves the sequence and checks the range [start, end) against the length
+// limit and, unless values are reduced modulo modulus, the size of its largest term against NthDigitsLimit.
+// Negative indices are accepted for sequences that can be run backwards.
+func (s *fibonacciService) newSequenceRange(spec domain.SequenceSpec, start, end int, modulus uint64, limit int) (sequenceRange, error) {
+	seq, err := resolveSequence(spec)
+	if err != nil {
+		return sequenceRange{}, domain.NewFieldError("sequence", err)
+	}
+
+	if end < start {
+		return sequenceRange{}, domain.NewFieldError("end", fmt.Errorf("%w: end %d is before start %d", domain.ErrInvalidRange, end, start))
+	}
+
+	if end-start > limit {
+		return sequenceRange{}, domain.NewFieldError("n", fmt.Errorf("%w: must not exceed %d", domain.ErrTooLargeN, limit))
+	}
+
+	if start < 0 && !invertible(seq) {
+		return sequenceRange{}, domain.NewFieldError("start", fmt.Errorf("%w: start %d", domain.ErrUndefinedIndex, start))
+	}
+
+	if end > start && modulus == 0 {
+		// Terms grow away from zero in both directions, so the largest is at one of the ends.
+		if err := s.checkDigits("start", seq, start); err != nil {
+			return sequenceRange{}, err
 		}
-		if j >= 0 {
-			sum += int(num2[j] - '0')
-			j--
+
+		if err := s.checkDigits("end", seq, end-1); err != nil {
+			return sequenceRange{}, err
 		}
+	}
 
-		if sum > 9 {
-			carry = true
-			sum -= 10
-		} else {
-			carry = false
+	return sequenceRange{spec: spec, seq: seq, start: start, end: end, modulus: modulus}, nil
+}
+
+// checkDigits checks the estimated size of the n-th term of seq against the digit limit,
+// attributing a violation to field.
+func (s *fibonacciService) checkDigits(field string, seq Sequence, n int) error {
+	if digits := sequenceDigits(seq, n); digits > s.NthDigitsLimit {
+		return domain.NewFieldError(field, fmt.Errorf("%w: term %d has %d digits, must not exceed %d", domain.ErrTooManyDigits, n, digits, s.NthDigitsLimit))
+	}
+
+	return nil
+}
+
+func (s *fibonacciService) GetNth(ctx context.Context, req domain.FibonacciNthRequest) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "GetNth", trace.WithAttributes(
+		attrSequence.String(sequenceName(req.Sequence)), attrN.Int(req.N), attrModulus.Int64(int64(req.Modulus))))
+	defer func() { endSpan(span, err) }()
+
+	seq, err := s.nthSequence(req)
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+
+	var res string
+	if s.table.holds(seq, req.Modulus, req.N) {
+		res = s.table.term(req.N)
+	} else if res, err = getNth(ctx, seq, req.N, req.Modulus); err != nil {
+		return "", err
+	}
+
+	span.SetAttributes(attrDigits.Int64(valuesDigits([]string{res})))
+
+	observe("FibonacciNth", uint64(max(req.N, -req.N)), start)
+
+	return res, nil
+}
+
+// nthSequence resolves the sequence of a single term request and checks that the term is defined
+// and, unless it is reduced modulo the modulus, not too large.
+func (s *fibonacciService) nthSequence(req domain.FibonacciNthRequest) (Sequence, error) {
+	seq, err := resolveSequence(req.Sequence)
+	if err != nil {
+		return nil, domain.NewFieldError("sequence", err)
+	}
+
+	if req.N < 0 && !invertible(seq) {
+		return nil, domain.NewFieldError("n", fmt.Errorf("%w: n %d", domain.ErrUndefinedIndex, req.N))
+	}
+
+	if req.Modulus == 0 {
+		if err := s.checkDigits("n", seq, req.N); err != nil {
+			return nil, err
+		}
+	}
+
+	return seq, nil
+}
+
+// getNth calculates a(n) of seq, reduced modulo modulus unless it is zero.
+// Fibonacci uses fast doubling, every other sequence matrix exponentiation.
+func getNth(ctx context.Context, seq Sequence, n int, modulus uint64) (string, error) {
+	switch {
+	case isFibonacci(seq) && modulus != 0:
+		value, _, err := fibPairModAt(ctx, n, modulus)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatUint(value, 10), nil
+
+	case isFibonacci(seq):
+		value, _, err := fibPair(ctx, n)
+		if err != nil {
+			return "", err
+		}
+
+		return value.Text(10), nil
+
+	case modulus != 0:
+		window, err := recurrenceWindowMod(ctx, seq, n, modulus)
+		if err != nil {
+			return "", err
+		}
+
+		return strconv.FormatUint(window[0], 10), nil
+
+	default:
+		window, err := recurrenceWindow(ctx, seq, n)
+		if err != nil {
+			return "", err
 		}
 
-		result = append(result, byte(sum)+'0')
+		return window[0].Text(10), nil
+	}
+}
+
+func (s *fibonacciService) GetPisanoPeriod(ctx context.Context, modulus uint64) (_ uint64, err error) {
+	ctx, span := tracer.Start(ctx, "GetPisanoPeriod", trace.WithAttributes(attrModulus.Int64(int64(modulus))))
+	defer func() { endSpan(span, err) }()
+
+	if modulus == 0 || modulus > maxPisanoModulus {
+		return 0, domain.NewFieldError("modulus", fmt.Errorf("%w: must be between 1 and %d", domain.ErrInvalidModulus, uint64(maxPisanoModulus)))
+	}
+
+	start := time.Now()
+
+	period, err := pisanoPeriod(ctx, modulus)
+	if err != nil {
+		return 0, err
+	}
+
+	observe("PisanoPeriod", modulus, start)
+
+	return period, nil
+}
+
+// processChunks divides the terms of the range into chunks and streams each chunk
+// together with the absolute index of its first value and a token to resume after it.
+// The chunk slice is reused between sends, so send must not retain it. Every chunk is traced, see sendChunk.
+func (s *fibonacciService) processChunks(ctx context.Context, r sequenceRange, chunkSize int, send func(domain.FibonacciChunk) error) error {
+	c, err := s.newChunkCursor(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	for number := 0; !c.done(); number++ {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		if err := sendChunk(ctx, c, number, chunkSize, send); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chunkCursor is the position of a stream in its range. It produces the chunks one at a time,
+// so that streams can be driven by the caller's pace rather than a loop over the whole range.
+type chunkCursor struct {
+	s        *fibonacciService
+	r        sequenceRange
+	state    sequenceState
+	position int      // Index of the next term
+	values   []string // Reused chunk array
+}
+
+func (s *fibonacciService) newChunkCursor(ctx context.Context, r sequenceRange) (*chunkCursor, error) {
+	state, err := s.newState(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkCursor{s: s, r: r, state: state, position: r.start}, nil
+}
+
+// done reports whether the whole range has been produced.
+func (c *chunkCursor) done() bool {
+	return c.position >= c.r.end
+}
+
+// next returns the following chunk of at most chunkSize terms. Its values are overwritten by the next call.
+func (c *chunkCursor) next(chunkSize int) domain.FibonacciChunk {
+	n := min(chunkSize, c.r.end-c.position)
+	if cap(c.values) < n {
+		c.values = make([]string, chunkSize)
+	}
+
+	values := c.values[:n]
+	for j := range values {
+		values[j] = c.state.text()
+		c.state.advance()
+	}
+
+	chunk := domain.FibonacciChunk{Index: c.position, Values: values}
+	c.position += n
+
+	if c.position < c.r.end {
+		chunk.ContinuationToken = c.s.tokens.encode(resumePoint{
+			spec:     c.r.spec,
+			position: c.position,
+			end:      c.r.end,
+			modulus:  c.r.modulus,
+			state:    c.state.save(nil),
+		})
+	}
+
+	return chunk
+}
+
+// seek moves the cursor to the absolute index, which must lie within the range.
+// Seeking to the end of the range completes it.
+func (c *chunkCursor) seek(ctx context.Context, index int) error {
+	if index < c.r.start || index > c.r.end {
+		return domain.NewFieldError("seek", fmt.Errorf("%w: index must be between %d and %d", domain.ErrInvalidRange, c.r.start, c.r.end))
 	}
 
-	for k, l := 0, len(result)-1; k < l; k, l = k+1, l-1 {
-		result[k], result[l] = result[l], result[k]
+	if index == c.position {
+		return nil
 	}
 
-	return string(result)
+	state, err := c.s.stateAt(ctx, c.r, index)
+	if err != nil {
+		return err
+	}
+
+	c.state, c.position = state, index
+
+	return nil
+}
+
+// observe records a successful calculation of method, started at start, in the class of size, see metrics.SizeClass.
+func observe(method string, size uint64, start time.Time) {
+	class := metrics.SizeClass(size)
+
+	metrics.CalculationDuration.WithLabelValues(method, class).Observe(time.Since(start).Seconds())
+	metrics.CalculationsTotal.WithLabelValues(method, class).Inc()
+}
+
+// contextError reports whether ctx is done, translating cancellation into domain.ErrContextCanceled.
+func contextError(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return domain.ErrContextCanceled
+		}
+
+		return ctx.Err()
+	default:
+		return nil
+	}
 }
@@ -0,0 +1,187 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/service"
+)
+
+var benchSizes = []int{100, 1000, 10000}
+
+func BenchmarkGetFibonacci(b *testing.B) {
+	ctx := context.Background()
+	s := service.NewService(100, 5, 100000, 100000, 1000000)
+
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("legacy/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				legacyFibonacci(n)
+			}
+		})
+
+		b.Run(fmt.Sprintf("limbs/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: n}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetFibonacciStream(b *testing.B) {
+	ctx := context.Background()
+	s := service.NewService(100, 5, 100000, 100000, 1000000)
+	discard := func(domain.FibonacciChunk) error { return nil }
+
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("legacy/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				legacyChunks(n, 100, func([]string, int) error { return nil })
+			}
+		})
+
+		b.Run(fmt.Sprintf("limbs/n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
+					End:       n,
+					ChunkSize: 100,
+					SendFunc:  discard,
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGetFibonacciBatch(b *testing.B) {
+	ctx := context.Background()
+	s := service.NewService(100, 5, 100000, 100000, 1000000, service.WithBatchLimits(1000, 100000, 0))
+
+	// Many small requests for prefixes of different lengths, like an analytics pipeline would send.
+	items := make([]domain.BatchItem, 1000)
+	for i := range items {
+		items[i] = domain.BatchItem{Range: &domain.FibonacciRequest{End: (i*7919)%5000 + 1}}
+	}
+
+	b.Run("single calls", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, item := range items {
+				if _, err := s.GetFibonacci(ctx, *item.Range); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.GetFibonacciBatch(ctx, items); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestLegacyMatchesService(t *testing.T) {
+	s := service.NewService(100, 5, 1000, 1000, 1000)
+
+	res, err := s.GetFibonacci(context.Background(), domain.FibonacciRequest{End: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, v := range legacyFibonacci(1000) {
+		if res[i] != v {
+			t.Fatalf("term %d: got %s, want %s", i, res[i], v)
+		}
+	}
+}
+
+// legacyFibonacci is the decimal-string implementation the service used before
+// the big-integer engine. It is kept here as the baseline for benchmarks.
+func legacyFibonacci(n int) []string {
+	seq := make([]string, n)
+
+	if n > 0 {
+		seq[0] = "0"
+	}
+
+	if n > 1 {
+		seq[1] = "1"
+	}
+
+	for i := 2; i < n; i++ {
+		seq[i] = legacyAddStrings(seq[i-1], seq[i-2])
+	}
+
+	return seq
+}
+
+func legacyChunks(n, chunkSize int, send func([]string, int) error) {
+	prev1, prev2 := "0", "1"
+	chunk := make([]string, chunkSize)
+
+	for i := 0; i < n; i += chunkSize {
+		end := i + chunkSize
+		if end > n {
+			end = n
+		}
+
+		for j := 0; j < end-i; j++ {
+			chunk[j] = prev1
+			prev1, prev2 = prev2, legacyAddStrings(prev1, prev2)
+		}
+
+		_ = send(chunk[:end-i], i)
+	}
+}
+
+func legacyAddStrings(num1, num2 string) string {
+	var result []byte
+	carry := false
+	i, j := len(num1)-1, len(num2)-1
+
+	for i >= 0 || j >= 0 || carry {
+		sum := 0
+
+		if carry {
+			sum++
+		}
+
+		if i >= 0 {
+			sum += int(num1[i] - '0')
+			i--
+		}
+		if j >= 0 {
+			sum += int(num2[j] - '0')
+			j--
+		}
+
+		if sum > 9 {
+			carry = true
+			sum -= 10
+		} else {
+			carry = false
+		}
+
+		result = append(result, byte(sum)+'0')
+	}
+
+	for k, l := 0, len(result)-1; k < l; k, l = k+1, l-1 {
+		result[k], result[l] = result[l], result[k]
+	}
+
+	return string(result)
+}
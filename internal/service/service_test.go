@@ -3,6 +3,10 @@ package service_test
 import (
 	"context"
 	"errors"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
 	"testing"
 
 	"fibonacci/internal/domain"
@@ -12,56 +16,110 @@ import (
 )
 
 func TestGetFibonacci(t *testing.T) {
-	s := service.NewService(10, 2, 100, 200)
+	s := service.NewService(10, 2, 100, 200, 100)
 
 	t.Run("valid input", func(t *testing.T) {
 		ctx := context.Background()
-		result, err := s.GetFibonacci(ctx, 10)
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: 10})
 
 		assert.NoError(t, err)
 		assert.Equal(t, []string{"0", "1", "1", "2", "3", "5", "8", "13", "21", "34"}, result)
 	})
 
+	t.Run("range", func(t *testing.T) {
+		ctx := context.Background()
+		prefix, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: 100})
+		assert.NoError(t, err)
+
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: 90, End: 100})
+
+		assert.NoError(t, err)
+		assert.Equal(t, prefix[90:], result)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: 50, End: 50})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
 	t.Run("negative n", func(t *testing.T) {
 		ctx := context.Background()
-		_, err := s.GetFibonacci(ctx, -5)
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: -5})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRange)
+	})
+
+	t.Run("modulus", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: 5, End: 15, Modulus: 7})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"5", "1", "6", "0", "6", "6", "5", "4", "2", "6"}, result)
+	})
+
+	t.Run("modulus lifts digit limit", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: 1000000, End: 1000010, Modulus: 10})
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 10)
+	})
+
+	t.Run("end before start", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: 10, End: 5})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRange)
+	})
+
+	t.Run("range term has too many digits", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: 1000, End: 1010})
 
-		assert.ErrorIs(t, err, domain.ErrNegativeN)
+		assert.ErrorIs(t, err, domain.ErrTooManyDigits)
 	})
 
 	t.Run("n exceeds limit", func(t *testing.T) {
 		ctx := context.Background()
-		_, err := s.GetFibonacci(ctx, 150)
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: 150})
 
 		assert.ErrorIs(t, err, domain.ErrTooLargeN)
+
+		var fieldErr *domain.FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "n", fieldErr.Field)
+		assert.EqualError(t, fieldErr.Err, "to large n: must not exceed 100")
 	})
 
 	t.Run("context canceled", func(t *testing.T) {
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		_, err := s.GetFibonacci(ctx, 10)
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: 10})
 
 		assert.ErrorIs(t, err, domain.ErrContextCanceled)
 	})
 }
 
 func TestGetFibonacciStream(t *testing.T) {
-	s := service.NewService(10, 2, 50, 100)
+	s := service.NewService(10, 2, 50, 100, 100)
 
 	t.Run("valid input", func(t *testing.T) {
 		ctx := context.Background()
 		chunks := [][]string{}
-		sendFunc := func(values []string, index int) error {
-			temp := make([]string, len(values))
-			copy(temp, values)
+		sendFunc := func(chunk domain.FibonacciChunk) error {
+			temp := make([]string, len(chunk.Values))
+			copy(temp, chunk.Values)
 			chunks = append(chunks, temp)
 
 			return nil
 		}
 
 		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-			N:         10,
+			End:       10,
 			ChunkSize: 4,
 			SendFunc:  sendFunc,
 		})
@@ -74,12 +132,60 @@ func TestGetFibonacciStream(t *testing.T) {
 		}, chunks)
 	})
 
+	t.Run("range", func(t *testing.T) {
+		ctx := context.Background()
+		indexes := []int{}
+		chunks := [][]string{}
+		sendFunc := func(chunk domain.FibonacciChunk) error {
+			temp := make([]string, len(chunk.Values))
+			copy(temp, chunk.Values)
+			chunks = append(chunks, temp)
+			indexes = append(indexes, chunk.Index)
+
+			return nil
+		}
+
+		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
+			Start:     10,
+			End:       20,
+			ChunkSize: 4,
+			SendFunc:  sendFunc,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10, 14, 18}, indexes)
+		assert.Equal(t, [][]string{
+			{"55", "89", "144", "233"},
+			{"377", "610", "987", "1597"},
+			{"2584", "4181"},
+		}, chunks)
+	})
+
+	t.Run("modulus", func(t *testing.T) {
+		ctx := context.Background()
+		values := []string{}
+		sendFunc := func(chunk domain.FibonacciChunk) error {
+			values = append(values, chunk.Values...)
+			return nil
+		}
+
+		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
+			End:       20,
+			Modulus:   2,
+			ChunkSize: 4,
+			SendFunc:  sendFunc,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Split("01101101101101101101", ""), values)
+	})
+
 	t.Run("n exceeds limit", func(t *testing.T) {
 		ctx := context.Background()
 		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-			N:         150,
+			End:       150,
 			ChunkSize: 20,
-			SendFunc:  func([]string, int) error { return nil },
+			SendFunc:  func(domain.FibonacciChunk) error { return nil },
 		})
 
 		assert.ErrorIs(t, err, domain.ErrTooLargeN)
@@ -88,20 +194,24 @@ func TestGetFibonacciStream(t *testing.T) {
 	t.Run("chunk size too large", func(t *testing.T) {
 		ctx := context.Background()
 		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-			N:         10,
+			End:       10,
 			ChunkSize: 20,
-			SendFunc:  func([]string, int) error { return nil },
+			SendFunc:  func(domain.FibonacciChunk) error { return nil },
 		})
 
 		assert.ErrorIs(t, err, domain.ErrInvalidChunkSize)
+
+		var fieldErr *domain.FieldError
+		assert.ErrorAs(t, err, &fieldErr)
+		assert.Equal(t, "chunk_size", fieldErr.Field)
 	})
 
 	t.Run("chunk size too small", func(t *testing.T) {
 		ctx := context.Background()
 		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-			N:         10,
+			End:       10,
 			ChunkSize: 1,
-			SendFunc:  func([]string, int) error { return nil },
+			SendFunc:  func(domain.FibonacciChunk) error { return nil },
 		})
 
 		assert.ErrorIs(t, err, domain.ErrInvalidChunkSize)
@@ -112,9 +222,9 @@ func TestGetFibonacciStream(t *testing.T) {
 		cancel()
 
 		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-			N:         10,
+			End:       10,
 			ChunkSize: 4,
-			SendFunc:  func([]string, int) error { return nil },
+			SendFunc:  func(domain.FibonacciChunk) error { return nil },
 		})
 
 		assert.ErrorIs(t, err, domain.ErrContextCanceled)
@@ -123,9 +233,9 @@ func TestGetFibonacciStream(t *testing.T) {
 	t.Run("send function error", func(t *testing.T) {
 		ctx := context.Background()
 		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
-			N:         10,
+			End:       10,
 			ChunkSize: 4,
-			SendFunc: func([]string, int) error {
+			SendFunc: func(domain.FibonacciChunk) error {
 				return errors.New("send error")
 			},
 		})
@@ -134,3 +244,735 @@ func TestGetFibonacciStream(t *testing.T) {
 		assert.Contains(t, err.Error(), "send error")
 	})
 }
+
+func TestGetNth(t *testing.T) {
+	s := service.NewService(10, 2, 100, 200, 100)
+
+	t.Run("valid input", func(t *testing.T) {
+		ctx := context.Background()
+
+		for n, want := range map[int]string{
+			0:   "0",
+			1:   "1",
+			2:   "1",
+			10:  "55",
+			100: "354224848179261915075",
+			300: "222232244629420445529739893461909967206666939096499764990979600",
+		} {
+			result, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: n})
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, result, "F(%d)", n)
+		}
+	})
+
+	t.Run("matches sequence", func(t *testing.T) {
+		ctx := context.Background()
+		seq, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: 100})
+		assert.NoError(t, err)
+
+		for n, want := range seq {
+			result, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: n})
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, result, "F(%d)", n)
+		}
+	})
+
+	t.Run("modulus", func(t *testing.T) {
+		ctx := context.Background()
+		m := uint64(1_000_000_007)
+
+		full, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: 300})
+		assert.NoError(t, err)
+
+		value, ok := new(big.Int).SetString(full, 10)
+		assert.True(t, ok)
+
+		result, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: 300, Modulus: m})
+		assert.NoError(t, err)
+		assert.Equal(t, value.Mod(value, new(big.Int).SetUint64(m)).String(), result)
+	})
+
+	t.Run("modulus with huge n", func(t *testing.T) {
+		ctx := context.Background()
+
+		// π(10^9) = 1.5 * 10^9, so F(k * π + 7) ≡ F(7) (mod 10^9).
+		result, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: 3_000_000_000_000_007, Modulus: 1_000_000_000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "13", result)
+	})
+
+	t.Run("negative n", func(t *testing.T) {
+		ctx := context.Background()
+
+		for n, want := range map[int]string{
+			-1:   "1",
+			-2:   "-1",
+			-5:   "5",
+			-10:  "-55",
+			-100: "-354224848179261915075",
+		} {
+			result, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: n})
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, result, "F(%d)", n)
+		}
+	})
+
+	t.Run("too many digits", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: 1000})
+
+		assert.ErrorIs(t, err, domain.ErrTooManyDigits)
+	})
+
+	t.Run("most negative n", func(t *testing.T) {
+		ctx := context.Background()
+
+		// -math.MinInt64 overflows, which must not make the term look small.
+		_, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: math.MinInt64})
+		assert.ErrorIs(t, err, domain.ErrTooManyDigits)
+
+		_, err = s.GetNth(ctx, domain.FibonacciNthRequest{
+			Sequence: domain.SequenceSpec{Name: service.SequenceTribonacci},
+			N:        math.MinInt64,
+		})
+		assert.ErrorIs(t, err, domain.ErrTooManyDigits)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: 10})
+
+		assert.ErrorIs(t, err, domain.ErrContextCanceled)
+	})
+}
+
+func TestGetPisanoPeriod(t *testing.T) {
+	s := service.NewService(10, 2, 100, 200, 100)
+
+	t.Run("known periods", func(t *testing.T) {
+		ctx := context.Background()
+
+		for m, want := range map[uint64]uint64{
+			1:             1,
+			2:             3,
+			3:             8,
+			5:             20,
+			7:             16,
+			10:            60,
+			11:            10,
+			25:            100,
+			1000:          1500,
+			1_000_000_000: 1_500_000_000,
+			1_000_000_007: 2_000_000_016,
+		} {
+			period, err := s.GetPisanoPeriod(ctx, m)
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, period, "π(%d)", m)
+		}
+	})
+
+	t.Run("matches brute force", func(t *testing.T) {
+		ctx := context.Background()
+
+		for m := uint64(2); m <= 500; m++ {
+			var want uint64
+			for a, b := uint64(0), uint64(1); ; {
+				a, b = b, (a+b)%m
+				want++
+
+				if a == 0 && b == 1 {
+					break
+				}
+			}
+
+			period, err := s.GetPisanoPeriod(ctx, m)
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, period, "π(%d)", m)
+		}
+	})
+
+	t.Run("large composite modulus", func(t *testing.T) {
+		ctx := context.Background()
+		m := uint64(1_000_000_007) * 998_244_353
+
+		period, err := s.GetPisanoPeriod(ctx, m)
+		assert.NoError(t, err)
+
+		result, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: int(period), Modulus: m})
+		assert.NoError(t, err)
+		assert.Equal(t, "0", result)
+
+		result, err = s.GetNth(ctx, domain.FibonacciNthRequest{N: int(period + 1), Modulus: m})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", result)
+	})
+
+	t.Run("zero modulus", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := s.GetPisanoPeriod(ctx, 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidModulus)
+	})
+}
+
+func TestSequences(t *testing.T) {
+	s := service.NewService(10, 2, 100, 200, 100)
+
+	t.Run("built-in sequences", func(t *testing.T) {
+		ctx := context.Background()
+
+		for spec, want := range map[string][]string{
+			service.SequenceLucas:      {"2", "1", "3", "4", "7", "11", "18", "29", "47", "76"},
+			service.SequencePell:       {"0", "1", "2", "5", "12", "29", "70", "169", "408", "985"},
+			service.SequenceTribonacci: {"0", "0", "1", "1", "2", "4", "7", "13", "24", "44"},
+		} {
+			result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{
+				Sequence: domain.SequenceSpec{Name: spec},
+				End:      10,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, want, result, spec)
+		}
+	})
+
+	t.Run("k-bonacci", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: service.SequenceKBonacci, K: 4},
+			End:      10,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"0", "0", "0", "1", "1", "2", "4", "8", "15", "29"}, result)
+	})
+
+	t.Run("custom recurrence with negative terms", func(t *testing.T) {
+		ctx := context.Background()
+
+		// a(n) = a(n-1) - a(n-2) cycles through 1, 1, 0, -1, -1, 0.
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: service.SequenceCustom, Seeds: []string{"1", "1"}, Coefficients: []int64{1, -1}},
+			End:      8,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "1", "0", "-1", "-1", "0", "1", "1"}, result)
+	})
+
+	t.Run("custom recurrence with large coefficients", func(t *testing.T) {
+		ctx := context.Background()
+
+		// a(n) = 10^18 * a(n-1) walks through the limb boundaries.
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: service.SequenceCustom, Seeds: []string{"-7"}, Coefficients: []int64{1_000_000_000_000_000_000}},
+			End:      3,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"-7", "-7" + strings.Repeat("0", 18), "-7" + strings.Repeat("0", 36)}, result)
+	})
+
+	t.Run("range matches prefix", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, spec := range []domain.SequenceSpec{
+			{Name: service.SequencePell},
+			{Name: service.SequenceKBonacci, K: 5},
+			{Name: service.SequenceCustom, Seeds: []string{"3", "-2", "5"}, Coefficients: []int64{-2, 7, 3}},
+		} {
+			prefix, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, End: 60})
+			assert.NoError(t, err)
+
+			result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, Start: 37, End: 60})
+			assert.NoError(t, err)
+			assert.Equal(t, prefix[37:], result)
+
+			nth, err := s.GetNth(ctx, domain.FibonacciNthRequest{Sequence: spec, N: 59})
+			assert.NoError(t, err)
+			assert.Equal(t, prefix[59], nth)
+
+			modulus := uint64(1_000_003)
+			modular, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, Start: 37, End: 60, Modulus: modulus})
+			assert.NoError(t, err)
+
+			for i, v := range prefix[37:] {
+				value, _ := new(big.Int).SetString(v, 10)
+				assert.Equal(t, value.Mod(value, new(big.Int).SetUint64(modulus)).String(), modular[i])
+			}
+		}
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ctx := context.Background()
+		values := []string{}
+		sendFunc := func(chunk domain.FibonacciChunk) error {
+			values = append(values, chunk.Values...)
+			return nil
+		}
+
+		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
+			Sequence:  domain.SequenceSpec{Name: service.SequenceLucas},
+			Start:     5,
+			End:       10,
+			ChunkSize: 2,
+			SendFunc:  sendFunc,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"11", "18", "29", "47", "76"}, values)
+	})
+
+	t.Run("registered sequence", func(t *testing.T) {
+		ctx := context.Background()
+		seq, err := service.NewRecurrence([]*big.Int{big.NewInt(1)}, []int64{3})
+		assert.NoError(t, err)
+
+		assert.NoError(t, service.RegisterSequence("powers-of-three", seq))
+		assert.ErrorIs(t, service.RegisterSequence("powers-of-three", seq), domain.ErrInvalidSequence)
+
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{
+			Sequence: domain.SequenceSpec{Name: "powers-of-three"},
+			End:      5,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"1", "3", "9", "27", "81"}, result)
+	})
+
+	t.Run("digit limit uses sequence growth", func(t *testing.T) {
+		ctx := context.Background()
+
+		// Pell numbers grow by about 0.38 digits per term, so a(300) has about 115 digits.
+		_, err := s.GetNth(ctx, domain.FibonacciNthRequest{Sequence: domain.SequenceSpec{Name: service.SequencePell}, N: 300})
+
+		assert.ErrorIs(t, err, domain.ErrTooManyDigits)
+	})
+
+	t.Run("invalid sequences", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, spec := range []domain.SequenceSpec{
+			{Name: "unknown"},
+			{Name: service.SequenceKBonacci, K: 1},
+			{Name: service.SequenceCustom, Seeds: []string{"1"}, Coefficients: []int64{1, 1}},
+			{Name: service.SequenceCustom, Seeds: []string{"x", "1"}, Coefficients: []int64{1, 1}},
+			{Name: service.SequenceCustom},
+		} {
+			_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, End: 5})
+
+			assert.ErrorIs(t, err, domain.ErrInvalidSequence, spec)
+		}
+	})
+}
+
+func TestNegativeIndices(t *testing.T) {
+	s := service.NewService(10, 2, 100, 200, 100)
+
+	t.Run("range", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: -5, End: 5})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"5", "-3", "2", "-1", "1", "0", "1", "1", "2", "3"}, result)
+	})
+
+	t.Run("stream crosses zero", func(t *testing.T) {
+		ctx := context.Background()
+		indexes := []int{}
+		values := []string{}
+		sendFunc := func(chunk domain.FibonacciChunk) error {
+			indexes = append(indexes, chunk.Index)
+			values = append(values, chunk.Values...)
+			return nil
+		}
+
+		err := s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
+			Start:     -12,
+			End:       2,
+			ChunkSize: 5,
+			SendFunc:  sendFunc,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{-12, -7, -2}, indexes)
+		assert.Equal(t, []string{
+			"-144", "89", "-55", "34", "-21",
+			"13", "-8", "5", "-3", "2",
+			"-1", "1", "0", "1",
+		}, values)
+	})
+
+	t.Run("matches F(-n) = (-1)^(n+1) F(n)", func(t *testing.T) {
+		ctx := context.Background()
+		positive, err := s.GetFibonacci(ctx, domain.FibonacciRequest{End: 100})
+		assert.NoError(t, err)
+
+		negative, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: -99, End: 1})
+		assert.NoError(t, err)
+
+		for n := 1; n < 100; n++ {
+			want := positive[n]
+			if n%2 == 0 {
+				want = "-" + want
+			}
+
+			assert.Equal(t, want, negative[99-n], "F(-%d)", n)
+		}
+	})
+
+	t.Run("modulus", func(t *testing.T) {
+		ctx := context.Background()
+		result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: -5, End: 1, Modulus: 7})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"5", "4", "2", "6", "1", "0"}, result)
+
+		nth, err := s.GetNth(ctx, domain.FibonacciNthRequest{N: -3_000_000_000_000_008, Modulus: 1_000_000_000})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "999999979", nth) // F(-8) = -21
+	})
+
+	t.Run("recurrences run backwards", func(t *testing.T) {
+		ctx := context.Background()
+
+		for _, spec := range []domain.SequenceSpec{
+			{Name: service.SequenceLucas},
+			{Name: service.SequenceTribonacci},
+			{Name: service.SequenceCustom, Seeds: []string{"3", "-2", "5"}, Coefficients: []int64{-2, 7, -1}},
+		} {
+			result, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, Start: -20, End: 20})
+			assert.NoError(t, err)
+
+			prefix, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, End: 20})
+			assert.NoError(t, err)
+			assert.Equal(t, prefix, result[20:])
+
+			nth, err := s.GetNth(ctx, domain.FibonacciNthRequest{Sequence: spec, N: -20})
+			assert.NoError(t, err)
+			assert.Equal(t, result[0], nth)
+
+			modulus := uint64(1_000_003)
+			modular, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, Start: -20, End: 0, Modulus: modulus})
+			assert.NoError(t, err)
+
+			for i, v := range result[:20] {
+				value, _ := new(big.Int).SetString(v, 10)
+				assert.Equal(t, value.Mod(value, new(big.Int).SetUint64(modulus)).String(), modular[i])
+			}
+		}
+
+		lucas, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: domain.SequenceSpec{Name: service.SequenceLucas}, Start: -5, End: 0})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"-11", "7", "-4", "3", "-1"}, lucas)
+	})
+
+	t.Run("undefined index", func(t *testing.T) {
+		ctx := context.Background()
+
+		// a(n) = a(n-1) + 2*a(n-2) cannot be run backwards in the integers.
+		spec := domain.SequenceSpec{Name: service.SequenceCustom, Seeds: []string{"0", "1"}, Coefficients: []int64{1, 2}}
+
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Sequence: spec, Start: -1, End: 5})
+		assert.ErrorIs(t, err, domain.ErrUndefinedIndex)
+
+		_, err = s.GetNth(ctx, domain.FibonacciNthRequest{Sequence: spec, N: -1})
+		assert.ErrorIs(t, err, domain.ErrUndefinedIndex)
+	})
+
+	t.Run("digit limit applies to negative start", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := s.GetFibonacci(ctx, domain.FibonacciRequest{Start: -1000, End: -990})
+
+		assert.ErrorIs(t, err, domain.ErrTooManyDigits)
+	})
+}
+
+func TestResumeToken(t *testing.T) {
+	s := service.NewService(10, 2, 50, 100, 100)
+
+	// stream collects the values and tokens of every chunk.
+	stream := func(s service.Service, req domain.FibonacciStreamRequest) ([]string, []string, error) {
+		values, tokens := []string{}, []string{}
+		req.SendFunc = func(chunk domain.FibonacciChunk) error {
+			values = append(values, chunk.Values...)
+			tokens = append(tokens, chunk.ContinuationToken)
+
+			return nil
+		}
+
+		return values, tokens, s.GetFibonacciStream(context.Background(), req)
+	}
+
+	t.Run("resumes where the stream stopped", func(t *testing.T) {
+		for _, req := range []domain.FibonacciStreamRequest{
+			{End: 20, ChunkSize: 3},
+			{Start: -15, End: 5, ChunkSize: 4},
+			{End: 30, Modulus: 7, ChunkSize: 5},
+			{Start: -10, End: 10, Modulus: 1_000_003, ChunkSize: 3},
+			{Sequence: domain.SequenceSpec{Name: service.SequenceTribonacci}, Start: -6, End: 14, ChunkSize: 4},
+			{Sequence: domain.SequenceSpec{Name: service.SequenceLucas}, End: 20, Modulus: 11, ChunkSize: 6},
+			{
+				Sequence:  domain.SequenceSpec{Name: service.SequenceCustom, Seeds: []string{"3", "-2", "5"}, Coefficients: []int64{-2, 7, -1}},
+				Start:     -5,
+				End:       15,
+				ChunkSize: 4,
+			},
+		} {
+			values, tokens, err := stream(s, req)
+			assert.NoError(t, err)
+			assert.Empty(t, tokens[len(tokens)-1], "last chunk has no token")
+
+			for i, token := range tokens[:len(tokens)-1] {
+				assert.NotEmpty(t, token)
+
+				resumed, _, err := stream(s, domain.FibonacciStreamRequest{ChunkSize: req.ChunkSize, ResumeToken: token})
+				assert.NoError(t, err)
+				assert.Equal(t, values[(i+1)*req.ChunkSize:], resumed, "%+v resumed after chunk %d", req, i)
+			}
+		}
+	})
+
+	t.Run("chunk size may change", func(t *testing.T) {
+		values, tokens, err := stream(s, domain.FibonacciStreamRequest{End: 20, ChunkSize: 5})
+		assert.NoError(t, err)
+
+		resumed, resumedTokens, err := stream(s, domain.FibonacciStreamRequest{ChunkSize: 2, ResumeToken: tokens[0]})
+		assert.NoError(t, err)
+		assert.Equal(t, values[5:], resumed)
+		assert.Len(t, resumedTokens, 8)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, tokens, err := stream(s, domain.FibonacciStreamRequest{End: 20, ChunkSize: 5})
+		assert.NoError(t, err)
+
+		tampered := []byte(tokens[0])
+		tampered[len(tampered)/2] ^= 1
+
+		other := service.NewService(10, 2, 50, 100, 100, service.WithTokenSecret([]byte("another secret")))
+
+		for name, tc := range map[string]struct {
+			service service.Service
+			token   string
+		}{
+			"garbage":        {s, "not a token"},
+			"tampered":       {s, string(tampered)},
+			"truncated":      {s, tokens[0][:len(tokens[0])-4]},
+			"another secret": {other, tokens[0]},
+		} {
+			_, _, err := stream(tc.service, domain.FibonacciStreamRequest{ChunkSize: 5, ResumeToken: tc.token})
+			assert.ErrorIs(t, err, domain.ErrInvalidToken, name)
+
+			var fieldErr *domain.FieldError
+			assert.ErrorAs(t, err, &fieldErr, name)
+			assert.Equal(t, "resume_token", fieldErr.Field, name)
+		}
+	})
+
+	t.Run("current limits of the caller", func(t *testing.T) {
+		secret := []byte("shared secret")
+		s := service.NewService(10, 2, 50, 100, 100, service.WithTokenSecret(secret))
+
+		_, tokens, err := stream(s, domain.FibonacciStreamRequest{End: 100, ChunkSize: 10})
+		assert.NoError(t, err)
+
+		resume := func(s service.Service, ctx context.Context) error {
+			return s.GetFibonacciStream(ctx, domain.FibonacciStreamRequest{
+				ChunkSize:   10,
+				ResumeToken: tokens[0],
+				SendFunc:    func(domain.FibonacciChunk) error { return nil },
+			})
+		}
+
+		// The remaining 90 terms exceed a lowered limit and the limit of a client allowed fewer terms.
+		lowered := service.NewService(10, 2, 50, 50, 100, service.WithTokenSecret(secret))
+		assert.ErrorIs(t, resume(lowered, context.Background()), domain.ErrTooLargeN)
+
+		limited := service.WithClientLimits(context.Background(), service.ClientLimits{StreamNLimit: 80})
+		assert.ErrorIs(t, resume(s, limited), domain.ErrTooLargeN)
+
+		assert.NoError(t, resume(s, service.WithClientLimits(context.Background(), service.ClientLimits{StreamNLimit: 90})))
+	})
+
+	t.Run("shared secret", func(t *testing.T) {
+		secret := []byte("shared secret")
+		a := service.NewService(10, 2, 50, 100, 100, service.WithTokenSecret(secret))
+		b := service.NewService(10, 2, 50, 100, 100, service.WithTokenSecret(secret))
+
+		values, tokens, err := stream(a, domain.FibonacciStreamRequest{End: 20, ChunkSize: 5})
+		assert.NoError(t, err)
+
+		resumed, _, err := stream(b, domain.FibonacciStreamRequest{ChunkSize: 5, ResumeToken: tokens[1]})
+		assert.NoError(t, err)
+		assert.Equal(t, values[10:], resumed)
+	})
+}
+
+func TestGetFibonacciFlow(t *testing.T) {
+	s := service.NewService(10, 2, 50, 100, 100)
+
+	// flow runs a flow whose commands are all pending from the start, then closed,
+	// and collects the indexes and values of the chunks sent.
+	flow := func(req domain.FibonacciStreamRequest, commands ...domain.FlowCommand) ([]int, [][]string, error) {
+		ch := make(chan domain.FlowCommand, len(commands))
+		for _, cmd := range commands {
+			ch <- cmd
+		}
+		close(ch)
+
+		indexes, chunks := []int{}, [][]string{}
+		req.SendFunc = func(chunk domain.FibonacciChunk) error {
+			indexes = append(indexes, chunk.Index)
+			chunks = append(chunks, slices.Clone(chunk.Values))
+
+			return nil
+		}
+
+		err := s.GetFibonacciFlow(context.Background(), domain.FibonacciFlowRequest{Stream: req, Commands: ch})
+
+		return indexes, chunks, err
+	}
+
+	next := func(n int) domain.FlowCommand { return domain.FlowCommand{Kind: domain.FlowNext, Value: n} }
+
+	t.Run("sends only requested chunks", func(t *testing.T) {
+		indexes, chunks, err := flow(domain.FibonacciStreamRequest{End: 20, ChunkSize: 3}, next(1), next(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 3}, indexes)
+		assert.Equal(t, [][]string{{"0", "1", "1"}, {"2", "3", "5"}}, chunks)
+	})
+
+	t.Run("nothing requested", func(t *testing.T) {
+		indexes, _, err := flow(domain.FibonacciStreamRequest{End: 20, ChunkSize: 3})
+
+		assert.NoError(t, err)
+		assert.Empty(t, indexes)
+	})
+
+	t.Run("ends with the range", func(t *testing.T) {
+		indexes, chunks, err := flow(domain.FibonacciStreamRequest{Start: 5, End: 10, ChunkSize: 3}, next(10))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{5, 8}, indexes)
+		assert.Equal(t, [][]string{{"5", "8", "13"}, {"21", "34"}}, chunks)
+	})
+
+	t.Run("pause drops requested chunks", func(t *testing.T) {
+		indexes, _, err := flow(domain.FibonacciStreamRequest{End: 20, ChunkSize: 3},
+			next(5), domain.FlowCommand{Kind: domain.FlowPause}, next(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0}, indexes)
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		indexes, chunks, err := flow(domain.FibonacciStreamRequest{Start: -5, End: 20, Modulus: 7, ChunkSize: 2},
+			domain.FlowCommand{Kind: domain.FlowSeek, Value: 10}, next(1))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{10}, indexes)
+		assert.Equal(t, [][]string{{"6", "5"}}, chunks) // 55, 89 mod 7
+	})
+
+	t.Run("commands interleave with chunks", func(t *testing.T) {
+		commands := make(chan domain.FlowCommand)
+		chunks := make(chan domain.FibonacciChunk, 10)
+		errc := make(chan error, 1)
+
+		go func() {
+			errc <- s.GetFibonacciFlow(context.Background(), domain.FibonacciFlowRequest{
+				Stream: domain.FibonacciStreamRequest{
+					Sequence:  domain.SequenceSpec{Name: service.SequenceLucas},
+					End:       30,
+					ChunkSize: 2,
+					SendFunc: func(chunk domain.FibonacciChunk) error {
+						chunk.Values = slices.Clone(chunk.Values)
+						chunks <- chunk
+
+						return nil
+					},
+				},
+				Commands: commands,
+			})
+		}()
+
+		commands <- next(1)
+		chunk := <-chunks
+		assert.Equal(t, 0, chunk.Index)
+		assert.Equal(t, []string{"2", "1"}, chunk.Values)
+
+		commands <- domain.FlowCommand{Kind: domain.FlowChunkSize, Value: 4}
+		commands <- next(1)
+		chunk = <-chunks
+		assert.Equal(t, 2, chunk.Index)
+		assert.Equal(t, []string{"3", "4", "7", "11"}, chunk.Values)
+
+		commands <- domain.FlowCommand{Kind: domain.FlowSeek, Value: 1}
+		commands <- next(1)
+		chunk = <-chunks
+		assert.Equal(t, 1, chunk.Index)
+		assert.Equal(t, []string{"1", "3", "4", "7"}, chunk.Values)
+
+		close(commands)
+		assert.NoError(t, <-errc)
+		assert.Empty(t, chunks)
+
+		// The token continues after the last chunk, even after seeking back.
+		_, resumed, err := flow(domain.FibonacciStreamRequest{ChunkSize: 2, ResumeToken: chunk.ContinuationToken}, next(1))
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"11", "18"}}, resumed)
+	})
+
+	t.Run("invalid commands", func(t *testing.T) {
+		for _, tc := range []struct {
+			cmd   domain.FlowCommand
+			field string
+			err   error
+		}{
+			{next(0), "next", domain.ErrInvalidCommand},
+			{domain.FlowCommand{Kind: domain.FlowChunkSize, Value: 11}, "chunk_size", domain.ErrInvalidChunkSize},
+			{domain.FlowCommand{Kind: domain.FlowSeek, Value: -1}, "seek", domain.ErrInvalidRange},
+			{domain.FlowCommand{Kind: domain.FlowSeek, Value: 21}, "seek", domain.ErrInvalidRange},
+			{domain.FlowCommand{}, "", domain.ErrInvalidCommand},
+		} {
+			_, _, err := flow(domain.FibonacciStreamRequest{End: 20, ChunkSize: 3}, tc.cmd)
+			assert.ErrorIs(t, err, tc.err)
+
+			if tc.field != "" {
+				var fieldErr *domain.FieldError
+				assert.ErrorAs(t, err, &fieldErr)
+				assert.Equal(t, tc.field, fieldErr.Field)
+			}
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		_, _, err := flow(domain.FibonacciStreamRequest{End: 20, ChunkSize: 1}, next(1))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidChunkSize)
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := s.GetFibonacciFlow(ctx, domain.FibonacciFlowRequest{
+			Stream:   domain.FibonacciStreamRequest{End: 20, ChunkSize: 3, SendFunc: func(domain.FibonacciChunk) error { return nil }},
+			Commands: make(chan domain.FlowCommand),
+		})
+
+		assert.ErrorIs(t, err, domain.ErrContextCanceled)
+	})
+}
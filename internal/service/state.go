@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+)
+
+// sequenceState produces consecutive terms of a sequence.
+type sequenceState interface {
+	// text returns the decimal representation of the current term.
+	text() string
+
+	// advance moves to the next term.
+	advance()
+
+	// save appends the terms needed to restore the state at its current position to buf.
+	save(buf []byte) []byte
+}
+
+// newStateAt returns a state positioned at a(start) of seq, reduced modulo modulus unless it is zero.
+// Fibonacci gets the dedicated two-term states, every other sequence the generic recurrence.
+func newStateAt(ctx context.Context, seq Sequence, start int, modulus uint64) (sequenceState, error) {
+	switch {
+	case isFibonacci(seq) && modulus != 0:
+		return newModFibStateAt(ctx, start, modulus)
+	case isFibonacci(seq):
+		return newFibStateAt(ctx, start)
+	case modulus != 0:
+		return newModRecurrenceStateAt(ctx, seq, start, modulus)
+	default:
+		return newRecurrenceStateAt(ctx, seq, start)
+	}
+}
+
+// restoreState returns the state that save wrote for seq and modulus.
+func restoreState(seq Sequence, modulus uint64, saved []byte) (sequenceState, error) {
+	r := &tokenReader{data: saved}
+
+	var state sequenceState
+	switch {
+	case isFibonacci(seq) && modulus != 0:
+		state = restoreModFibState(r, modulus)
+	case isFibonacci(seq):
+		state = restoreFibState(r)
+	case modulus != 0:
+		state = restoreModRecurrenceState(r, seq, modulus)
+	default:
+		state = restoreRecurrenceState(r, seq)
+	}
+
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// fibState holds two consecutive Fibonacci terms.
+// Advancing reuses the limb storage of both terms, and a single byte buffer is
+// reused for decimal conversion, so the only per-term allocation is the
+// emitted string itself.
+type fibState struct {
+	curr decimal // F(i)
+	next decimal // F(i+1)
+	buf  []byte  // Reused decimal conversion buffer
+}
+
+func newFibState() *fibState {
+	s := &fibState{}
+	s.next.setUint64(1)
+
+	return s
+}
+
+// newFibStateAt returns a state positioned at F(start), seeded with fast doubling.
+func newFibStateAt(ctx context.Context, start int) (*fibState, error) {
+	if start == 0 {
+		return newFibState(), nil
+	}
+
+	curr, next, err := fibPair(ctx, start)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &fibState{}
+	s.curr.setBig(curr)
+	s.next.setBig(next)
+
+	return s, nil
+}
+
+func restoreFibState(r *tokenReader) *fibState {
+	s := &fibState{}
+	s.curr.readBinary(r)
+	s.next.readBinary(r)
+
+	return s
+}
+
+// advance moves the state from (F(i), F(i+1)) to (F(i+1), F(i+2)) in place.
+func (s *fibState) advance() {
+	s.curr.add(&s.curr, &s.next)
+	s.curr, s.next = s.next, s.curr
+}
+
+// text returns the decimal representation of the current term.
+func (s *fibState) text() string {
+	s.buf = s.curr.append(s.buf[:0])
+
+	return string(s.buf)
+}
+
+func (s *fibState) save(buf []byte) []byte {
+	buf = s.curr.appendBinary(buf)
+
+	return s.next.appendBinary(buf)
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"fibonacci/internal/domain"
+)
+
+// A table file holds the decimal digits of F(0)..F(count-1), all integers being little-endian:
+//
+//	header   magic "FIBTABLE", version uint32, reserved uint32, count uint64, data size uint64,
+//	         SHA-256 of the data followed by the offsets
+//	data     digits of every term back to back
+//	offsets  count+1 uint64 offsets of the terms into the data, the last one being the data size
+//
+// Terms are stored as text, so serving them is a copy rather than a conversion.
+const (
+	tableMagic      = "FIBTABLE"
+	tableVersion    = 1
+	tableHeaderSize = 64
+)
+
+// Table is a precomputed table of the Fibonacci terms F(0)..F(n-1) mapped into memory, see OpenTable.
+type Table struct {
+	mapped  []byte // The whole file
+	data    []byte
+	offsets []byte
+	count   int
+}
+
+// WithTable makes the service read the Fibonacci terms held by t instead of computing them.
+// Ranges reaching beyond the table continue with computed terms.
+func WithTable(t *Table) Option {
+	return func(s *fibonacciService) {
+		s.table = t
+	}
+}
+
+// OpenTable maps the table file at path into memory after checking its structure and checksum.
+// Files larger than maxBytes are rejected.
+func OpenTable(path string, maxBytes int64) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() > maxBytes {
+		return nil, fmt.Errorf("%w: %s has %d bytes, must not exceed %d", domain.ErrInvalidTable, path, info.Size(), maxBytes)
+	}
+
+	if info.Size() < tableHeaderSize {
+		return nil, fmt.Errorf("%w: %s is truncated", domain.ErrInvalidTable, path)
+	}
+
+	mapped, err := mapFile(f, int(info.Size()))
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := parseTable(mapped)
+	if err != nil {
+		_ = unmapFile(mapped)
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return t, nil
+}
+
+func parseTable(b []byte) (*Table, error) {
+	if string(b[:8]) != tableMagic {
+		return nil, fmt.Errorf("%w: not a table file", domain.ErrInvalidTable)
+	}
+
+	if v := binary.LittleEndian.Uint32(b[8:]); v != tableVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", domain.ErrInvalidTable, v)
+	}
+
+	count := binary.LittleEndian.Uint64(b[16:])
+	size := binary.LittleEndian.Uint64(b[24:])
+	sum := b[32:tableHeaderSize]
+
+	// Both are bounded by the file size before they are multiplied or added, so nothing overflows.
+	rest := uint64(len(b) - tableHeaderSize)
+	if count < 2 || count >= rest/8 || size != rest-(count+1)*8 {
+		return nil, fmt.Errorf("%w: sizes do not match the file", domain.ErrInvalidTable)
+	}
+
+	t := &Table{
+		mapped:  b,
+		data:    b[tableHeaderSize : tableHeaderSize+size],
+		offsets: b[tableHeaderSize+size:],
+		count:   int(count),
+	}
+
+	h := sha256.New()
+	h.Write(t.data)
+	h.Write(t.offsets)
+
+	if !bytes.Equal(h.Sum(nil), sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", domain.ErrInvalidTable)
+	}
+
+	// Every term has at least one digit, which also keeps the offsets within the data.
+	for i := range t.count {
+		if t.offset(i) >= t.offset(i+1) {
+			return nil, fmt.Errorf("%w: invalid offset of term %d", domain.ErrInvalidTable, i+1)
+		}
+	}
+
+	if t.offset(0) != 0 || t.offset(t.count) != size {
+		return nil, fmt.Errorf("%w: offsets do not cover the data", domain.ErrInvalidTable)
+	}
+
+	return t, nil
+}
+
+// Len returns the number of terms in the table.
+func (t *Table) Len() int {
+	return t.count
+}
+
+// Close unmaps the table. It must not be used by the service afterwards.
+func (t *Table) Close() error {
+	return unmapFile(t.mapped)
+}
+
+// Verify checks every term of the table against its computed value.
+func (t *Table) Verify(ctx context.Context) error {
+	var (
+		state = newFibState()
+		buf   []byte
+	)
+
+	for i := range t.count {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		buf = state.curr.append(buf[:0])
+		if !bytes.Equal(buf, t.digits(i)) {
+			return fmt.Errorf("%w: term %d is not F(%d)", domain.ErrInvalidTable, i, i)
+		}
+
+		state.advance()
+	}
+
+	return nil
+}
+
+// holds reports whether the table holds a(n) of seq reduced modulo modulus. A nil table holds nothing.
+func (t *Table) holds(seq Sequence, modulus uint64, n int) bool {
+	return t != nil && isFibonacci(seq) && modulus == 0 && n >= 0 && n < t.count
+}
+
+// term returns the decimal representation of F(i).
+func (t *Table) term(i int) string {
+	return string(t.digits(i))
+}
+
+func (t *Table) digits(i int) []byte {
+	return t.data[t.offset(i):t.offset(i+1)]
+}
+
+func (t *Table) offset(i int) uint64 {
+	return binary.LittleEndian.Uint64(t.offsets[8*i:])
+}
+
+// fibState returns a state at F(i), parsed from the table if it holds the pair or advanced from its last one.
+func (t *Table) fibState(i int) *fibState {
+	j := min(i, t.count-2)
+
+	s := &fibState{}
+	s.curr.setText(t.term(j))
+	s.next.setText(t.term(j + 1))
+
+	for ; j < i; j++ {
+		s.advance()
+	}
+
+	return s
+}
+
+// BuildTable writes the table of the terms F(0)..F(n-1) to path, replacing the file only once it is complete.
+func BuildTable(ctx context.Context, path string, n int) error {
+	if n < 2 {
+		return fmt.Errorf("%w: must hold at least 2 terms", domain.ErrInvalidTable)
+	}
+
+	tmp := path + ".tmp"
+
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	err = writeTable(ctx, f, n)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// writeTable writes the data and offsets after the header, which is written last as it holds their checksum.
+func writeTable(ctx context.Context, f *os.File, n int) error {
+	if _, err := f.Seek(tableHeaderSize, io.SeekStart); err != nil {
+		return err
+	}
+
+	var (
+		h       = sha256.New()
+		w       = bufio.NewWriter(io.MultiWriter(f, h))
+		offsets = make([]byte, 0, (n+1)*8)
+		size    = uint64(0)
+		state   = newFibState()
+	)
+
+	for range n {
+		if err := contextError(ctx); err != nil {
+			return err
+		}
+
+		offsets = binary.LittleEndian.AppendUint64(offsets, size)
+
+		state.buf = state.curr.append(state.buf[:0])
+		if _, err := w.Write(state.buf); err != nil {
+			return err
+		}
+
+		size += uint64(len(state.buf))
+		state.advance()
+	}
+
+	offsets = binary.LittleEndian.AppendUint64(offsets, size)
+	if _, err := w.Write(offsets); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	header := make([]byte, 0, tableHeaderSize)
+	header = append(header, tableMagic...)
+	header = binary.LittleEndian.AppendUint32(header, tableVersion)
+	header = binary.LittleEndian.AppendUint32(header, 0)
+	header = binary.LittleEndian.AppendUint64(header, uint64(n))
+	header = binary.LittleEndian.AppendUint64(header, size)
+	header = h.Sum(header)
+
+	_, err := f.WriteAt(header, 0)
+
+	return err
+}
+
+// tableState reads Fibonacci terms from a table, and computes them once it runs past its end.
+type tableState struct {
+	t     *Table
+	index int       // Index of the current term while it is in the table
+	fib   *fibState // Computes the terms past the end of the table, nil until it is reached
+}
+
+func (s *tableState) text() string {
+	if s.fib != nil {
+		return s.fib.text()
+	}
+
+	return s.t.term(s.index)
+}
+
+func (s *tableState) advance() {
+	if s.fib != nil {
+		s.fib.advance()
+		return
+	}
+
+	if s.index++; s.index == s.t.count {
+		s.fib = s.t.fibState(s.index)
+	}
+}
+
+func (s *tableState) save(buf []byte) []byte {
+	if s.fib != nil {
+		return s.fib.save(buf)
+	}
+
+	return s.t.fibState(s.index).save(buf)
+}
@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "table.bin")
+	assert.NoError(t, service.BuildTable(context.Background(), path, 500))
+
+	table, err := service.OpenTable(path, 1<<20)
+	assert.NoError(t, err)
+	defer table.Close()
+
+	assert.Equal(t, 500, table.Len())
+	assert.NoError(t, table.Verify(context.Background()))
+
+	secret := service.WithTokenSecret([]byte("secret"))
+	plain := service.NewService(50, 1, 5000, 5000, 1000, secret)
+	s := service.NewService(50, 1, 5000, 5000, 1000, secret, service.WithTable(table))
+
+	t.Run("serves ranges from the table and beyond", func(t *testing.T) {
+		for _, req := range []domain.FibonacciRequest{
+			{End: 10},
+			{Start: 480, End: 500},
+			{Start: 490, End: 530},
+			{Start: 600, End: 620},
+			{Start: -20, End: 20},
+			{Start: 10, End: 20, Modulus: 7},
+		} {
+			want, err := plain.GetFibonacci(context.Background(), req)
+			assert.NoError(t, err)
+
+			got, err := s.GetFibonacci(context.Background(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "%+v", req)
+		}
+
+		for _, n := range []int{0, 499, 500, -3} {
+			want, err := plain.GetNth(context.Background(), domain.FibonacciNthRequest{N: n})
+			assert.NoError(t, err)
+
+			got, err := s.GetNth(context.Background(), domain.FibonacciNthRequest{N: n})
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, "n %d", n)
+		}
+	})
+
+	t.Run("streams resume across the end of the table", func(t *testing.T) {
+		want, err := plain.GetFibonacci(context.Background(), domain.FibonacciRequest{Start: 470, End: 530})
+		assert.NoError(t, err)
+
+		var values, tokens []string
+		err = s.GetFibonacciStream(context.Background(), domain.FibonacciStreamRequest{
+			Start:     470,
+			End:       530,
+			ChunkSize: 7,
+			SendFunc: func(chunk domain.FibonacciChunk) error {
+				values = append(values, chunk.Values...)
+				tokens = append(tokens, chunk.ContinuationToken)
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, want, values)
+
+		// Tokens issued while reading from the table resume the stream on a service without it.
+		var resumed []string
+		err = plain.GetFibonacciStream(context.Background(), domain.FibonacciStreamRequest{
+			ResumeToken: tokens[2],
+			ChunkSize:   7,
+			SendFunc: func(chunk domain.FibonacciChunk) error {
+				resumed = append(resumed, chunk.Values...)
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, want[21:], resumed)
+	})
+
+	t.Run("rejects invalid files", func(t *testing.T) {
+		_, err := service.OpenTable(path, 1000)
+		assert.ErrorIs(t, err, domain.ErrInvalidTable)
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+
+		corrupt := filepath.Join(t.TempDir(), "corrupt.bin")
+		data[100] ^= 1
+		assert.NoError(t, os.WriteFile(corrupt, data, 0o644))
+
+		_, err = service.OpenTable(corrupt, 1<<20)
+		assert.ErrorIs(t, err, domain.ErrInvalidTable)
+
+		truncated := filepath.Join(t.TempDir(), "truncated.bin")
+		assert.NoError(t, os.WriteFile(truncated, data[:len(data)-8], 0o644))
+
+		_, err = service.OpenTable(truncated, 1<<20)
+		assert.ErrorIs(t, err, domain.ErrInvalidTable)
+
+		assert.ErrorIs(t, service.BuildTable(context.Background(), corrupt, 1), domain.ErrInvalidTable)
+	})
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"fibonacci/internal/domain"
+)
+
+// tokenVersion identifies the token layout, so tokens issued by an incompatible release are rejected.
+const tokenVersion = 1
+
+// errTruncated reports a token payload that ends before all of its fields.
+var errTruncated = errors.New("truncated payload")
+
+// resumePoint is everything needed to continue a stream: the range and sequence it was started with,
+// the position of the next term and the saved state of the sequence at that position.
+type resumePoint struct {
+	spec     domain.SequenceSpec
+	position int
+	end      int
+	modulus  uint64
+	state    []byte // Written by sequenceState.save
+}
+
+// tokenSigner issues and verifies continuation tokens.
+//
+// A token is the base64url encoding of a binary resumePoint followed by its HMAC-SHA256, so clients
+// can carry it around but cannot make the server resume from a forged or modified state.
+type tokenSigner struct {
+	secret []byte
+}
+
+// newTokenSigner returns a signer using secret, or a random secret when it is empty.
+func newTokenSigner(secret []byte) tokenSigner {
+	if len(secret) == 0 {
+		secret = make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			panic(err)
+		}
+	}
+
+	return tokenSigner{secret: secret}
+}
+
+func (t tokenSigner) encode(p resumePoint) string {
+	payload := []byte{tokenVersion}
+	payload = binary.AppendVarint(payload, int64(p.position))
+	payload = binary.AppendVarint(payload, int64(p.end))
+	payload = binary.AppendUvarint(payload, p.modulus)
+
+	payload = appendString(payload, p.spec.Name)
+	payload = binary.AppendVarint(payload, int64(p.spec.K))
+	payload = binary.AppendUvarint(payload, uint64(len(p.spec.Seeds)))
+	for _, seed := range p.spec.Seeds {
+		payload = appendString(payload, seed)
+	}
+
+	payload = binary.AppendUvarint(payload, uint64(len(p.spec.Coefficients)))
+	for _, c := range p.spec.Coefficients {
+		payload = binary.AppendVarint(payload, c)
+	}
+
+	payload = append(payload, p.state...)
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, t.mac(payload)...))
+}
+
+func (t tokenSigner) decode(token string) (resumePoint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) < 1+sha256.Size {
+		return resumePoint{}, fmt.Errorf("%w: malformed", domain.ErrInvalidToken)
+	}
+
+	payload, mac := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if !hmac.Equal(mac, t.mac(payload)) {
+		return resumePoint{}, fmt.Errorf("%w: signature mismatch", domain.ErrInvalidToken)
+	}
+
+	if payload[0] != tokenVersion {
+		return resumePoint{}, fmt.Errorf("%w: unsupported version %d", domain.ErrInvalidToken, payload[0])
+	}
+
+	r := &tokenReader{data: payload[1:]}
+
+	p := resumePoint{
+		position: int(r.varint()),
+		end:      int(r.varint()),
+		modulus:  r.uvarint(),
+	}
+
+	p.spec.Name = r.string()
+	p.spec.K = int(r.varint())
+
+	if n := r.count(); n > 0 {
+		p.spec.Seeds = make([]string, n)
+		for i := range p.spec.Seeds {
+			p.spec.Seeds[i] = r.string()
+		}
+	}
+
+	if n := r.count(); n > 0 {
+		p.spec.Coefficients = make([]int64, n)
+		for i := range p.spec.Coefficients {
+			p.spec.Coefficients[i] = r.varint()
+		}
+	}
+
+	p.state = r.data
+
+	if r.err != nil {
+		return resumePoint{}, fmt.Errorf("%w: %w", domain.ErrInvalidToken, r.err)
+	}
+
+	return p, nil
+}
+
+func (t tokenSigner) mac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+
+	return append(buf, s...)
+}
+
+// tokenReader decodes the fields of a token payload, remembering the first error
+// so that callers can check it once after reading every field.
+type tokenReader struct {
+	data []byte
+	err  error
+}
+
+func (r *tokenReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errTruncated
+		return 0
+	}
+
+	r.data = r.data[n:]
+
+	return v
+}
+
+func (r *tokenReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errTruncated
+		return 0
+	}
+
+	r.data = r.data[n:]
+
+	return v
+}
+
+// uint64 reads a fixed-size little-endian value.
+func (r *tokenReader) uint64() uint64 {
+	if r.err != nil {
+		return 0
+	}
+
+	if len(r.data) < 8 {
+		r.err = errTruncated
+		return 0
+	}
+
+	v := binary.LittleEndian.Uint64(r.data)
+	r.data = r.data[8:]
+
+	return v
+}
+
+// count reads a number of elements that follow, each taking at least one byte.
+func (r *tokenReader) count() int {
+	n := r.uvarint()
+	if n > uint64(len(r.data)) {
+		r.fail(errTruncated)
+		return 0
+	}
+
+	return int(n)
+}
+
+func (r *tokenReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+
+	s := string(r.data[:n])
+	r.data = r.data[n:]
+
+	return s
+}
+
+// fail records err unless an earlier error has been recorded.
+func (r *tokenReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// done fails unless all of the data has been read.
+func (r *tokenReader) done() error {
+	if r.err == nil && len(r.data) > 0 {
+		r.err = errors.New("trailing data")
+	}
+
+	return r.err
+}
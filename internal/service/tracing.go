@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"fibonacci/internal/domain"
+	"fibonacci/internal/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer traces the computations of the service with the global tracer provider, see tracing.Setup.
+var tracer = otel.Tracer("fibonacci/internal/service")
+
+// Attributes of the service spans.
+const (
+	attrSequence    = attribute.Key("fibonacci.sequence")
+	attrStart       = attribute.Key("fibonacci.start")
+	attrEnd         = attribute.Key("fibonacci.end")
+	attrN           = attribute.Key("fibonacci.n")
+	attrModulus     = attribute.Key("fibonacci.modulus")
+	attrChunkSize   = attribute.Key("fibonacci.chunk_size")
+	attrDigits      = attribute.Key("fibonacci.digits")
+	attrChunkNumber = attribute.Key("fibonacci.chunk.number") // Ordinal of the chunk in its stream
+	attrChunkIndex  = attribute.Key("fibonacci.chunk.index")  // Absolute index of the first term of the chunk
+	attrChunkTerms  = attribute.Key("fibonacci.chunk.terms")
+	attrCoalesced   = attribute.Key("fibonacci.chunk.coalesced") // The chunk was computed for several streams
+	attrItems       = attribute.Key("fibonacci.batch.items")
+	attrTerms       = attribute.Key("fibonacci.batch.terms") // Distinct terms computed for a batch
+)
+
+// rangeAttributes describes the range of a span.
+func rangeAttributes(r sequenceRange) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attrSequence.String(sequenceName(r.spec)),
+		attrStart.Int(r.start),
+		attrEnd.Int(r.end),
+		attrModulus.Int64(int64(r.modulus)),
+	}
+}
+
+// sequenceName names the sequence of a request in spans.
+func sequenceName(spec domain.SequenceSpec) string {
+	if spec.Name == "" {
+		return SequenceFibonacci
+	}
+
+	return spec.Name
+}
+
+// endSpan ends span, marking it as failed by err unless it is nil. Cancellation is recorded, but not as a failure.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+
+		if !errors.Is(err, domain.ErrContextCanceled) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+
+	span.End()
+}
+
+// sendChunk produces the chunk with the given ordinal from c and passes it to send. The chunk span records
+// its position and the digits produced, and has a compute and a send span telling the time spent on either apart.
+func sendChunk(ctx context.Context, c *chunkCursor, number, chunkSize int, send func(domain.FibonacciChunk) error) error {
+	ctx, span := tracer.Start(ctx, "chunk", trace.WithAttributes(attrChunkNumber.Int(number)))
+
+	return sendTraced(ctx, span, computeChunk(ctx, c, chunkSize), send)
+}
+
+// sendSharedChunk passes the chunk with the given ordinal of a coalesced stream to send. Its compute span
+// belongs to the stream that started the computation, see broadcast.produce.
+func sendSharedChunk(ctx context.Context, number int, chunk domain.FibonacciChunk, send func(domain.FibonacciChunk) error) error {
+	ctx, span := tracer.Start(ctx, "chunk", trace.WithAttributes(attrChunkNumber.Int(number), attrCoalesced.Bool(true)))
+
+	return sendTraced(ctx, span, chunk, send)
+}
+
+// computeChunk produces the next chunk of c in a compute span.
+func computeChunk(ctx context.Context, c *chunkCursor, chunkSize int) domain.FibonacciChunk {
+	_, span := tracer.Start(ctx, "compute")
+	defer span.End()
+
+	return c.next(chunkSize)
+}
+
+// sendTraced records chunk on its span and passes it to send in a send span, then ends the chunk span.
+func sendTraced(ctx context.Context, span trace.Span, chunk domain.FibonacciChunk, send func(domain.FibonacciChunk) error) (err error) {
+	defer func() { endSpan(span, err) }()
+
+	if span.IsRecording() {
+		span.SetAttributes(attrChunkIndex.Int(chunk.Index), attrChunkTerms.Int(len(chunk.Values)), attrDigits.Int64(valuesDigits(chunk.Values)))
+	}
+
+	_, sending := tracer.Start(ctx, "send")
+	start := time.Now()
+	err = send(chunk)
+	metrics.ChunkSendDuration.WithLabelValues().Observe(time.Since(start).Seconds())
+	endSpan(sending, err)
+
+	return err
+}
+
+// valuesDigits returns the number of decimal digits of values, without signs.
+func valuesDigits(values []string) int64 {
+	var digits int64
+	for _, v := range values {
+		digits += int64(len(strings.TrimPrefix(v, "-")))
+	}
+
+	return digits
+}
@@ -0,0 +1,103 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Exporters spans can be sent with.
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp" // OTLP over gRPC, usually to a local collector
+	ExporterFile = "file" // One JSON object per span and line, meant for tests and debugging
+)
+
+// Config selects where spans are exported to.
+type Config struct {
+	Exporter    string  // ExporterNone, ExporterOTLP or ExporterFile; none if empty
+	Endpoint    string  // host:port of the OTLP collector
+	Insecure    bool    // Send to the collector w/o TLS
+	File        string  // Path of the file spans are appended to
+	ServiceName string  // Names the process in the traces
+	SampleRatio float64 // Share of the traces started here that are recorded; incoming calls follow the caller's choice
+}
+
+// Setup installs the global tracer provider exporting spans as cfg selects, and propagates the W3C trace context
+// and baggage of incoming calls. Spans are dropped when no exporter is selected, but the context is still propagated.
+//
+// It must be called at most once, before the first span is started, as tracers keep the first provider installed.
+// The returned function flushes the pending spans and stops exporting.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		closers  []func() error
+		err      error
+	)
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		// The client connects lazily, so a collector that is not up yet does not fail the startup.
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+
+		closers = append(closers, f.Close)
+
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q, expected %s, %s or %s", cfg.Exporter, ExporterNone, ExporterOTLP, ExporterFile)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		for _, c := range closers {
+			err = errors.Join(err, c())
+		}
+
+		return err
+	}, nil
+}